@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"ongi-back/config"
 	"ongi-back/database"
@@ -22,13 +23,43 @@ func main() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Run migrations
-	if err := database.Migrate(); err != nil {
-		log.Fatal("Failed to migrate database:", err)
+	// Refuse to start against an outdated schema (run cmd/migrate first)
+	if err := database.CheckSchemaUpToDate(); err != nil {
+		log.Fatal("Database schema check failed:", err)
 	}
 
-	// Initialize WebSocket Hub
-	services.InitHub()
+	// Load profile type / description rules
+	if err := services.InitProfileRules(config.AppConfig.ProfileRulesFile); err != nil {
+		log.Fatal("Failed to load profile rules:", err)
+	}
+
+	// Validate similarity metric settings
+	if err := services.InitSimilarityMetrics(); err != nil {
+		log.Fatal("Invalid similarity metric configuration:", err)
+	}
+
+	// Validate recommendation strategy and load the running A/B experiment
+	if err := services.InitExperiments(); err != nil {
+		log.Fatal("Invalid recommendation strategy configuration:", err)
+	}
+
+	// Build in-memory vector index for similar-profile search
+	if err := services.InitVectorIndex(); err != nil {
+		log.Fatal("Failed to build vector index:", err)
+	}
+
+	// Register token revocation list for JWT validation
+	services.InitTokenRevocation()
+
+	// Initialize WebSocket Hub and subscribe to the cross-instance broker (HUB_BROKER)
+	if err := services.InitHub(context.Background()); err != nil {
+		log.Fatal("Failed to initialize WebSocket hub:", err)
+	}
+
+	// Register background jobs and start the scheduler (JOBS_ENABLED)
+	if err := services.InitJobs(context.Background()); err != nil {
+		log.Fatal("Failed to initialize background jobs:", err)
+	}
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"ongi-back/config"
+	"ongi-back/database"
+	"ongi-back/services"
+)
+
+const usage = `Usage: evaluate [flags]
+
+과거 클럽 가입을 다시 재생해 등록된 클럽 추천 전략을 비교합니다.
+회원마다 가입 전에 응답한 세션 벡터(없으면 프로필 벡터)로 추천을 만들고,
+실제로 가입한 클럽을 정답으로 precision@K, recall@K, NDCG, coverage를 계산합니다.
+
+Flags:`
+
+func main() {
+	k := flag.Int("k", services.DefaultEvaluationK, "상위 K개 추천으로 평가")
+	strategies := flag.String("strategies", "", "평가할 전략 (쉼표 구분, 비우면 등록된 전략 전체)")
+	since := flag.String("since", "", "이 날짜(YYYY-MM-DD) 이후의 가입만 정답으로 사용 (이전 가입 클럽은 후보에서 제외)")
+	maxUsers := flag.Int("max-users", 0, "평가할 최대 회원 수 (0이면 전체)")
+	format := flag.String("format", "table", "출력 형식 (table, json)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *format != "table" && *format != "json" {
+		log.Fatalf("Invalid format: %s (table, json)", *format)
+	}
+
+	opts := services.EvaluationOptions{K: *k, MaxUsers: *maxUsers}
+	for _, name := range strings.Split(*strategies, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.Strategies = append(opts.Strategies, name)
+		}
+	}
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			log.Fatalf("Invalid since date: %s", *since)
+		}
+		opts.Since = t
+	}
+	opts.Progress = func(done, total int) {
+		if done%100 == 0 || done == total {
+			log.Printf("Evaluated %d/%d users", done, total)
+		}
+	}
+
+	// Load configuration
+	config.Load()
+
+	// Connect to database
+	if err := database.Connect(); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Similar-respondent strategies search the vector index
+	if err := services.InitSimilarityMetrics(); err != nil {
+		log.Fatal("Invalid similarity metric configuration:", err)
+	}
+	if err := services.InitVectorIndex(); err != nil {
+		log.Fatal("Failed to build vector index:", err)
+	}
+
+	report, err := services.EvaluateStrategies(opts)
+	if err != nil {
+		log.Fatal("Evaluation failed:", err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatal("Failed to write report:", err)
+		}
+		return
+	}
+	printReport(report)
+}
+
+func printReport(report *services.EvaluationReport) {
+	fmt.Printf("%d users (%d from sessions, %d from profiles, %d skipped), %d joins, %d clubs\n\n",
+		report.Users, report.FromSessions, report.FromProfiles, report.Skipped, report.Joins, report.Clubs)
+
+	k := fmt.Sprint(report.K)
+	fmt.Printf("%-20s %-12s %-12s %-10s %-10s %s\n", "STRATEGY", "PRECISION@"+k, "RECALL@"+k, "NDCG@"+k, "HIT@"+k, "COVERAGE")
+	for _, s := range report.Strategies {
+		fmt.Printf("%-20s %-12.4f %-12.4f %-10.4f %-10.4f %.4f\n", s.Strategy, s.PrecisionAtK, s.RecallAtK, s.NDCG, s.HitRate, s.Coverage)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"ongi-back/config"
+	"ongi-back/database"
+)
+
+const usage = `Usage: migrate <command> [N]
+
+Commands:
+  up [N]     적용되지 않은 마이그레이션 적용 (N 생략 시 전부)
+  down [N]   최근 마이그레이션 N개 롤백 (기본 1)
+  status     마이그레이션 적용 상태 출력`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	steps := 0
+	if len(os.Args) > 2 {
+		n, err := strconv.Atoi(os.Args[2])
+		if err != nil || n < 0 {
+			log.Fatalf("Invalid step count: %s", os.Args[2])
+		}
+		steps = n
+	}
+
+	// Load configuration
+	config.Load()
+
+	// Connect to database
+	if err := database.Connect(); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := database.MigrateUp(steps)
+		if err != nil {
+			log.Fatal("Migration failed:", err)
+		}
+		log.Printf("%d migration(s) applied", applied)
+
+	case "down":
+		rolledBack, err := database.MigrateDown(steps)
+		if err != nil {
+			log.Fatal("Rollback failed:", err)
+		}
+		log.Printf("%d migration(s) rolled back", rolledBack)
+
+	case "status":
+		statuses, err := database.GetMigrationStatus()
+		if err != nil {
+			log.Fatal("Failed to read migration status:", err)
+		}
+		printStatus(statuses)
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
+
+func printStatus(statuses []database.MigrationStatus) {
+	fmt.Printf("%-8s %-40s %s\n", "VERSION", "NAME", "APPLIED AT")
+	pending := 0
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		} else {
+			pending++
+		}
+		fmt.Printf("%04d     %-40s %s\n", status.Version, status.Name, appliedAt)
+	}
+	fmt.Printf("\n%d total, %d pending\n", len(statuses), pending)
+}
@@ -12,6 +12,18 @@ type Config struct {
 	DatabaseURL   string
 	JWTSecret     string
 	Environment   string
+	ProfileRulesFile string // 프로필 규칙 YAML 파일 (비어 있으면 DB 또는 내장 기본 규칙)
+	SimilarityMetric    string // 유사 프로필 검색 기본 척도 (cosine, euclidean, mahalanobis, weighted)
+	CompatibilityMetric string // 궁합 계산 기본 척도
+	SimilarityWeights   string // weighted 척도의 차원별 가중치 (예: "sociality:1.5,intimacy:2")
+	JobsEnabled         bool   // 이 인스턴스에서 백그라운드 작업 실행
+	JobCleanupSchedule  string // 만료 세션/토큰 정리 (cron, off면 수동 실행만)
+	JobRematchSchedule  string // 전체 사용자 재매칭
+	JobReindexSchedule  string // 벡터 인덱스 재구성 (인스턴스마다)
+	RecommendationStrategy string // 실험에 배정되지 않은 응답자의 클럽 추천 전략 (content, feedback, similar_members)
+	HubBroker  string // WebSocket 이벤트를 인스턴스 간에 전달할 브로커 (postgres, redis, memory)
+	HubChannel string // 브로커 채널 이름 (LISTEN/NOTIFY 채널, Redis 채널)
+	RedisURL   string // HUB_BROKER=redis일 때 연결 주소 (redis://[user:password@]host:port)
 }
 
 var AppConfig *Config
@@ -27,6 +39,18 @@ func Load() {
 		DatabaseURL: getEnv("DATABASE_URL", ""),
 		JWTSecret:   getEnv("JWT_SECRET", "your-secret-key"),
 		Environment: getEnv("ENVIRONMENT", "development"),
+		ProfileRulesFile: getEnv("PROFILE_RULES_FILE", ""),
+		SimilarityMetric:    getEnv("SIMILARITY_METRIC", "cosine"),
+		CompatibilityMetric: getEnv("COMPATIBILITY_METRIC", "cosine"),
+		SimilarityWeights:   getEnv("SIMILARITY_WEIGHTS", ""),
+		JobsEnabled:         getEnv("JOBS_ENABLED", "true") == "true",
+		JobCleanupSchedule:  getEnv("JOB_CLEANUP_SCHEDULE", "0 * * * *"),
+		JobRematchSchedule:  getEnv("JOB_REMATCH_SCHEDULE", "off"),
+		JobReindexSchedule:  getEnv("JOB_REINDEX_SCHEDULE", "*/30 * * * *"),
+		RecommendationStrategy: getEnv("RECOMMENDATION_STRATEGY", "feedback"),
+		HubBroker:  getEnv("HUB_BROKER", "postgres"),
+		HubChannel: getEnv("HUB_CHANNEL", "ongi_hub"),
+		RedisURL:   getEnv("REDIS_URL", ""),
 	}
 
 	log.Println("Configuration loaded")
@@ -5,8 +5,6 @@ import (
 	"log"
 	"os"
 
-	"ongi-back/models"
-
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -51,34 +49,6 @@ func Connect() error {
 	return nil
 }
 
-func Migrate() error {
-	log.Println("Running database migrations...")
-
-	err := DB.AutoMigrate(
-		&models.User{},
-		&models.UserProfile{},
-		&models.Question{},
-		&models.Option{},
-		&models.UserAnswer{},
-		&models.Club{},
-		&models.ClubMember{},
-		&models.Meeting{},
-		&models.GuestSession{},
-		&models.GuestAnswer{},
-		&models.SessionVector{},
-		&models.ChatRoom{},
-		&models.ChatRoomMember{},
-		&models.ChatMessage{},
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	log.Println("Database migration completed")
-	return nil
-}
-
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,308 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// 마이그레이션 파일 이름 형식: 0001_name.up.sql / 0001_name.down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// 여러 인스턴스가 동시에 마이그레이션하지 않도록 사용하는 advisory lock 키
+const migrationLockKey int64 = 7262810514
+
+// 다른 인스턴스가 먼저 적용/롤백한 경우
+var errMigrationSkipped = fmt.Errorf("migration already handled by another process")
+
+// Migration 버전 관리되는 SQL 마이그레이션
+type Migration struct {
+	Version int64
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+// MigrationStatus 마이그레이션 적용 상태
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// SchemaMigration schema_migrations 테이블 레코드
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// LoadMigrations 내장된 마이그레이션 파일을 버전 순으로 읽기
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names: %s, %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.UpSQL) == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrate 적용되지 않은 모든 마이그레이션 적용
+func Migrate() error {
+	log.Println("Running database migrations...")
+
+	applied, err := MigrateUp(0)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	log.Printf("Database migration completed (%d applied)", applied)
+	return nil
+}
+
+// MigrateUp 적용되지 않은 마이그레이션을 최대 steps개 적용 (0이면 전부)
+func MigrateUp(steps int) (int, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	appliedVersions, err := appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range migrations {
+		if _, ok := appliedVersions[migration.Version]; ok {
+			continue
+		}
+		if steps > 0 && count >= steps {
+			break
+		}
+
+		migration := migration
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			applied, err := lockAndCheckApplied(tx, migration.Version)
+			if err != nil {
+				return err
+			}
+			if applied {
+				return errMigrationSkipped
+			}
+
+			if err := tx.Exec(migration.UpSQL).Error; err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err == errMigrationSkipped {
+			continue
+		}
+		if err != nil {
+			return count, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+		count++
+	}
+
+	return count, nil
+}
+
+// MigrateDown 최근에 적용된 마이그레이션을 steps개 되돌리기
+func MigrateDown(steps int) (int, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	appliedVersions, err := appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := migrations[i]
+		if _, ok := appliedVersions[migration.Version]; !ok {
+			continue
+		}
+
+		if strings.TrimSpace(migration.DownSQL) == "" {
+			return count, fmt.Errorf("migration %04d_%s has no down script", migration.Version, migration.Name)
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			applied, err := lockAndCheckApplied(tx, migration.Version)
+			if err != nil {
+				return err
+			}
+			if !applied {
+				return errMigrationSkipped
+			}
+
+			if err := tx.Exec(migration.DownSQL).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, migration.Version).Error
+		})
+		if err == errMigrationSkipped {
+			continue
+		}
+		if err != nil {
+			return count, fmt.Errorf("rollback of %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		log.Printf("Rolled back migration %04d_%s", migration.Version, migration.Name)
+		count++
+	}
+
+	return count, nil
+}
+
+// GetMigrationStatus 모든 마이그레이션의 적용 상태
+func GetMigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	appliedVersions, err := appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := appliedVersions[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// CheckSchemaUpToDate 적용되지 않은 마이그레이션이 있으면 에러 반환
+func CheckSchemaUpToDate() error {
+	statuses, err := GetMigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, fmt.Sprintf("%04d_%s", status.Version, status.Name))
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is behind, pending migrations: %s (run `go run cmd/migrate/main.go up`)", strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// lockAndCheckApplied 트랜잭션 단위 advisory lock을 잡은 뒤 적용 여부를 다시 확인
+func lockAndCheckApplied(tx *gorm.DB, version int64) (bool, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+		return false, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	var count int64
+	if err := tx.Model(&SchemaMigration{}).Where("version = ?", version).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func appliedMigrations() (map[int64]SchemaMigration, error) {
+	if err := DB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var records []SchemaMigration
+	if err := DB.Order("version ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int64]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS chat_messages;
+DROP TABLE IF EXISTS chat_room_members;
+DROP TABLE IF EXISTS chat_rooms;
+DROP TABLE IF EXISTS session_vectors;
+DROP TABLE IF EXISTS guest_answers;
+DROP TABLE IF EXISTS guest_sessions;
+DROP TABLE IF EXISTS meetings;
+DROP TABLE IF EXISTS club_members;
+DROP TABLE IF EXISTS clubs;
+DROP TABLE IF EXISTS user_answers;
+DROP TABLE IF EXISTS options;
+DROP TABLE IF EXISTS questions;
+DROP TABLE IF EXISTS user_profiles;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,169 @@
+-- 초기 스키마 (기존 AutoMigrate로 생성되던 테이블과 동일)
+-- 이미 AutoMigrate로 생성된 데이터베이스에서도 실행할 수 있도록 IF NOT EXISTS 사용
+
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    email      TEXT NOT NULL,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS user_profiles (
+    id                BIGSERIAL PRIMARY KEY,
+    user_id           BIGINT NOT NULL REFERENCES users(id),
+    sociality_score   DECIMAL,
+    activity_score    DECIMAL,
+    intimacy_score    DECIMAL,
+    immersion_score   DECIMAL,
+    flexibility_score DECIMAL,
+    result_summary    TEXT,
+    profile_type      TEXT,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_profiles_user_id ON user_profiles(user_id);
+
+CREATE TABLE IF NOT EXISTS questions (
+    id            BIGSERIAL PRIMARY KEY,
+    question_text TEXT NOT NULL,
+    "order"       BIGINT NOT NULL,
+    category      TEXT,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS options (
+    id          BIGSERIAL PRIMARY KEY,
+    question_id BIGINT NOT NULL REFERENCES questions(id),
+    option_text TEXT NOT NULL,
+    score       BIGINT,
+    weight      TEXT,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS user_answers (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users(id),
+    question_id BIGINT NOT NULL REFERENCES questions(id),
+    option_id   BIGINT NOT NULL REFERENCES options(id),
+    created_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS clubs (
+    id                BIGSERIAL PRIMARY KEY,
+    name              TEXT NOT NULL,
+    description       TEXT,
+    category          TEXT,
+    vibe              TEXT,
+    meeting_frequency TEXT,
+    location          TEXT,
+    image_url         TEXT,
+    member_count      BIGINT,
+    max_members       BIGINT,
+    tags              TEXT,
+    preferred_scores  TEXT,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS club_members (
+    id         BIGSERIAL PRIMARY KEY,
+    club_id    BIGINT NOT NULL REFERENCES clubs(id),
+    user_id    BIGINT NOT NULL REFERENCES users(id),
+    joined_at  TIMESTAMPTZ,
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS meetings (
+    id           BIGSERIAL PRIMARY KEY,
+    title        TEXT NOT NULL,
+    description  TEXT,
+    club_id      BIGINT REFERENCES clubs(id),
+    location     TEXT,
+    scheduled_at TIMESTAMPTZ,
+    max_members  BIGINT,
+    category     TEXT,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS guest_sessions (
+    id                TEXT PRIMARY KEY,
+    sociality_score   DECIMAL,
+    activity_score    DECIMAL,
+    intimacy_score    DECIMAL,
+    immersion_score   DECIMAL,
+    flexibility_score DECIMAL,
+    profile_type      TEXT,
+    result_summary    TEXT,
+    is_linked         BOOLEAN DEFAULT false,
+    linked_user_id    BIGINT,
+    expires_at        TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS guest_answers (
+    id          BIGSERIAL PRIMARY KEY,
+    session_id  TEXT NOT NULL REFERENCES guest_sessions(id),
+    question_id BIGINT NOT NULL REFERENCES questions(id),
+    option_id   BIGINT NOT NULL REFERENCES options(id),
+    created_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS session_vectors (
+    id         BIGSERIAL PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    user_id    BIGINT,
+    vector     JSONB,
+    magnitude  DECIMAL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_session_vectors_session_id ON session_vectors(session_id);
+CREATE INDEX IF NOT EXISTS idx_session_vectors_user_id ON session_vectors(user_id);
+
+CREATE TABLE IF NOT EXISTS chat_rooms (
+    id              BIGSERIAL PRIMARY KEY,
+    name            TEXT NOT NULL,
+    description     TEXT,
+    club_id         BIGINT REFERENCES clubs(id),
+    room_type       TEXT DEFAULT 'group',
+    created_by      BIGINT NOT NULL REFERENCES users(id),
+    member_count    BIGINT DEFAULT 0,
+    last_message    TEXT,
+    last_message_at TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_chat_rooms_club_id ON chat_rooms(club_id);
+
+CREATE TABLE IF NOT EXISTS chat_room_members (
+    id            BIGSERIAL PRIMARY KEY,
+    chat_room_id  BIGINT NOT NULL REFERENCES chat_rooms(id),
+    user_id       BIGINT NOT NULL REFERENCES users(id),
+    role          TEXT DEFAULT 'member',
+    joined_at     TIMESTAMPTZ,
+    last_read_at  TIMESTAMPTZ,
+    unread_count  BIGINT DEFAULT 0,
+    created_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_chat_room_members_chat_room_id ON chat_room_members(chat_room_id);
+CREATE INDEX IF NOT EXISTS idx_chat_room_members_user_id ON chat_room_members(user_id);
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id           BIGSERIAL PRIMARY KEY,
+    chat_room_id BIGINT NOT NULL REFERENCES chat_rooms(id),
+    user_id      BIGINT NOT NULL REFERENCES users(id),
+    message      TEXT NOT NULL,
+    message_type TEXT DEFAULT 'text',
+    file_url     TEXT,
+    is_read      BOOLEAN DEFAULT false,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_chat_room_id ON chat_messages(chat_room_id);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_user_id ON chat_messages(user_id);
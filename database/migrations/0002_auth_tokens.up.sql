@@ -0,0 +1,31 @@
+-- 리프레시 토큰, 액세스 토큰 폐기 목록, 계정 정지
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_banned BOOLEAN DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id),
+    token_hash TEXT NOT NULL,
+    family_id  TEXT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    used_at    TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id            BIGSERIAL PRIMARY KEY,
+    jti           TEXT,
+    user_id       BIGINT NOT NULL,
+    revoke_before TIMESTAMPTZ,
+    reason        TEXT,
+    expires_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_jti ON revoked_tokens(jti);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_user_id ON revoked_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_email;
+DROP TABLE IF EXISTS user_identities;
+-- 이메일 유니크 제약은 중복 데이터가 생겼을 수 있으므로 복구하지 않는다
@@ -0,0 +1,23 @@
+-- 다중 로그인 제공자: provider + subject로 사용자 식별
+-- users.email은 더 이상 로그인 식별자가 아니므로 중복/빈 값을 허용한다.
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL REFERENCES users(id),
+    provider       TEXT NOT NULL,
+    subject        TEXT NOT NULL,
+    email          TEXT,
+    email_verified BOOLEAN DEFAULT false,
+    password_hash  TEXT,
+    last_login_at  TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_identity_provider_subject ON user_identities(provider, subject);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- 이전 버전의 GORM은 users_email_key, 최신 버전은 uni_users_email 이름으로 생성
+ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_email;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
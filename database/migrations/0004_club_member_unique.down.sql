@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_club_members_user_id;
+DROP INDEX IF EXISTS idx_club_members_club_user;
@@ -0,0 +1,13 @@
+-- 같은 사용자가 같은 클럽에 중복 가입되지 않도록 유니크 인덱스 추가
+-- 기존 중복 행은 가장 먼저 생성된 행만 남기고 삭제하고, 클럽 인원 수를 실제 멤버 수로 다시 계산
+
+DELETE FROM club_members a
+USING club_members b
+WHERE a.club_id = b.club_id
+  AND a.user_id = b.user_id
+  AND a.id > b.id;
+
+UPDATE clubs SET member_count = (SELECT COUNT(*) FROM club_members WHERE club_members.club_id = clubs.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_club_members_club_user ON club_members(club_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_club_members_user_id ON club_members(user_id);
@@ -0,0 +1,8 @@
+ALTER TABLE guest_sessions DROP COLUMN IF EXISTS questionnaire_id;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS questionnaire_id;
+ALTER TABLE guest_answers DROP COLUMN IF EXISTS questionnaire_id;
+ALTER TABLE user_answers DROP COLUMN IF EXISTS questionnaire_id;
+DROP INDEX IF EXISTS idx_questions_questionnaire_order;
+DROP INDEX IF EXISTS idx_questions_questionnaire_id;
+ALTER TABLE questions DROP COLUMN IF EXISTS questionnaire_id;
+DROP TABLE IF EXISTS questionnaires;
@@ -0,0 +1,51 @@
+-- 설문 버전 도입
+-- 기존 질문은 발행된 1번 버전으로 묶고, 응답/결과에 버전을 기록한다.
+
+CREATE TABLE IF NOT EXISTS questionnaires (
+    id           BIGSERIAL PRIMARY KEY,
+    version      BIGINT NOT NULL,
+    title        TEXT,
+    status       TEXT NOT NULL DEFAULT 'draft',
+    published_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_questionnaires_version ON questionnaires(version);
+
+INSERT INTO questionnaires (version, title, status, published_at, created_at, updated_at)
+SELECT 1, '온기 성향 설문', 'published', NOW(), NOW(), NOW()
+WHERE EXISTS (SELECT 1 FROM questions)
+  AND NOT EXISTS (SELECT 1 FROM questionnaires);
+
+-- questions
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS questionnaire_id BIGINT REFERENCES questionnaires(id);
+UPDATE questions SET questionnaire_id = (SELECT id FROM questionnaires WHERE version = 1)
+WHERE questionnaire_id IS NULL;
+ALTER TABLE questions ALTER COLUMN questionnaire_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_questions_questionnaire_id ON questions(questionnaire_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_questions_questionnaire_order ON questions(questionnaire_id, "order");
+
+-- user_answers
+ALTER TABLE user_answers ADD COLUMN IF NOT EXISTS questionnaire_id BIGINT REFERENCES questionnaires(id);
+UPDATE user_answers ua SET questionnaire_id = q.questionnaire_id
+FROM questions q
+WHERE ua.question_id = q.id AND ua.questionnaire_id IS NULL;
+ALTER TABLE user_answers ALTER COLUMN questionnaire_id SET NOT NULL;
+
+-- guest_answers
+ALTER TABLE guest_answers ADD COLUMN IF NOT EXISTS questionnaire_id BIGINT REFERENCES questionnaires(id);
+UPDATE guest_answers ga SET questionnaire_id = q.questionnaire_id
+FROM questions q
+WHERE ga.question_id = q.id AND ga.questionnaire_id IS NULL;
+ALTER TABLE guest_answers ALTER COLUMN questionnaire_id SET NOT NULL;
+
+-- 결과에 사용된 버전 (직접 입력한 프로필은 null)
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS questionnaire_id BIGINT REFERENCES questionnaires(id);
+UPDATE user_profiles up SET questionnaire_id = a.questionnaire_id
+FROM (SELECT user_id, MAX(questionnaire_id) AS questionnaire_id FROM user_answers GROUP BY user_id) a
+WHERE up.user_id = a.user_id AND up.questionnaire_id IS NULL;
+
+ALTER TABLE guest_sessions ADD COLUMN IF NOT EXISTS questionnaire_id BIGINT REFERENCES questionnaires(id);
+UPDATE guest_sessions gs SET questionnaire_id = a.questionnaire_id
+FROM (SELECT session_id, MAX(questionnaire_id) AS questionnaire_id FROM guest_answers GROUP BY session_id) a
+WHERE gs.id = a.session_id AND gs.questionnaire_id IS NULL;
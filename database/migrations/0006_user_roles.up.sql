@@ -0,0 +1,4 @@
+-- 관리자 권한 (설문 콘텐츠 관리)
+-- 관리자 지정: UPDATE users SET role = 'admin' WHERE id = ...;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user';
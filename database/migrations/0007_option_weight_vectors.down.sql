@@ -0,0 +1,14 @@
+-- 가중치가 가장 큰 차원을 단일 카테고리로 되돌림 (다차원 가중치 정보는 손실됨)
+
+ALTER TABLE options ADD COLUMN IF NOT EXISTS score BIGINT;
+ALTER TABLE options ADD COLUMN IF NOT EXISTS weight TEXT;
+
+UPDATE options o SET weight = top.key, score = ROUND(top.value::text::numeric)
+FROM (
+    SELECT DISTINCT ON (options.id) options.id, w.key, w.value
+    FROM options, jsonb_each(options.weights) AS w
+    ORDER BY options.id, ABS(w.value::text::numeric) DESC
+) top
+WHERE o.id = top.id;
+
+ALTER TABLE options DROP COLUMN IF EXISTS weights;
@@ -0,0 +1,12 @@
+-- 옵션 가중치를 단일 카테고리 점수(score, weight)에서 차원별 가중치 벡터(weights)로 변경
+-- 기존 옵션은 {weight: score}로 변환한다.
+
+ALTER TABLE options ADD COLUMN IF NOT EXISTS weights JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+UPDATE options SET weights = jsonb_build_object(weight, score)
+WHERE weights = '{}'::jsonb
+  AND weight IN ('sociality', 'activity', 'intimacy', 'immersion', 'flexibility')
+  AND score IS NOT NULL;
+
+ALTER TABLE options DROP COLUMN IF EXISTS score;
+ALTER TABLE options DROP COLUMN IF EXISTS weight;
@@ -0,0 +1,15 @@
+-- 프로필 타입/설명/수준 판정 규칙
+-- 비어 있으면 서버에 내장된 기본 규칙(services/profile_rules.yaml)을 사용한다.
+
+CREATE TABLE IF NOT EXISTS profile_rules (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    rule_group TEXT,
+    label TEXT NOT NULL,
+    priority BIGINT NOT NULL DEFAULT 0,
+    conditions JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_profile_rules_kind ON profile_rules (kind);
@@ -0,0 +1,3 @@
+ALTER TABLE profile_rules DROP COLUMN IF EXISTS translations;
+ALTER TABLE options DROP COLUMN IF EXISTS translations;
+ALTER TABLE questions DROP COLUMN IF EXISTS translations;
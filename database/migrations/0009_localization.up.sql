@@ -0,0 +1,6 @@
+-- 다국어 지원: 질문/옵션/프로필 규칙 텍스트의 언어별 번역문 ({"en": "...", "ja": "..."})
+-- 원문 컬럼은 기본 언어(ko)로 유지한다.
+
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS translations JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE options ADD COLUMN IF NOT EXISTS translations JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE profile_rules ADD COLUMN IF NOT EXISTS translations JSONB NOT NULL DEFAULT '{}'::jsonb;
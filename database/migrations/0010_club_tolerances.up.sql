@@ -0,0 +1,4 @@
+-- 클럽 성향 매칭: 선호 성향 점수(preferred_scores)의 차원별 허용 오차 ({"sociality": 15, ...})
+-- 지정하지 않은 차원은 기본 허용 오차를 사용한다.
+
+ALTER TABLE clubs ADD COLUMN IF NOT EXISTS tolerances TEXT;
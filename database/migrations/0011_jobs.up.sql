@@ -0,0 +1,25 @@
+-- 백그라운드 작업 실행 기록 (세션 정리, 재매칭, 벡터 인덱스 재구성)
+-- instance가 비어 있으면 단일 실행 잠금(advisory lock)을 가진 인스턴스가 실행한다.
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id               BIGSERIAL PRIMARY KEY,
+    name             TEXT NOT NULL,
+    trigger          TEXT NOT NULL,
+    status           TEXT NOT NULL,
+    instance         TEXT NOT NULL DEFAULT '',
+    params           TEXT,
+    result           TEXT,
+    error            TEXT,
+    progress         INTEGER NOT NULL DEFAULT 0,
+    progress_message TEXT,
+    attempt          INTEGER NOT NULL DEFAULT 0,
+    max_attempts     INTEGER NOT NULL DEFAULT 1,
+    run_at           TIMESTAMPTZ NOT NULL,
+    started_at       TIMESTAMPTZ,
+    finished_at      TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_name ON jobs(name);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+CREATE INDEX IF NOT EXISTS idx_jobs_run_at ON jobs(run_at);
@@ -0,0 +1,17 @@
+-- 추천 피드백 이벤트 (노출, 클릭, 관심 없음, 가입)
+-- 회원은 user_id, 비회원은 session_id로 기록하며 계정 연동 시 user_id가 채워진다.
+
+CREATE TABLE IF NOT EXISTS recommendation_events (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT REFERENCES users(id),
+    session_id TEXT,
+    item_type  TEXT NOT NULL,
+    item_id    BIGINT NOT NULL,
+    event      TEXT NOT NULL,
+    source     TEXT,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_recommendation_events_user_id ON recommendation_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_recommendation_events_session_id ON recommendation_events(session_id);
+CREATE INDEX IF NOT EXISTS idx_recommendation_events_item ON recommendation_events(item_type, item_id);
+CREATE INDEX IF NOT EXISTS idx_recommendation_events_created_at ON recommendation_events(created_at);
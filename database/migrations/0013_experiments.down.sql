@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS experiment_exposures;
+DROP TABLE IF EXISTS experiments;
@@ -0,0 +1,31 @@
+-- 클럽 추천 전략 A/B 실험과 노출 기록
+-- 노출은 실험별 응답자(subject)당 한 번만 기록하며, 가입 전환율은 노출 이후의 클럽 가입으로 계산한다.
+
+CREATE TABLE IF NOT EXISTS experiments (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    description TEXT,
+    variants    TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    started_at  TIMESTAMPTZ,
+    stopped_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_experiments_name ON experiments(name);
+CREATE INDEX IF NOT EXISTS idx_experiments_status ON experiments(status);
+-- 실행 중인 실험은 하나만
+CREATE UNIQUE INDEX IF NOT EXISTS idx_experiments_running ON experiments(status) WHERE status = 'running';
+
+CREATE TABLE IF NOT EXISTS experiment_exposures (
+    id            BIGSERIAL PRIMARY KEY,
+    experiment_id BIGINT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    subject       TEXT NOT NULL,
+    variant       TEXT NOT NULL,
+    user_id       BIGINT REFERENCES users(id),
+    session_id    TEXT,
+    created_at    TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_experiment_exposures_subject ON experiment_exposures(experiment_id, subject);
+CREATE INDEX IF NOT EXISTS idx_experiment_exposures_user_id ON experiment_exposures(user_id);
+CREATE INDEX IF NOT EXISTS idx_experiment_exposures_session_id ON experiment_exposures(session_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_chat_messages_client_id;
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS client_id;
@@ -0,0 +1,5 @@
+-- 클라이언트가 생성한 메시지 ID (재전송 시 같은 메시지를 두 번 저장하지 않기 위한 멱등 키)
+-- NULL은 서로 다른 값으로 취급되므로 client_id 없이 보낸 메시지는 제약을 받지 않는다.
+
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS client_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_messages_client_id ON chat_messages(chat_room_id, user_id, client_id);
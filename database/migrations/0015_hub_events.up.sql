@@ -0,0 +1,11 @@
+-- NOTIFY 페이로드 제한(8000바이트)을 넘는 WebSocket Hub 이벤트
+-- 발행한 인스턴스가 저장하고 ID만 NOTIFY하면 다른 인스턴스가 읽어 간다. 정리 작업이 오래된 행을 지운다.
+-- 전달이 끝나면 필요 없는 데이터이므로 WAL을 쓰지 않는 UNLOGGED 테이블로 만든다.
+
+CREATE UNLOGGED TABLE IF NOT EXISTS hub_events (
+    id         BIGSERIAL PRIMARY KEY,
+    payload    TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_hub_events_created_at ON hub_events(created_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS chat_room_events;
+DROP INDEX IF EXISTS idx_chat_messages_room_seq;
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS seq;
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS last_seq;
@@ -0,0 +1,33 @@
+-- 채팅방별 순번 (메시지와 멤버 이벤트가 같은 순번을 나눠 쓴다)
+-- 실시간 프레임에 seq를 실어 보내고, 다시 연결한 클라이언트에는 놓친 순번 이후를 DB에서 다시 보낸다.
+
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS last_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS seq BIGINT;
+
+-- 기존 메시지는 채팅방별 작성 순서대로 번호를 매긴다.
+UPDATE chat_messages m
+SET seq = numbered.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY chat_room_id ORDER BY created_at, id) AS seq
+    FROM chat_messages
+) numbered
+WHERE m.id = numbered.id AND m.seq IS NULL;
+
+ALTER TABLE chat_messages ALTER COLUMN seq SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_messages_room_seq ON chat_messages(chat_room_id, seq);
+
+UPDATE chat_rooms r
+SET last_seq = COALESCE((SELECT MAX(seq) FROM chat_messages WHERE chat_room_id = r.id), 0);
+
+-- 멤버 추가/제거 이벤트 (브로드캐스트한 data를 그대로 저장해 재연결 시 다시 보낸다)
+CREATE TABLE IF NOT EXISTS chat_room_events (
+    id           BIGSERIAL PRIMARY KEY,
+    chat_room_id BIGINT NOT NULL REFERENCES chat_rooms(id) ON DELETE CASCADE,
+    seq          BIGINT NOT NULL,
+    type         TEXT NOT NULL,
+    user_id      BIGINT NOT NULL,
+    data         TEXT,
+    created_at   TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_room_events_room_seq ON chat_room_events(chat_room_id, seq);
@@ -0,0 +1 @@
+ALTER TABLE questionnaires DROP COLUMN IF EXISTS scoring_method;
@@ -0,0 +1,8 @@
+-- 설문 버전별 점수 계산 방식
+-- 0007에서 단일 카테고리 점수({weight: score})로부터 변환된 버전은 예전처럼 차원별 원점수 평균을 쓰고,
+-- 그 뒤에 만든 버전은 가중치 벡터 정규화를 쓴다 (이미 저장된 결과와 프로필 규칙 기준이 바뀌지 않도록).
+
+ALTER TABLE questionnaires ADD COLUMN IF NOT EXISTS scoring_method TEXT NOT NULL DEFAULT 'weighted';
+
+UPDATE questionnaires SET scoring_method = 'average'
+WHERE created_at <= (SELECT applied_at FROM schema_migrations WHERE version = 7);
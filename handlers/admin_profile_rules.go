@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"ongi-back/config"
+	"ongi-back/middleware"
+	"ongi-back/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminGetProfileRules - 현재 적용 중인 프로필 규칙과 출처
+func AdminGetProfileRules(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    services.CurrentProfileRules(),
+	})
+}
+
+// AdminPreviewProfileRules - 점수 벡터에 현재 규칙을 적용한 결과 미리보기 (요청 언어로 표시)
+func AdminPreviewProfileRules(c *fiber.Ctx) error {
+	var scores services.ScoreResult
+	if err := c.BodyParser(&scores); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	for dimension, score := range scores.ByDimension() {
+		if score < 0 || score > 100 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid scores",
+				"details": dimension + " score must be between 0 and 100",
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    services.CurrentProfileRules().EvaluateProfile(&scores, middleware.GetLocale(c)),
+	})
+}
+
+// AdminReloadProfileRules - 규칙 파일 또는 profile_rules 테이블에서 규칙 다시 로드
+// 검증에 실패하면 기존 규칙을 그대로 유지한다.
+func AdminReloadProfileRules(c *fiber.Ctx) error {
+	if err := services.InitProfileRules(config.AppConfig.ProfileRulesFile); err != nil {
+		var ruleErr *services.ProfileRuleError
+		if errors.As(err, &ruleErr) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid profile rules",
+				"details": ruleErr.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to reload profile rules",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Profile rules reloaded",
+		"data":    services.CurrentProfileRules(),
+	})
+}
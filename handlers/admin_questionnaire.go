@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"ongi-back/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminGetQuestionnaires - 초안을 포함한 설문 버전 목록
+func AdminGetQuestionnaires(c *fiber.Ctx) error {
+	questionnaires, err := services.ListQuestionnaires(true)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch questionnaires",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    questionnaires,
+	})
+}
+
+// AdminCreateQuestionnaire - 새 설문 초안 생성 (base_questionnaire_id 지정 시 해당 버전 복사)
+func AdminCreateQuestionnaire(c *fiber.Ctx) error {
+	var req struct {
+		Title               string `json:"title"`
+		BaseQuestionnaireID uint   `json:"base_questionnaire_id"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	questionnaire, err := services.CreateQuestionnaireDraft(req.Title, req.BaseQuestionnaireID)
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    questionnaire,
+	})
+}
+
+// AdminGetQuestionnaire - 설문 버전 상세 (질문/옵션 및 차원 커버리지 포함)
+func AdminGetQuestionnaire(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid questionnaire ID",
+		})
+	}
+
+	questionnaire, err := services.GetQuestionnaireWithQuestions(uint(id))
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	coverage, err := services.GetQuestionnaireCoverage(uint(id))
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"data":     questionnaire,
+		"coverage": coverage,
+	})
+}
+
+// AdminDeleteQuestionnaire - 설문 초안 삭제
+func AdminDeleteQuestionnaire(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid questionnaire ID",
+		})
+	}
+
+	if err := services.DeleteQuestionnaireDraft(uint(id)); err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Questionnaire draft deleted",
+	})
+}
+
+// AdminPublishQuestionnaire - 설문 초안 발행 (모든 차원을 커버해야 함)
+func AdminPublishQuestionnaire(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid questionnaire ID",
+		})
+	}
+
+	questionnaire, err := services.PublishQuestionnaire(uint(id))
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Questionnaire published",
+		"data":    questionnaire,
+	})
+}
+
+// AdminCreateQuestion - 설문 초안에 질문 추가
+func AdminCreateQuestion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid questionnaire ID",
+		})
+	}
+
+	var req services.QuestionInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	question, err := services.CreateQuestion(uint(id), req)
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    question,
+	})
+}
+
+// AdminUpdateQuestion - 질문 수정
+func AdminUpdateQuestion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid question ID",
+		})
+	}
+
+	var req services.QuestionUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	question, err := services.UpdateQuestion(uint(id), req)
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    question,
+	})
+}
+
+// AdminDeleteQuestion - 질문 삭제
+func AdminDeleteQuestion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid question ID",
+		})
+	}
+
+	if err := services.DeleteQuestion(uint(id)); err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Question deleted",
+	})
+}
+
+// AdminCreateOption - 질문에 옵션 추가
+func AdminCreateOption(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid question ID",
+		})
+	}
+
+	var req services.OptionInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	option, err := services.CreateOption(uint(id), req)
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    option,
+	})
+}
+
+// AdminUpdateOption - 옵션 수정 (텍스트, 점수, 가중치)
+func AdminUpdateOption(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid option ID",
+		})
+	}
+
+	var req services.OptionUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	option, err := services.UpdateOption(uint(id), req)
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    option,
+	})
+}
+
+// AdminDeleteOption - 옵션 삭제
+func AdminDeleteOption(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid option ID",
+		})
+	}
+
+	if err := services.DeleteOption(uint(id)); err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Option deleted",
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateUserRequest 관리자 사용자 생성 요청
+type CreateUserRequest struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// AdminCreateUser - 로그인 수단 없이 사용자 생성 (일반 회원가입은 /auth/:provider/login, /auth/email/register)
+// 이메일 중복 확인을 거치지 않으므로 관리자만 사용한다.
+func AdminCreateUser(c *fiber.Ctx) error {
+	var req CreateUserRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user := models.User{
+		Email: req.Email,
+		Name:  req.Name,
+	}
+
+	err := database.DB.Create(&user).Error
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create user",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    user,
+	})
+}
+
+// AdminBanUser - 계정 정지 (모든 토큰을 즉시 폐기하고 로그인/토큰 갱신 거부)
+func AdminBanUser(c *fiber.Ctx) error {
+	return setUserBanned(c, true)
+}
+
+// AdminUnbanUser - 계정 정지 해제 (사용자는 다시 로그인해야 한다)
+func AdminUnbanUser(c *fiber.Ctx) error {
+	return setUserBanned(c, false)
+}
+
+func setUserBanned(c *fiber.Ctx, banned bool) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if banned {
+		err = services.BanUser(uint(id))
+	} else {
+		err = services.UnbanUser(uint(id))
+	}
+	if errors.Is(err, services.ErrUserNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to update user ban",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"user_id":   id,
+			"is_banned": banned,
+		},
+	})
+}
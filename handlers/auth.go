@@ -1,13 +1,14 @@
 package handlers
 
 import (
-	"fmt"
-	"ongi-back/database"
+	"errors"
+	"ongi-back/middleware"
 	"ongi-back/models"
 	"ongi-back/services"
-	"ongi-back/utils"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
 // KakaoLoginRequest 카카오 로그인 요청
@@ -18,11 +19,19 @@ type KakaoLoginRequest struct {
 // KakaoLoginResponse 카카오 로그인 응답
 type KakaoLoginResponse struct {
 	Success bool   `json:"success"`
-	Token   string `json:"token"`
+	Token   string `json:"token"` // 액세스 토큰
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // 액세스 토큰 유효 기간 (초)
 	User    models.User `json:"user"`
 	IsNewUser bool `json:"is_new_user"`
 }
 
+// RefreshTokenRequest 토큰 갱신/로그아웃 요청
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"` // 로그아웃 시 모든 기기에서 로그아웃
+}
+
 // KakaoLogin 카카오 로그인 처리 (클라이언트사이드 OAuth)
 // POST /auth/kakao/login
 func KakaoLogin(c *fiber.Ctx) error {
@@ -52,33 +61,8 @@ func KakaoLogin(c *fiber.Ctx) error {
 		})
 	}
 
-	// 2. 회원가입/로그인 처리
-	user, isNewUser, err := processKakaoUser(kakaoUserInfo)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to process user",
-			"details": err.Error(),
-		})
-	}
-
-	// 3. JWT 토큰 발급
-	token, err := utils.GenerateJWT(user.ID, user.Email)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to generate token",
-			"details": err.Error(),
-		})
-	}
-
-	// 4. 응답 반환
-	return c.Status(fiber.StatusOK).JSON(KakaoLoginResponse{
-		Success:   true,
-		Token:     token,
-		User:      user,
-		IsNewUser: isNewUser,
-	})
+	// 2. 회원가입/로그인 처리 및 JWT 토큰 발급
+	return loginWithIdentity(c, services.KakaoIdentity(kakaoUserInfo))
 }
 
 // KakaoCallback 카카오 OAuth 콜백 처리 (서버사이드 OAuth)
@@ -124,67 +108,352 @@ func KakaoCallback(c *fiber.Ctx) error {
 		})
 	}
 
-	// 4. 회원가입/로그인 처리
-	user, isNewUser, err := processKakaoUser(kakaoUserInfo)
+	// 4. 회원가입/로그인 처리 및 JWT 토큰 발급
+	return loginWithIdentity(c, services.KakaoIdentity(kakaoUserInfo))
+}
+
+// issueLoginResponse 액세스/리프레시 토큰 발급 후 로그인 응답 반환
+func issueLoginResponse(c *fiber.Ctx, user models.User, isNewUser bool) error {
+	pair, err := services.IssueTokenPair(&user, c.Get(fiber.HeaderUserAgent))
+	if errors.Is(err, services.ErrUserBanned) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "Account is banned",
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
-			"error":   "Failed to process user",
+			"error":   "Failed to generate token",
 			"details": err.Error(),
 		})
 	}
 
-	// 5. JWT 토큰 발급
-	token, err := utils.GenerateJWT(user.ID, user.Email)
+	return c.Status(fiber.StatusOK).JSON(KakaoLoginResponse{
+		Success:      true,
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+		User:         user,
+		IsNewUser:    isNewUser,
+	})
+}
+
+// RefreshToken 리프레시 토큰으로 토큰 재발급 (리프레시 토큰도 회전)
+// POST /auth/refresh
+func RefreshToken(c *fiber.Ctx) error {
+	var req RefreshTokenRequest
+
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Refresh token is required",
+		})
+	}
+
+	pair, err := services.RotateRefreshToken(req.RefreshToken, c.Get(fiber.HeaderUserAgent))
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserBanned):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   "Account is banned",
+			})
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid or expired refresh token",
+			})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"success": false,
+				"error":   "Failed to refresh token",
+				"details": err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    pair,
+	})
+}
+
+// Logout 로그아웃 (현재 액세스 토큰 및 리프레시 토큰 폐기)
+// POST /auth/logout
+func Logout(c *fiber.Ctx) error {
+	var req RefreshTokenRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+		}
+	}
+
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return authErrorResponse(c, fiber.NewError(fiber.StatusUnauthorized, "Authentication required"))
+	}
+
+	// 모든 기기에서 로그아웃
+	if req.All {
+		if err := services.RevokeAllUserTokens(claims.UserID, "logout_all"); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"success": false,
+				"error":   "Failed to logout",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Logged out from all devices",
+		})
+	}
+
+	if req.RefreshToken != "" {
+		if err := services.RevokeRefreshToken(req.RefreshToken, claims.UserID); err != nil && !errors.Is(err, services.ErrInvalidRefreshToken) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"success": false,
+				"error":   "Failed to revoke refresh token",
+			})
+		}
+	}
+
+	if err := services.RevokeAccessToken(claims, "logout"); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
-			"error":   "Failed to generate token",
-			"details": err.Error(),
+			"error":   "Failed to revoke access token",
 		})
 	}
 
-	// 6. 응답 반환
-	return c.Status(fiber.StatusOK).JSON(KakaoLoginResponse{
-		Success:   true,
-		Token:     token,
-		User:      user,
-		IsNewUser: isNewUser,
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Logged out successfully",
 	})
 }
 
-// processKakaoUser 카카오 사용자 정보로 회원가입/로그인 처리
-func processKakaoUser(kakaoUserInfo *services.KakaoUserInfo) (models.User, bool, error) {
-	// 이메일 결정
-	email := kakaoUserInfo.KakaoAccount.Email
-	if email == "" {
-		// 이메일이 없는 경우 카카오 ID 사용
-		email = fmt.Sprintf("kakao_%d@kakao.com", kakaoUserInfo.ID)
+// ProviderLogin 로그인 제공자(kakao, naver, google, apple, email)로 로그인/회원가입
+// POST /auth/:provider/login
+func ProviderLogin(c *fiber.Ctx) error {
+	var cred services.Credential
+
+	if err := c.BodyParser(&cred); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
 	}
 
-	// 이름 결정
-	name := kakaoUserInfo.KakaoAccount.Profile.Nickname
-	if name == "" {
-		name = fmt.Sprintf("User_%d", kakaoUserInfo.ID)
+	ext, err := services.AuthenticateWithProvider(c.Params("provider"), cred)
+	if err != nil {
+		return identityErrorResponse(c, err)
 	}
 
-	// 기존 사용자 확인
-	var user models.User
-	result := database.DB.Where("email = ?", email).First(&user)
+	return loginWithIdentity(c, ext)
+}
+
+// EmailRegisterRequest 이메일 회원가입 요청
+type EmailRegisterRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	Name     string `json:"name"`
+}
+
+// EmailRegister 이메일/비밀번호 회원가입
+// POST /auth/email/register
+func EmailRegister(c *fiber.Ctx) error {
+	var req EmailRegisterRequest
 
-	isNewUser := false
-	if result.Error != nil {
-		// 신규 사용자 - 회원가입
-		user = models.User{
-			Email: email,
-			Name:  name,
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	user, err := services.RegisterWithEmail(req.Email, req.Password, req.Name)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	return issueLoginResponse(c, user, true)
+}
+
+// GetIdentities 로그인된 사용자에게 연결된 로그인 수단 목록
+// GET /auth/identities
+func GetIdentities(c *fiber.Ctx) error {
+	userID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	identities, err := services.GetUserIdentities(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to fetch identities",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"identities": identities,
+			"providers":  services.IdentityProviderNames(),
+		},
+	})
+}
+
+// LinkProvider 로그인된 사용자에게 새 로그인 수단 연결
+// POST /auth/:provider/link
+func LinkProvider(c *fiber.Ctx) error {
+	userID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	var cred services.Credential
+	if err := c.BodyParser(&cred); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	var identity *models.UserIdentity
+	provider := c.Params("provider")
+	if provider == "email" {
+		// 이메일은 기존 계정 확인이 아니라 새 비밀번호 등록
+		identity, err = services.LinkEmailPassword(userID, cred.Email, cred.Password)
+	} else {
+		var ext *services.ExternalIdentity
+		ext, err = services.AuthenticateWithProvider(provider, cred)
+		if err == nil {
+			identity, err = services.LinkIdentity(userID, ext)
 		}
+	}
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
 
-		if err := database.DB.Create(&user).Error; err != nil {
-			return user, false, fmt.Errorf("failed to create user: %w", err)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"message": "Identity linked successfully",
+		"data":    identity,
+	})
+}
+
+// UnlinkProvider 로그인 수단 연결 해제
+// DELETE /auth/:provider/link
+func UnlinkProvider(c *fiber.Ctx) error {
+	userID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	if err := services.UnlinkIdentity(userID, c.Params("provider")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Identity not found",
+			})
 		}
-		isNewUser = true
+		return identityErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Identity unlinked successfully",
+	})
+}
+
+// loginWithIdentity 확인된 외부 계정으로 로그인 처리 후 토큰 발급
+func loginWithIdentity(c *fiber.Ctx, ext *services.ExternalIdentity) error {
+	user, isNewUser, err := services.LoginWithIdentity(ext)
+	if err != nil {
+		return identityErrorResponse(c, err)
 	}
 
-	return user, isNewUser, nil
+	return issueLoginResponse(c, user, isNewUser)
+}
+
+// identityErrorResponse 로그인/계정 연결 에러 응답
+func identityErrorResponse(c *fiber.Ctx, err error) error {
+	var exists *services.AccountExistsError
+	switch {
+	case errors.As(err, &exists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"success":   false,
+			"error":     "An account with this email already exists. Log in with an existing method and link this one.",
+			"code":      "account_exists",
+			"providers": exists.Providers,
+		})
+	case errors.Is(err, services.ErrIdentityLinkedToOther):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"success": false,
+			"error":   "This login is already linked to another account",
+			"code":    "identity_in_use",
+		})
+	case errors.Is(err, services.ErrUnknownProvider):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   "Unknown login provider",
+		})
+	case errors.Is(err, services.ErrWeakPassword), errors.Is(err, services.ErrLastIdentity):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidCredential):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid credentials",
+		})
+	default:
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to authenticate",
+			"details": err.Error(),
+		})
+	}
+}
+
+// resolveActingUser 요청에 포함된 사용자 ID를 토큰의 사용자와 대조
+// requested가 0이면 토큰 사용자를 그대로 사용하고, 다른 사용자를 지정하면 403을 반환한다.
+func resolveActingUser(c *fiber.Ctx, requested uint) (uint, error) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return 0, fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+	}
+
+	if requested != 0 && requested != userID {
+		return 0, fiber.NewError(fiber.StatusForbidden, "Cannot act on behalf of another user")
+	}
+
+	return userID, nil
+}
+
+// resolveActingUserParam 경로 파라미터의 사용자 ID를 토큰의 사용자와 대조
+func resolveActingUserParam(c *fiber.Ctx, param string) (uint, error) {
+	requested, err := strconv.ParseUint(c.Params(param), 10, 32)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	return resolveActingUser(c, uint(requested))
+}
+
+// authErrorResponse 인증/인가 에러 응답
+func authErrorResponse(c *fiber.Ctx, err error) error {
+	code := fiber.StatusUnauthorized
+	if e, ok := err.(*fiber.Error); ok {
+		code = e.Code
+	}
+
+	return c.Status(code).JSON(fiber.Map{
+		"success": false,
+		"error":   err.Error(),
+	})
 }
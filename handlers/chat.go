@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"ongi-back/database"
 	"ongi-back/models"
 	"ongi-back/services"
@@ -31,15 +33,28 @@ func CreateChatRoom(c *fiber.Ctx) error {
 		})
 	}
 
-	// 생성자 ID (실제로는 JWT에서 가져와야 함)
-	// 여기서는 임시로 request body에서 받거나 기본값 사용
-	createdBy := uint(1) // TODO: JWT에서 user_id 추출
+	// 생성자 ID (JWT 토큰의 사용자)
+	createdBy, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
 
 	// 기본값 설정
 	if req.RoomType == "" {
 		req.RoomType = "group"
 	}
 
+	// 초대 멤버 중복 제거 (생성자 제외)
+	memberIDs := make([]uint, 0, len(req.MemberIDs))
+	seen := map[uint]bool{createdBy: true}
+	for _, memberID := range req.MemberIDs {
+		if seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
+		memberIDs = append(memberIDs, memberID)
+	}
+
 	// 채팅방 생성
 	chatRoom := models.ChatRoom{
 		Name:        req.Name,
@@ -47,7 +62,7 @@ func CreateChatRoom(c *fiber.Ctx) error {
 		ClubID:      req.ClubID,
 		RoomType:    req.RoomType,
 		CreatedBy:   createdBy,
-		MemberCount: len(req.MemberIDs) + 1, // 생성자 포함
+		MemberCount: len(memberIDs) + 1, // 생성자 포함
 	}
 
 	if err := database.DB.Create(&chatRoom).Error; err != nil {
@@ -68,10 +83,7 @@ func CreateChatRoom(c *fiber.Ctx) error {
 	database.DB.Create(&creatorMember)
 
 	// 멤버 추가
-	for _, memberID := range req.MemberIDs {
-		if memberID == createdBy {
-			continue // 생성자는 이미 추가됨
-		}
+	for _, memberID := range memberIDs {
 		member := models.ChatRoomMember{
 			ChatRoomID: chatRoom.ID,
 			UserID:     memberID,
@@ -84,6 +96,9 @@ func CreateChatRoom(c *fiber.Ctx) error {
 	// 생성된 채팅방 정보 조회 (멤버 정보 포함)
 	database.DB.Preload("Members.User").Preload("Creator").Preload("Club").First(&chatRoom, chatRoom.ID)
 
+	// 멤버들의 /ws 연결에 새 채팅방 구독 추가
+	services.NotifyChatRoomJoined(chatRoom.ID, append([]uint{createdBy}, memberIDs...))
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
 		"message": "Chat room created successfully",
@@ -94,21 +109,22 @@ func CreateChatRoom(c *fiber.Ctx) error {
 // GetChatRooms 사용자의 채팅방 목록 조회
 // GET /chat/rooms
 func GetChatRooms(c *fiber.Ctx) error {
-	// 사용자 ID (실제로는 JWT에서 가져와야 함)
-	userIDStr := c.Query("user_id")
-	if userIDStr == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "user_id is required",
-		})
+	// 사용자 ID (JWT 토큰의 사용자, user_id 쿼리는 하위 호환용)
+	var requestedID uint64
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		parsed, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid user_id",
+			})
+		}
+		requestedID = parsed
 	}
 
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	userID, err := resolveActingUser(c, uint(requestedID))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Invalid user_id",
-		})
+		return authErrorResponse(c, err)
 	}
 
 	// 사용자가 속한 채팅방 멤버십 조회
@@ -158,6 +174,18 @@ func GetChatRooms(c *fiber.Ctx) error {
 func GetChatRoom(c *fiber.Ctx) error {
 	roomID := c.Params("id")
 
+	userID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	if !isChatRoomMember(roomID, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "User is not a member of this chat room",
+		})
+	}
+
 	var chatRoom models.ChatRoom
 	if err := database.DB.
 		Preload("Members.User").
@@ -178,18 +206,25 @@ func GetChatRoom(c *fiber.Ctx) error {
 
 // SendMessageRequest 메시지 전송 요청
 type SendMessageRequest struct {
-	UserID      uint   `json:"user_id" validate:"required"`
+	UserID      uint   `json:"user_id"` // 선택, 지정 시 토큰 사용자와 일치해야 함
 	Message     string `json:"message" validate:"required"`
 	MessageType string `json:"message_type"` // text, image, file, system
 	FileURL     string `json:"file_url"`
+	ClientID    string `json:"client_id"` // 선택, 같은 값으로 다시 보내면 처음 저장된 메시지를 반환
 }
 
 // SendMessage 메시지 전송
 // POST /chat/rooms/:id/messages
 func SendMessage(c *fiber.Ctx) error {
-	roomID := c.Params("id")
-	var req SendMessageRequest
+	roomID, err := c.ParamsInt("id")
+	if err != nil || roomID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid chat room ID",
+		})
+	}
 
+	var req SendMessageRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"success": false,
@@ -197,44 +232,37 @@ func SendMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	// 채팅방 존재 확인
-	var chatRoom models.ChatRoom
-	if err := database.DB.First(&chatRoom, roomID).Error; err != nil {
+	userID, err := resolveActingUser(c, req.UserID)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	// 메시지 저장 및 WebSocket 브로드캐스트 (WebSocket message 프레임과 같은 처리)
+	message, duplicate, err := services.SendChatMessage(uint(roomID), userID, services.ChatMessageInput{
+		Message:     req.Message,
+		MessageType: req.MessageType,
+		FileURL:     req.FileURL,
+		ClientID:    req.ClientID,
+	})
+	var messageErr *services.ChatMessageError
+	switch {
+	case errors.As(err, &messageErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid message",
+			"details": messageErr.Error(),
+		})
+	case errors.Is(err, services.ErrChatRoomNotFound):
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"success": false,
 			"error":   "Chat room not found",
 		})
-	}
-
-	// 사용자가 채팅방 멤버인지 확인
-	var membership models.ChatRoomMember
-	if err := database.DB.Where("chat_room_id = ? AND user_id = ?", roomID, req.UserID).First(&membership).Error; err != nil {
+	case errors.Is(err, services.ErrNotChatMember):
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"success": false,
 			"error":   "User is not a member of this chat room",
 		})
-	}
-
-	// 기본값 설정
-	if req.MessageType == "" {
-		req.MessageType = "text"
-	}
-
-	var fileURL *string
-	if req.FileURL != "" {
-		fileURL = &req.FileURL
-	}
-
-	// 메시지 생성
-	message := models.ChatMessage{
-		ChatRoomID:  chatRoom.ID,
-		UserID:      req.UserID,
-		Message:     req.Message,
-		MessageType: req.MessageType,
-		FileURL:     fileURL,
-	}
-
-	if err := database.DB.Create(&message).Error; err != nil {
+	case err != nil:
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
 			"error":   "Failed to send message",
@@ -242,29 +270,13 @@ func SendMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	// 채팅방의 last_message 및 last_message_at 업데이트
-	now := time.Now()
-	database.DB.Model(&chatRoom).Updates(map[string]interface{}{
-		"last_message":    req.Message,
-		"last_message_at": now,
-	})
-
-	// 다른 멤버들의 unread_count 증가
-	database.DB.Model(&models.ChatRoomMember{}).
-		Where("chat_room_id = ? AND user_id != ?", roomID, req.UserID).
-		UpdateColumn("unread_count", database.DB.Raw("unread_count + 1"))
-
-	// 메시지 정보 조회 (사용자 정보 포함)
-	database.DB.Preload("User").First(&message, message.ID)
-
-	// WebSocket으로 실시간 브로드캐스트
-	if services.GlobalHub != nil {
-		services.GlobalHub.BroadcastMessage(
-			chatRoom.ID,
-			"message",
-			req.UserID,
-			message,
-		)
+	if duplicate {
+		return c.JSON(fiber.Map{
+			"success":   true,
+			"message":   "Message already sent",
+			"duplicate": true,
+			"data":      message,
+		})
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -283,6 +295,11 @@ func GetMessages(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 50)
 	offset := c.QueryInt("offset", 0)
 
+	userID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
 	// 채팅방 존재 확인
 	var chatRoom models.ChatRoom
 	if err := database.DB.First(&chatRoom, roomID).Error; err != nil {
@@ -292,12 +309,30 @@ func GetMessages(c *fiber.Ctx) error {
 		})
 	}
 
-	// 메시지 조회
+	if !isChatRoomMember(roomID, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "User is not a member of this chat room",
+		})
+	}
+
+	// 메시지 조회 (after_seq를 지정하면 그 순번 이후를 오래된 순으로, 재연결 재전송이 잘렸을 때 이어서 조회)
+	query := database.DB.Preload("User").Where("chat_room_id = ?", roomID)
+	if afterSeqStr := c.Query("after_seq"); afterSeqStr != "" {
+		afterSeq, err := strconv.ParseInt(afterSeqStr, 10, 64)
+		if err != nil || afterSeq < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid after_seq",
+			})
+		}
+		query = query.Where("seq > ?", afterSeq).Order("seq ASC")
+	} else {
+		query = query.Order("created_at DESC")
+	}
+
 	var messages []models.ChatMessage
-	if err := database.DB.
-		Preload("User").
-		Where("chat_room_id = ?", roomID).
-		Order("created_at DESC").
+	if err := query.
 		Limit(limit).
 		Offset(offset).
 		Find(&messages).Error; err != nil {
@@ -328,47 +363,47 @@ func MarkAsRead(c *fiber.Ctx) error {
 	roomID := c.Params("id")
 
 	type ReadRequest struct {
-		UserID uint `json:"user_id" validate:"required"`
+		UserID uint `json:"user_id"` // 선택, 지정 시 토큰 사용자와 일치해야 함
 	}
 
 	var req ReadRequest
-	if err := c.BodyParser(&req); err != nil {
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+		}
+	}
+
+	userID, err := resolveActingUser(c, req.UserID)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+	req.UserID = userID
+
+	roomIDUint, err := strconv.ParseUint(roomID, 10, 32)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"success": false,
-			"error":   "Invalid request body",
+			"error":   "Invalid chat room ID",
 		})
 	}
 
-	// 멤버십 확인 및 업데이트
-	var membership models.ChatRoomMember
-	if err := database.DB.Where("chat_room_id = ? AND user_id = ?", roomID, req.UserID).First(&membership).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+	// last_read_at 업데이트, unread_count 초기화 및 WebSocket 브로드캐스트
+	if _, err := services.MarkChatRoomRead(uint(roomIDUint), req.UserID); err != nil {
+		if errors.Is(err, services.ErrNotChatMember) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "Membership not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
-			"error":   "Membership not found",
+			"error":   "Failed to mark messages as read",
 		})
 	}
 
-	// last_read_at 업데이트 및 unread_count 초기화
-	now := time.Now()
-	database.DB.Model(&membership).Updates(map[string]interface{}{
-		"last_read_at":  now,
-		"unread_count": 0,
-	})
-
-	// WebSocket으로 읽음 처리 브로드캐스트
-	roomIDUint, _ := strconv.ParseUint(roomID, 10, 32)
-	if services.GlobalHub != nil {
-		services.GlobalHub.BroadcastMessage(
-			uint(roomIDUint),
-			"read",
-			req.UserID,
-			fiber.Map{
-				"user_id":      req.UserID,
-				"last_read_at": now,
-			},
-		)
-	}
-
 	return c.JSON(fiber.Map{
 		"success": true,
 		"message": "Messages marked as read",
@@ -401,6 +436,19 @@ func AddChatRoomMember(c *fiber.Ctx) error {
 		})
 	}
 
+	// 요청자가 채팅방 멤버인지 확인 (멤버만 초대 가능)
+	actorID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	if !isChatRoomMember(roomID, actorID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "Only chat room members can add members",
+		})
+	}
+
 	// 이미 멤버인지 확인
 	var existingMember models.ChatRoomMember
 	if err := database.DB.Where("chat_room_id = ? AND user_id = ?", roomID, req.UserID).First(&existingMember).Error; err == nil {
@@ -431,15 +479,11 @@ func AddChatRoomMember(c *fiber.Ctx) error {
 	// 멤버 정보 조회
 	database.DB.Preload("User").First(&member, member.ID)
 
-	// WebSocket으로 멤버 추가 브로드캐스트
-	if services.GlobalHub != nil {
-		services.GlobalHub.BroadcastMessage(
-			chatRoom.ID,
-			"member_join",
-			req.UserID,
-			member,
-		)
+	// WebSocket으로 멤버 추가 브로드캐스트 (재연결 재전송용으로 순번과 함께 기록)
+	if err := services.PublishMemberEvent(chatRoom.ID, "member_join", req.UserID, member); err != nil {
+		log.Printf("Failed to publish member_join: roomID=%d, userID=%d: %v", chatRoom.ID, req.UserID, err)
 	}
+	services.NotifyChatRoomJoined(chatRoom.ID, []uint{req.UserID})
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
@@ -454,6 +498,22 @@ func RemoveChatRoomMember(c *fiber.Ctx) error {
 	roomID := c.Params("id")
 	userID := c.Params("userId")
 
+	actorID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	// 본인 탈퇴 또는 채팅방 admin만 멤버 제거 가능
+	if strconv.FormatUint(uint64(actorID), 10) != userID {
+		var actor models.ChatRoomMember
+		if err := database.DB.Where("chat_room_id = ? AND user_id = ?", roomID, actorID).First(&actor).Error; err != nil || actor.Role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   "Only chat room admins can remove other members",
+			})
+		}
+	}
+
 	// 멤버십 확인
 	var member models.ChatRoomMember
 	if err := database.DB.Where("chat_room_id = ? AND user_id = ?", roomID, userID).First(&member).Error; err != nil {
@@ -476,22 +536,27 @@ func RemoveChatRoomMember(c *fiber.Ctx) error {
 	database.DB.First(&chatRoom, roomID)
 	database.DB.Model(&chatRoom).UpdateColumn("member_count", database.DB.Raw("member_count - 1"))
 
-	// WebSocket으로 멤버 제거 브로드캐스트
+	// WebSocket으로 멤버 제거 브로드캐스트 (재연결 재전송용으로 순번과 함께 기록)
 	roomIDUint, _ := strconv.ParseUint(roomID, 10, 32)
 	userIDUint, _ := strconv.ParseUint(userID, 10, 32)
-	if services.GlobalHub != nil {
-		services.GlobalHub.BroadcastMessage(
-			uint(roomIDUint),
-			"member_leave",
-			uint(userIDUint),
-			fiber.Map{
-				"user_id": userIDUint,
-			},
-		)
+	if err := services.PublishMemberEvent(uint(roomIDUint), "member_leave", uint(userIDUint), fiber.Map{
+		"user_id": userIDUint,
+	}); err != nil {
+		log.Printf("Failed to publish member_leave: roomID=%s, userID=%s: %v", roomID, userID, err)
 	}
+	services.NotifyChatRoomLeft(uint(roomIDUint), uint(userIDUint))
 
 	return c.JSON(fiber.Map{
 		"success": true,
 		"message": "Member removed successfully",
 	})
 }
+
+// isChatRoomMember 사용자가 채팅방 멤버인지 확인
+func isChatRoomMember(roomID interface{}, userID uint) bool {
+	var count int64
+	database.DB.Model(&models.ChatRoomMember{}).
+		Where("chat_room_id = ? AND user_id = ?", roomID, userID).
+		Count(&count)
+	return count > 0
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"ongi-back/database"
 	"ongi-back/models"
+	"ongi-back/services"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -42,12 +45,86 @@ func GetClub(c *fiber.Ctx) error {
 	})
 }
 
+// 성향 기반 클럽 추천 (로그인한 회원)
+// GET /clubs/recommended?tags=독서,토론&vibe=cozy&location=강남&limit=10
+func GetRecommendedClubs(c *fiber.Ctx) error {
+	userID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	matches, err := services.RecommendClubMatches(services.UserRespondent(userID), clubLimitQuery(c), clubFilterQuery(c))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User profile not found. Please complete the survey first.",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    matches,
+	})
+}
+
+// 성향 기반 클럽 추천 (비회원 세션)
+// GET /guest/result/:sessionId/clubs?tags=...&vibe=...&location=...&limit=10
+func GetRecommendedClubsForSession(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+
+	if _, err := services.GetGuestSession(sessionID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Session not found or expired",
+		})
+	}
+
+	matches, err := services.RecommendClubMatches(services.GuestRespondent(sessionID), clubLimitQuery(c), clubFilterQuery(c))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Result not found. Please fetch the session result first.",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    matches,
+	})
+}
+
+// clubFilterQuery 클럽 추천 필터 쿼리 (tags는 쉼표로 구분, 모두 포함하는 클럽만)
+func clubFilterQuery(c *fiber.Ctx) services.ClubFilter {
+	filter := services.ClubFilter{
+		Vibe:        strings.TrimSpace(c.Query("vibe")),
+		Location:    strings.TrimSpace(c.Query("location")),
+		ExcludeFull: c.QueryBool("exclude_full", false),
+	}
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	return filter
+}
+
+// clubLimitQuery 추천 개수 (기본 10, 최대 50)
+func clubLimitQuery(c *fiber.Ctx) int {
+	limit := c.QueryInt("limit", 10)
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	return limit
+}
+
 // 클럽 생성
 type CreateClubRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Category    string `json:"category"`
-	ImageURL    string `json:"image_url"`
+	Name            string             `json:"name"`
+	Description     string             `json:"description"`
+	Category        string             `json:"category"`
+	ImageURL        string             `json:"image_url"`
+	PreferredScores map[string]float64 `json:"preferred_scores"` // 선택, 차원별 목표 점수 (0-100)
+	Tolerances      map[string]float64 `json:"tolerances"`       // 선택, 차원별 허용 오차
 }
 
 func CreateClub(c *fiber.Ctx) error {
@@ -59,15 +136,30 @@ func CreateClub(c *fiber.Ctx) error {
 		})
 	}
 
+	target := services.ClubTarget{Scores: req.PreferredScores, Tolerances: req.Tolerances}
+	preferredScores, tolerances, err := target.Encode()
+	if err != nil {
+		var targetErr *services.ClubTargetError
+		if errors.As(err, &targetErr) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid club target",
+				"details": targetErr.Error(),
+			})
+		}
+		return err
+	}
+
 	club := models.Club{
-		Name:        req.Name,
-		Description: req.Description,
-		Category:    req.Category,
-		ImageURL:    req.ImageURL,
-		MemberCount: 0,
+		Name:            req.Name,
+		Description:     req.Description,
+		Category:        req.Category,
+		ImageURL:        req.ImageURL,
+		MemberCount:     0,
+		PreferredScores: preferredScores,
+		Tolerances:      tolerances,
 	}
 
-	err := database.DB.Create(&club).Error
+	err = database.DB.Create(&club).Error
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create club",
@@ -82,7 +174,7 @@ func CreateClub(c *fiber.Ctx) error {
 
 // 클럽 가입
 type JoinClubRequest struct {
-	UserID uint `json:"user_id"`
+	UserID uint `json:"user_id"` // 선택, 지정 시 토큰 사용자와 일치해야 함
 	ClubID uint `json:"club_id"`
 }
 
@@ -95,31 +187,33 @@ func JoinClub(c *fiber.Ctx) error {
 		})
 	}
 
-	// 이미 가입했는지 확인
-	var existing models.ClubMember
-	result := database.DB.Where("user_id = ? AND club_id = ?", req.UserID, req.ClubID).First(&existing)
-	if result.Error == nil {
+	userID, err := resolveActingUser(c, req.UserID)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+	req.UserID = userID
+
+	// 클럽 행을 잠그고 정원 확인 후 가입 (멤버 수도 같은 트랜잭션에서 증가)
+	member, err := services.JoinClub(req.UserID, req.ClubID)
+	switch {
+	case errors.Is(err, services.ErrAlreadyMember):
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Already a member of this club",
 		})
-	}
-
-	member := models.ClubMember{
-		UserID: req.UserID,
-		ClubID: req.ClubID,
-	}
-
-	err := database.DB.Create(&member).Error
-	if err != nil {
+	case errors.Is(err, services.ErrClubNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Club not found",
+		})
+	case errors.Is(err, services.ErrClubFull):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Club is full",
+		})
+	case err != nil:
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to join club",
 		})
 	}
 
-	// 클럽의 멤버 수 증가
-	database.DB.Model(&models.Club{}).Where("id = ?", req.ClubID).
-		Update("member_count", database.DB.Raw("member_count + 1"))
-
 	return c.JSON(fiber.Map{
 		"success": true,
 		"message": "Successfully joined club",
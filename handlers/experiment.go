@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"ongi-back/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminGetExperiments - 실험 목록과 사용할 수 있는 클럽 추천 전략
+func AdminGetExperiments(c *fiber.Ctx) error {
+	experiments, err := services.ListExperiments()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to fetch experiments",
+			"details": err.Error(),
+		})
+	}
+
+	strategies := []fiber.Map{}
+	for _, strategy := range services.ClubStrategies() {
+		strategies = append(strategies, fiber.Map{
+			"name":        strategy.Name(),
+			"description": strategy.Description(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success":    true,
+		"data":       experiments,
+		"strategies": strategies,
+	})
+}
+
+// AdminCreateExperiment - 실험 생성과 시작 (실행 중인 실험은 하나만)
+func AdminCreateExperiment(c *fiber.Ctx) error {
+	var req services.ExperimentInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	experiment, err := services.CreateExperiment(req)
+	var experimentErr *services.ExperimentError
+	if errors.As(err, &experimentErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid experiment",
+			"details": experimentErr.Error(),
+		})
+	}
+	if errors.Is(err, services.ErrExperimentRunning) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "Another experiment is running",
+			"details": "stop the running experiment first",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to create experiment",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    experiment,
+	})
+}
+
+// AdminStopExperiment - 실험 종료 (이후 추천은 RECOMMENDATION_STRATEGY)
+func AdminStopExperiment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid experiment ID",
+		})
+	}
+
+	experiment, err := services.StopExperiment(uint(id))
+	if errors.Is(err, services.ErrExperimentNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Experiment not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to stop experiment",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    experiment,
+	})
+}
+
+// AdminGetExperimentReport - 변형별 노출 수와 가입 전환율
+func AdminGetExperimentReport(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid experiment ID",
+		})
+	}
+
+	report, err := services.GetExperimentReport(uint(id))
+	if errors.Is(err, services.ErrExperimentNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Experiment not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to build experiment report",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
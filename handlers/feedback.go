@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"ongi-back/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RecommendationFeedbackRequest 추천 피드백 기록 요청
+type RecommendationFeedbackRequest struct {
+	Events []services.FeedbackEvent `json:"events"`
+}
+
+// RecordRecommendationFeedback - 회원의 추천 노출/클릭/관심 없음/가입 기록
+func RecordRecommendationFeedback(c *fiber.Ctx) error {
+	userID, err := resolveActingUser(c, 0)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+	return recordFeedback(c, services.UserRespondent(userID))
+}
+
+// RecordGuestRecommendationFeedback - 비회원 세션의 추천 피드백 기록
+func RecordGuestRecommendationFeedback(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+	if _, err := services.GetGuestSession(sessionID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Session not found or expired",
+		})
+	}
+	return recordFeedback(c, services.GuestRespondent(sessionID))
+}
+
+func recordFeedback(c *fiber.Ctx, r services.Respondent) error {
+	var req RecommendationFeedbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	recorded, err := services.RecordFeedback(r, req.Events)
+	var feedbackErr *services.FeedbackError
+	if errors.As(err, &feedbackErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid feedback",
+			"details": feedbackErr.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to record feedback",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"recorded": recorded,
+		},
+	})
+}
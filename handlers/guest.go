@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"ongi-back/database"
+	"ongi-back/i18n"
+	"ongi-back/middleware"
 	"ongi-back/models"
 	"ongi-back/services"
 	"ongi-back/utils"
@@ -25,7 +28,7 @@ func CreateGuestSession(c *fiber.Ctx) error {
 			"session_id": session.ID,
 			"expires_at": session.ExpiresAt,
 		},
-		"message": "Guest session created. Save this session_id to retrieve your results later.",
+		"message": i18n.T(middleware.GetLocale(c), "guest.session_created"),
 	})
 }
 
@@ -33,6 +36,7 @@ func CreateGuestSession(c *fiber.Ctx) error {
 func SubmitGuestAnswers(c *fiber.Ctx) error {
 	var req struct {
 		SessionID string                `json:"session_id"`
+		QuestionnaireID uint            `json:"questionnaire_id"` // 선택, 미지정 시 현재 발행된 버전
 		Answers   []models.AnswerPayload `json:"answers"`
 	}
 
@@ -51,16 +55,18 @@ func SubmitGuestAnswers(c *fiber.Ctx) error {
 	}
 
 	// 답변 저장
-	err = services.SubmitGuestAnswers(req.SessionID, req.Answers)
+	questionnaire, err := services.SubmitGuestAnswers(req.SessionID, req.QuestionnaireID, req.Answers)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to save answers",
-		})
+		return questionnaireErrorResponse(c, err)
 	}
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Answers submitted successfully",
+		"message": i18n.T(middleware.GetLocale(c), "guest.answers_submitted"),
+		"data": fiber.Map{
+			"questionnaire_id":      questionnaire.ID,
+			"questionnaire_version": questionnaire.Version,
+		},
 	})
 }
 
@@ -68,6 +74,12 @@ func SubmitGuestAnswers(c *fiber.Ctx) error {
 func GetGuestResult(c *fiber.Ctx) error {
 	sessionID := c.Params("sessionId")
 
+	// 유사 프로필 척도 (?metric=, 미지정 시 SIMILARITY_METRIC)
+	metric, err := resolveMetricParam(c.Query("metric"))
+	if err != nil {
+		return metricErrorResponse(c, err)
+	}
+
 	// 세션 확인
 	session, err := services.GetGuestSession(sessionID)
 	if err != nil {
@@ -79,11 +91,11 @@ func GetGuestResult(c *fiber.Ctx) error {
 	// 이미 계산된 결과가 있는지 확인
 	if session.ProfileType != "" {
 		// 캐시된 결과 반환
-		return returnGuestResult(c, sessionID, session)
+		return returnGuestResult(c, sessionID, session, metric)
 	}
 
 	// 점수 계산
-	scores, err := services.CalculateGuestScores(sessionID)
+	scores, err := services.CalculateRespondentScores(services.GuestRespondent(sessionID))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to calculate scores: " + err.Error(),
@@ -113,10 +125,10 @@ func GetGuestResult(c *fiber.Ctx) error {
 
 	// 세션 다시 조회
 	session, _ = services.GetGuestSession(sessionID)
-	return returnGuestResult(c, sessionID, session)
+	return returnGuestResult(c, sessionID, session, metric)
 }
 
-func returnGuestResult(c *fiber.Ctx, sessionID string, session *models.GuestSession) error {
+func returnGuestResult(c *fiber.Ctx, sessionID string, session *models.GuestSession, metric utils.SimilarityMetric) error {
 	scores := &services.ScoreResult{
 		SocialityScore:   session.SocialityScore,
 		ActivityScore:    session.ActivityScore,
@@ -125,22 +137,25 @@ func returnGuestResult(c *fiber.Ctx, sessionID string, session *models.GuestSess
 		FlexibilityScore: session.FlexibilityScore,
 	}
 
-	descriptions := strings.Split(session.ResultSummary, " ")
-	if len(descriptions) == 1 && descriptions[0] == "" {
-		descriptions = services.GenerateDescriptions(scores)
-	}
+	// 저장된 결과는 기본 언어이므로 요청 언어로 변환
+	locale := middleware.GetLocale(c)
+	profileType := services.LocalizeProfileType(session.ProfileType, locale)
+	descriptions := services.LocalizedDescriptions(scores, locale)
 
-	// 추천 데이터 가져오기
-	recommendedClubs, _ := services.GetRecommendedClubsForSession(sessionID, 5)
-	similarClubs, _ := services.GetClubsWithSimilarMembersForSession(sessionID, 5)
-	recommendedMeetings, _ := services.GetRecommendedMeetingsForSession(sessionID, 5)
-	similarProfiles, _ := services.GetSimilarProfilesFast(sessionID, 5)
+	// 추천 데이터 가져오기 (항목마다 추천 이유 포함, 클럽은 tags/vibe/location 필터 적용)
+	respondent := services.GuestRespondent(sessionID)
+	recommendedClubs, _ := services.ExplainClubRecommendations(respondent, 5, clubFilterQuery(c), locale)
+	similarClubs, _ := services.ExplainSimilarMemberClubs(respondent, 5, locale)
+	recommendedMeetings, _ := services.ExplainMeetingRecommendations(respondent, 5, locale)
+	similarProfiles, _ := services.FindSimilarRespondents(respondent, 5, services.SimilarityFilter{ExcludeExpired: true, Metric: metric})
+	services.ExplainSimilarProfiles(scores.Vector(), similarProfiles, locale)
 
 	result := fiber.Map{
 		"session_id":  sessionID,
 		"is_linked":   session.IsLinked,
 		"scores":      scores,
-		"profile_type": session.ProfileType,
+		"profile_type": profileType,
+		"questionnaire_id": session.QuestionnaireID,
 		"descriptions": descriptions,
 		"recommendations": fiber.Map{
 			"clubs":           recommendedClubs,
@@ -161,7 +176,7 @@ func returnGuestResult(c *fiber.Ctx, sessionID string, session *models.GuestSess
 func LinkSessionToAccount(c *fiber.Ctx) error {
 	var req struct {
 		SessionID string `json:"session_id"`
-		UserID    uint   `json:"user_id"`
+		UserID    uint   `json:"user_id"` // 선택, 지정 시 토큰 사용자와 일치해야 함
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -170,6 +185,12 @@ func LinkSessionToAccount(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, err := resolveActingUser(c, req.UserID)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+	req.UserID = userID
+
 	// 세션 확인
 	session, err := services.GetGuestSession(req.SessionID)
 	if err != nil {
@@ -204,7 +225,7 @@ func LinkSessionToAccount(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Session successfully linked to user account",
+		"message": i18n.T(middleware.GetLocale(c), "guest.session_linked"),
 		"data": fiber.Map{
 			"user_id":    req.UserID,
 			"session_id": req.SessionID,
@@ -242,6 +263,7 @@ func GetCompatibility(c *fiber.Ctx) error {
 	var req struct {
 		SessionID1 string `json:"session_id_1"`
 		SessionID2 string `json:"session_id_2"`
+		Metric     string `json:"metric"` // 선택, 미지정 시 COMPATIBILITY_METRIC
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -272,10 +294,40 @@ func GetCompatibility(c *fiber.Ctx) error {
 	}
 
 	// 궁합 계산
-	compatibility := services.CalculateProfileCompatibility(v1, v2)
+	metric, err := resolveMetricParam(req.Metric)
+	if err == nil && metric == nil {
+		metric, err = services.DefaultCompatibilityMetric()
+	}
+	if err != nil {
+		return metricErrorResponse(c, err)
+	}
+	compatibility := services.CalculateProfileCompatibility(v1, v2, metric, middleware.GetLocale(c))
 
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    compatibility,
 	})
 }
+
+// resolveMetricParam 요청에 지정된 유사도 척도 (비어 있으면 nil, 설정 기본값 사용)
+func resolveMetricParam(name string) (utils.SimilarityMetric, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return services.ResolveSimilarityMetric(name)
+}
+
+// metricErrorResponse 척도 조회 실패 응답 (알 수 없는 척도는 400, 모집단 부족 등은 503)
+func metricErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrUnknownMetric) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":             "Invalid similarity metric",
+			"details":           err.Error(),
+			"supported_metrics": utils.MetricNames,
+		})
+	}
+	return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+		"error":   "Similarity metric unavailable",
+		"details": err.Error(),
+	})
+}
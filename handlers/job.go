@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"ongi-back/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminGetJobs - 작업 실행기 상태와 최근 작업 기록 (?name=, ?status=, ?limit=)
+func AdminGetJobs(c *fiber.Ctx) error {
+	status, err := services.JobStatus()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to fetch job status",
+			"details": err.Error(),
+		})
+	}
+
+	jobs, err := services.ListJobs(c.Query("name"), c.Query("status"), c.QueryInt("limit", 0))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to fetch jobs",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"runner":  status,
+		"data":    jobs,
+	})
+}
+
+// AdminRunJob - 작업 즉시 실행 요청 (본문은 작업 입력 JSON, 선택)
+func AdminRunJob(c *fiber.Ctx) error {
+	var params interface{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&params); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	job, err := services.EnqueueJob(c.Params("name"), params)
+	if errors.Is(err, services.ErrUnknownJob) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Job not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to start job",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"success":    true,
+		"data":       job,
+		"status_url": jobStatusURL(job.ID),
+	})
+}
+
+// AdminGetJob - 작업 상태/진행률/결과 조회 (결과에 전체 사용자의 배정이 들어 있으므로 관리자 전용)
+func AdminGetJob(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+		})
+	}
+
+	job, err := services.GetJob(uint(id))
+	if errors.Is(err, services.ErrJobNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Job not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to fetch job",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    job,
+	})
+}
+
+func jobStatusURL(id uint) string {
+	return fmt.Sprintf("/api/v1/admin/jobs/%d", id)
+}
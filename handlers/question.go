@@ -1,26 +1,40 @@
 package handlers
 
 import (
+	"errors"
 	"ongi-back/database"
+	"ongi-back/middleware"
 	"ongi-back/models"
+	"ongi-back/services"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// 모든 질문 가져오기
+// 모든 질문 가져오기 (questionnaire_id 미지정 시 현재 발행된 버전)
 func GetQuestions(c *fiber.Ctx) error {
+	questionnaire, err := services.ResolveAnswerableQuestionnaire(uint(c.QueryInt("questionnaire_id", 0)))
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
 	var questions []models.Question
 
-	err := database.DB.Preload("Options").Order("\"order\" ASC").Find(&questions).Error
+	err = database.DB.Preload("Options").
+		Where("questionnaire_id = ?", questionnaire.ID).
+		Order("\"order\" ASC").
+		Find(&questions).Error
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch questions",
 		})
 	}
+	services.LocalizeQuestions(questions, middleware.GetLocale(c))
 
 	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    questions,
+		"success":               true,
+		"questionnaire_id":      questionnaire.ID,
+		"questionnaire_version": questionnaire.Version,
+		"data":                  questions,
 	})
 }
 
@@ -35,6 +49,7 @@ func GetQuestion(c *fiber.Ctx) error {
 			"error": "Question not found",
 		})
 	}
+	services.LocalizeQuestion(&question, middleware.GetLocale(c))
 
 	return c.JSON(fiber.Map{
 		"success": true,
@@ -44,7 +59,7 @@ func GetQuestion(c *fiber.Ctx) error {
 
 // 사용자 답변 제출
 type SubmitAnswerRequest struct {
-	UserID     uint `json:"user_id"`
+	UserID     uint `json:"user_id"` // 선택, 지정 시 토큰 사용자와 일치해야 함
 	QuestionID uint `json:"question_id"`
 	OptionID   uint `json:"option_id"`
 }
@@ -58,14 +73,37 @@ func SubmitAnswer(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, err := resolveActingUser(c, req.UserID)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+	req.UserID = userID
+
+	// 질문이 속한 설문 버전 확인
+	var question models.Question
+	if err := database.DB.First(&question, req.QuestionID).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Question not found",
+		})
+	}
+	if _, err := services.ResolveAnswerableQuestionnaire(question.QuestionnaireID); err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+	if err := services.ValidateAnswers(question.QuestionnaireID, []models.AnswerPayload{
+		{QuestionID: req.QuestionID, OptionID: req.OptionID},
+	}); err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
 	// 답변 저장
 	answer := models.UserAnswer{
-		UserID:     req.UserID,
-		QuestionID: req.QuestionID,
-		OptionID:   req.OptionID,
+		UserID:          req.UserID,
+		QuestionnaireID: question.QuestionnaireID,
+		QuestionID:      req.QuestionID,
+		OptionID:        req.OptionID,
 	}
 
-	err := database.DB.Create(&answer).Error
+	err = database.DB.Create(&answer).Error
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to save answer",
@@ -81,7 +119,8 @@ func SubmitAnswer(c *fiber.Ctx) error {
 
 // 여러 답변 한번에 제출
 type SubmitAnswersRequest struct {
-	UserID  uint              `json:"user_id"`
+	UserID  uint              `json:"user_id"` // 선택, 지정 시 토큰 사용자와 일치해야 함
+	QuestionnaireID uint      `json:"questionnaire_id"` // 선택, 미지정 시 현재 발행된 버전
 	Answers []AnswerSubmission `json:"answers"`
 }
 
@@ -99,15 +138,35 @@ func SubmitAnswers(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, err := resolveActingUser(c, req.UserID)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+	req.UserID = userID
+
+	questionnaire, err := services.ResolveAnswerableQuestionnaire(req.QuestionnaireID)
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
+	payloads := make([]models.AnswerPayload, len(req.Answers))
+	for i, ans := range req.Answers {
+		payloads[i] = models.AnswerPayload{QuestionID: ans.QuestionID, OptionID: ans.OptionID}
+	}
+	if err := services.ValidateAnswers(questionnaire.ID, payloads); err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+
 	// 기존 답변 삭제 (재시험 가능하도록)
 	database.DB.Where("user_id = ?", req.UserID).Delete(&models.UserAnswer{})
 
 	// 새 답변들 저장
 	for _, ans := range req.Answers {
 		answer := models.UserAnswer{
-			UserID:     req.UserID,
-			QuestionID: ans.QuestionID,
-			OptionID:   ans.OptionID,
+			UserID:          req.UserID,
+			QuestionnaireID: questionnaire.ID,
+			QuestionID:      ans.QuestionID,
+			OptionID:        ans.OptionID,
 		}
 
 		if err := database.DB.Create(&answer).Error; err != nil {
@@ -120,5 +179,54 @@ func SubmitAnswers(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{
 		"success": true,
 		"message": "All answers submitted successfully",
+		"data": fiber.Map{
+			"questionnaire_id":      questionnaire.ID,
+			"questionnaire_version": questionnaire.Version,
+		},
 	})
 }
+
+// questionnaireErrorResponse 설문 버전/응답 검증 에러를 HTTP 응답으로 변환
+func questionnaireErrorResponse(c *fiber.Ctx, err error) error {
+	var invalidAnswer *services.InvalidAnswerError
+	var invalidQuestion *services.QuestionValidationError
+	var coverageErr *services.CoverageError
+	switch {
+	case errors.As(err, &invalidAnswer):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid answer",
+			"details": invalidAnswer.Error(),
+		})
+	case errors.As(err, &invalidQuestion):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid question",
+			"details": invalidQuestion.Error(),
+		})
+	case errors.As(err, &coverageErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":              "Dimension coverage check failed",
+			"details":            coverageErr.Error(),
+			"missing_dimensions": coverageErr.Missing,
+		})
+	case errors.Is(err, services.ErrQuestionnaireNotFound), errors.Is(err, services.ErrNoPublishedQuestionnaire),
+		errors.Is(err, services.ErrQuestionNotFound), errors.Is(err, services.ErrOptionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrQuestionnaireNotOpen), errors.Is(err, services.ErrQuestionnaireEmpty):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrQuestionnaireNotDraft):
+		// 발행된 버전은 수정할 수 없으므로 새 초안을 만들어야 함
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   err.Error(),
+			"details": "Published questionnaires are immutable; create a new draft from it instead",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Questionnaire operation failed",
+			"details": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"ongi-back/middleware"
+	"ongi-back/models"
+	"ongi-back/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetQuestionnaires - 설문 버전 목록
+func GetQuestionnaires(c *fiber.Ctx) error {
+	questionnaires, err := services.ListQuestionnaires(false)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch questionnaires",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    questionnaires,
+	})
+}
+
+// GetQuestionnaire - 설문 버전 상세 (질문/옵션 포함)
+// 과거 결과를 해석할 수 있도록 보관된 버전도 조회할 수 있다.
+func GetQuestionnaire(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid questionnaire ID",
+		})
+	}
+
+	questionnaire, err := services.GetQuestionnaireWithQuestions(uint(id))
+	if err != nil {
+		return questionnaireErrorResponse(c, err)
+	}
+	if questionnaire.Status == models.QuestionnaireStatusDraft {
+		return questionnaireErrorResponse(c, services.ErrQuestionnaireNotFound)
+	}
+	services.LocalizeQuestions(questionnaire.Questions, middleware.GetLocale(c))
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    questionnaire,
+	})
+}
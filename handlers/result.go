@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"ongi-back/database"
+	"ongi-back/middleware"
 	"ongi-back/models"
 	"ongi-back/services"
-	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,21 +12,29 @@ import (
 
 // 분석 결과 생성 및 조회
 func GetAnalysisResult(c *fiber.Ctx) error {
-	userID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	userID, err := resolveActingUserParam(c, "userId")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return authErrorResponse(c, err)
 	}
 
+	// 유사 회원 척도 (?metric=, 미지정 시 SIMILARITY_METRIC)
+	metric, err := resolveMetricParam(c.Query("metric"))
+	if err != nil {
+		return metricErrorResponse(c, err)
+	}
+
+	respondent := services.UserRespondent(uint(userID))
+
 	// 점수 계산
-	scores, err := services.CalculateScores(uint(userID))
+	scores, err := services.CalculateRespondentScores(respondent)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to calculate scores: " + err.Error(),
 		})
 	}
 
+	questionnaireID, _ := services.RespondentQuestionnaireID(respondent)
+
 	// 프로필 타입 및 설명 생성
 	profileType := services.DetermineProfileType(scores)
 	descriptions := services.GenerateDescriptions(scores)
@@ -41,6 +49,7 @@ func GetAnalysisResult(c *fiber.Ctx) error {
 		FlexibilityScore: scores.FlexibilityScore,
 		ProfileType:      profileType,
 		ResultSummary:    strings.Join(descriptions, " "),
+		QuestionnaireID:  &questionnaireID,
 	}
 
 	// upsert (존재하면 업데이트, 없으면 생성)
@@ -54,17 +63,23 @@ func GetAnalysisResult(c *fiber.Ctx) error {
 		// 생성
 		database.DB.Create(&profile)
 	}
+	services.IndexUserProfile(&profile)
+
+	// 프로필에는 기본 언어로 저장하고, 응답은 요청 언어로 변환
+	locale := middleware.GetLocale(c)
 
-	// 추천 데이터 가져오기
-	recommendedClubs, _ := services.GetRecommendedClubs(uint(userID), 5)
-	similarClubs, _ := services.GetClubsWithSimilarMembers(uint(userID), 5)
-	recommendedMeetings, _ := services.GetRecommendedMeetings(uint(userID), 5)
-	similarUsers, _ := services.GetSimilarUsers(uint(userID), 5)
+	// 추천 데이터 가져오기 (항목마다 추천 이유 포함, 클럽은 tags/vibe/location 필터 적용)
+	recommendedClubs, _ := services.ExplainClubRecommendations(respondent, 5, clubFilterQuery(c), locale)
+	similarClubs, _ := services.ExplainSimilarMemberClubs(respondent, 5, locale)
+	recommendedMeetings, _ := services.ExplainMeetingRecommendations(respondent, 5, locale)
+	similarUsers, _ := services.GetSimilarUsers(uint(userID), 5, metric)
+	services.ExplainSimilarUsers(scores.Vector(), similarUsers, locale)
 
 	analysisResult := fiber.Map{
 		"scores":       scores,
-		"profile_type": profileType,
-		"descriptions": descriptions,
+		"profile_type": services.LocalizeProfileType(profileType, locale),
+		"questionnaire_id": questionnaireID,
+		"descriptions": services.LocalizedDescriptions(scores, locale),
 		"recommendations": fiber.Map{
 			"clubs":          recommendedClubs,
 			"similar_clubs":  similarClubs,
@@ -81,10 +96,13 @@ func GetAnalysisResult(c *fiber.Ctx) error {
 
 // 사용자의 모든 답변 조회
 func GetUserAnswers(c *fiber.Ctx) error {
-	userID := c.Params("userId")
+	userID, err := resolveActingUserParam(c, "userId")
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
 
 	var answers []models.UserAnswer
-	err := database.DB.Preload("Question").Preload("Option").
+	err = database.DB.Preload("Question").Preload("Option").
 		Where("user_id = ?", userID).
 		Find(&answers).Error
 
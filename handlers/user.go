@@ -1,49 +1,19 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"ongi-back/database"
+	"ongi-back/middleware"
 	"ongi-back/models"
 	"ongi-back/services"
+	"ongi-back/utils"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// 사용자 생성
-type CreateUserRequest struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
-}
-
-func CreateUser(c *fiber.Ctx) error {
-	var req CreateUserRequest
-
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
-	}
-
-	user := models.User{
-		Email: req.Email,
-		Name:  req.Name,
-	}
-
-	err := database.DB.Create(&user).Error
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create user",
-		})
-	}
-
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"success": true,
-		"data":    user,
-	})
-}
-
 // 사용자 조회
 func GetUser(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -79,24 +49,10 @@ func GetUsers(c *fiber.Ctx) error {
 	})
 }
 
-// 성향 분석 함수
-func analyzeTendency(score float64) string {
-	if score >= 80 {
-		return "매우 높음"
-	} else if score >= 60 {
-		return "높음"
-	} else if score >= 40 {
-		return "보통"
-	} else if score >= 20 {
-		return "낮음"
-	}
-	return "매우 낮음"
-}
-
 // CreateOrUpdateUserProfile 사용자 프로필 생성/수정
 // POST /users/profile
 type CreateUserProfileRequest struct {
-	UserID           uint    `json:"user_id" validate:"required"`
+	UserID           uint    `json:"user_id"` // 선택, 지정 시 토큰 사용자와 일치해야 함
 	SocialityScore   float64 `json:"sociality_score"`
 	ActivityScore    float64 `json:"activity_score"`
 	IntimacyScore    float64 `json:"intimacy_score"`
@@ -116,6 +72,12 @@ func CreateOrUpdateUserProfile(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, err := resolveActingUser(c, req.UserID)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+	req.UserID = userID
+
 	// 사용자 존재 확인
 	var user models.User
 	if err := database.DB.First(&user, req.UserID).Error; err != nil {
@@ -148,6 +110,7 @@ func CreateOrUpdateUserProfile(c *fiber.Ctx) error {
 				"error":   "Failed to create profile",
 			})
 		}
+		services.IndexUserProfile(&profile)
 
 		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 			"success": true,
@@ -171,6 +134,7 @@ func CreateOrUpdateUserProfile(c *fiber.Ctx) error {
 			"error":   "Failed to update profile",
 		})
 	}
+	services.IndexUserProfile(&profile)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"success": true,
@@ -183,34 +147,42 @@ func CreateOrUpdateUserProfile(c *fiber.Ctx) error {
 func GetUserProfile(c *fiber.Ctx) error {
 	userID := c.Params("id")
 
+	// 유사 사용자 척도 (?metric=, 미지정 시 SIMILARITY_METRIC)
+	metric, err := resolveMetricParam(c.Query("metric"))
+	if err != nil {
+		return metricErrorResponse(c, err)
+	}
+
 	var profile models.UserProfile
-	err := database.DB.Preload("User").Where("user_id = ?", userID).First(&profile).Error
+	err = database.DB.Preload("User").Where("user_id = ?", userID).First(&profile).Error
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Profile not found",
 		})
 	}
 
-	// 사용자 성향 분석
+	// 사용자 성향 분석 (요청 언어로 표시)
+	locale := middleware.GetLocale(c)
+	profile.ProfileType = services.LocalizeProfileType(profile.ProfileType, locale)
 	tendencies := fiber.Map{
-		"sociality":   fiber.Map{"score": profile.SocialityScore, "level": analyzeTendency(profile.SocialityScore)},
-		"activity":    fiber.Map{"score": profile.ActivityScore, "level": analyzeTendency(profile.ActivityScore)},
-		"intimacy":    fiber.Map{"score": profile.IntimacyScore, "level": analyzeTendency(profile.IntimacyScore)},
-		"immersion":   fiber.Map{"score": profile.ImmersionScore, "level": analyzeTendency(profile.ImmersionScore)},
-		"flexibility": fiber.Map{"score": profile.FlexibilityScore, "level": analyzeTendency(profile.FlexibilityScore)},
+		"sociality":   fiber.Map{"score": profile.SocialityScore, "level": services.DimensionLevel(profile.SocialityScore, locale)},
+		"activity":    fiber.Map{"score": profile.ActivityScore, "level": services.DimensionLevel(profile.ActivityScore, locale)},
+		"intimacy":    fiber.Map{"score": profile.IntimacyScore, "level": services.DimensionLevel(profile.IntimacyScore, locale)},
+		"immersion":   fiber.Map{"score": profile.ImmersionScore, "level": services.DimensionLevel(profile.ImmersionScore, locale)},
+		"flexibility": fiber.Map{"score": profile.FlexibilityScore, "level": services.DimensionLevel(profile.FlexibilityScore, locale)},
 	}
 
 	// 유사 사용자 추천 (70% 이상 유사도)
 	var uid uint
 	if _, err := fmt.Sscanf(userID, "%d", &uid); err == nil {
-		similarUsers, _ := services.GetSimilarUsers(uid, 20) // 상위 20명
+		similarUsers, _ := services.GetSimilarUsers(uid, 20, metric) // 상위 20명
 
 		// 클럽 추천 (유사한 멤버들이 있는 클럽 우선)
-		recommendedClubs, _ := services.GetClubsWithSimilarMembers(uid, 10)
+		recommendedClubs, _ := services.RecommendClubsWithSimilarMembers(services.UserRespondent(uid), 10)
 
 		// 만약 유사 멤버 기반 클럽이 부족하면 성향 기반 클럽 추가
 		if len(recommendedClubs) < 5 {
-			additionalClubs, _ := services.GetRecommendedClubs(uid, 10)
+			additionalClubs, _ := services.RecommendClubs(services.UserRespondent(uid), 10)
 			recommendedClubs = append(recommendedClubs, additionalClubs...)
 		}
 
@@ -236,18 +208,14 @@ func GetUserProfile(c *fiber.Ctx) error {
 
 // 자동 매칭 - 추천 모임 중 랜덤으로 자동 가입
 func AutoMatchClubs(c *fiber.Ctx) error {
-	userID := c.Params("id")
-
-	var uid uint
-	if _, err := fmt.Sscanf(userID, "%d", &uid); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+	uid, err := resolveActingUserParam(c, "id")
+	if err != nil {
+		return authErrorResponse(c, err)
 	}
 
 	// 사용자가 설문을 완료했는지 확인
 	var profile models.UserProfile
-	err := database.DB.Where("user_id = ?", uid).First(&profile).Error
+	err = database.DB.Where("user_id = ?", uid).First(&profile).Error
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User profile not found. Please complete the survey first.",
@@ -255,10 +223,10 @@ func AutoMatchClubs(c *fiber.Ctx) error {
 	}
 
 	// 추천 클럽 가져오기 (유사 멤버 기반 + 성향 기반)
-	recommendedClubs, err := services.GetClubsWithSimilarMembers(uid, 20)
+	recommendedClubs, err := services.RecommendClubsWithSimilarMembers(services.UserRespondent(uid), 20)
 	if err != nil || len(recommendedClubs) < 5 {
 		// 추가 클럽 가져오기
-		additionalClubs, _ := services.GetRecommendedClubs(uid, 20)
+		additionalClubs, _ := services.RecommendClubs(services.UserRespondent(uid), 20)
 		recommendedClubs = append(recommendedClubs, additionalClubs...)
 	}
 
@@ -268,92 +236,95 @@ func AutoMatchClubs(c *fiber.Ctx) error {
 		})
 	}
 
-	// 랜덤 인원수 결정 (1~5명, 추천 클럽 수를 초과하지 않도록)
+	// 랜덤 클럽 수 결정 (1~5개, 추천 클럽 수를 초과하지 않도록)
+	candidates := services.RankedCandidates(recommendedClubs)
 	rand.Seed(time.Now().UnixNano())
-	maxClubs := len(recommendedClubs)
+	maxClubs := len(candidates)
 	if maxClubs > 5 {
 		maxClubs = 5
 	}
 	numClubsToJoin := rand.Intn(maxClubs) + 1 // 1부터 maxClubs까지
 
-	// 추천 클럽 셔플
-	rand.Shuffle(len(recommendedClubs), func(i, j int) {
-		recommendedClubs[i], recommendedClubs[j] = recommendedClubs[j], recommendedClubs[i]
-	})
-
-	// 랜덤으로 선택된 클럽들에 자동 가입
-	var joinedClubs []models.Club
-	var alreadyMember []models.Club
-
-	for i := 0; i < numClubsToJoin && i < len(recommendedClubs); i++ {
-		club := recommendedClubs[i]
-
-		// 이미 가입된 클럽인지 확인
-		var existingMember models.ClubMember
-		checkErr := database.DB.Where("club_id = ? AND user_id = ?", club.ID, uid).First(&existingMember).Error
-
-		if checkErr == nil {
-			// 이미 가입된 경우
-			alreadyMember = append(alreadyMember, club)
-			continue
-		}
+	// 추천 순서대로 정원과 카테고리 제약을 지키며 가입 (?dry_run=true면 계획만)
+	dryRun := c.QueryBool("dry_run", false)
+	plan, err := services.AssignClubs([]services.AssignmentUnit{{
+		Key:        fmt.Sprintf("user:%d", uid),
+		UserIDs:    []uint{uid},
+		Candidates: candidates,
+		MaxClubs:   numClubsToJoin,
+	}}, dryRun)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to join clubs",
+			"details": err.Error(),
+		})
+	}
 
-		// 클럽 가입
-		newMember := models.ClubMember{
-			ClubID:   club.ID,
-			UserID:   uid,
-			JoinedAt: time.Now(),
-		}
+	clubByID := make(map[uint]models.Club, len(recommendedClubs))
+	for _, club := range recommendedClubs {
+		clubByID[club.ID] = club
+	}
 
-		if err := database.DB.Create(&newMember).Error; err != nil {
-			continue // 에러 발생시 다음 클럽으로
+	joinedClubs := []models.Club{}
+	for _, join := range plan.Units[0].Clubs {
+		joinedClubs = append(joinedClubs, clubByID[join.ClubID])
+	}
+	alreadyMember := []models.Club{}
+	for _, skipped := range plan.Units[0].Skipped {
+		if skipped.Reason == services.SkipAlreadyMember {
+			alreadyMember = append(alreadyMember, clubByID[skipped.ClubID])
 		}
-
-		// 멤버 수 증가
-		database.DB.Model(&models.Club{}).Where("id = ?", club.ID).Update("member_count", club.MemberCount+1)
-
-		joinedClubs = append(joinedClubs, club)
 	}
 
-	// 가입된 클럽이 없는 경우 (모두 이미 가입되어 있었던 경우)
-	if len(joinedClubs) == 0 && len(alreadyMember) > 0 {
+	// 가입된 클럽이 없는 경우 (모두 이미 가입했거나 자리가 없는 경우)
+	if len(joinedClubs) == 0 {
 		return c.JSON(fiber.Map{
 			"success": true,
-			"message": "Already member of selected clubs",
+			"message": "No club available to join",
 			"data": fiber.Map{
-				"joined_clubs":         []models.Club{},
+				"joined_clubs":         joinedClubs,
 				"already_member_clubs": alreadyMember,
 				"attempted_count":      numClubsToJoin,
+				"dry_run":              dryRun,
+				"plan":                 plan,
 			},
 		})
 	}
 
+	message := fmt.Sprintf("Successfully joined %d club(s)", len(joinedClubs))
+	if dryRun {
+		message = fmt.Sprintf("Would join %d club(s)", len(joinedClubs))
+	}
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": fmt.Sprintf("Successfully joined %d club(s)", len(joinedClubs)),
+		"message": message,
 		"data": fiber.Map{
 			"joined_clubs":         joinedClubs,
 			"already_member_clubs": alreadyMember,
 			"attempted_count":      numClubsToJoin,
-			"total_recommended":    len(recommendedClubs),
+			"total_recommended":    len(candidates),
+			"dry_run":              dryRun,
+			"plan":                 plan,
 		},
 	})
 }
 
 // 그룹 자동 매칭 - 유사한 성향의 사용자들과 함께 클럽에 가입
 func AutoMatchWithSimilarUsers(c *fiber.Ctx) error {
-	userID := c.Params("id")
+	uid, err := resolveActingUserParam(c, "id")
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
 
-	var uid uint
-	if _, err := fmt.Sscanf(userID, "%d", &uid); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+	// 유사 사용자 척도 (?metric=, 미지정 시 SIMILARITY_METRIC)
+	metric, err := resolveMetricParam(c.Query("metric"))
+	if err != nil {
+		return metricErrorResponse(c, err)
 	}
 
 	// 사용자가 설문을 완료했는지 확인
 	var profile models.UserProfile
-	err := database.DB.Where("user_id = ?", uid).First(&profile).Error
+	err = database.DB.Where("user_id = ?", uid).First(&profile).Error
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User profile not found. Please complete the survey first.",
@@ -361,7 +332,7 @@ func AutoMatchWithSimilarUsers(c *fiber.Ctx) error {
 	}
 
 	// 유사한 사용자 찾기 (유사도 70% 이상, 최대 20명)
-	similarUsers, err := services.GetSimilarUsers(uid, 20)
+	similarUsers, err := services.GetSimilarUsers(uid, 20, metric)
 	if err != nil || len(similarUsers) == 0 {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "No similar users found for group matching",
@@ -387,9 +358,9 @@ func AutoMatchWithSimilarUsers(c *fiber.Ctx) error {
 	}
 
 	// 그룹이 함께 들어갈 추천 클럽 찾기
-	recommendedClubs, err := services.GetClubsWithSimilarMembers(uid, 20)
+	recommendedClubs, err := services.RecommendClubsWithSimilarMembers(services.UserRespondent(uid), 20)
 	if err != nil || len(recommendedClubs) < 5 {
-		additionalClubs, _ := services.GetRecommendedClubs(uid, 20)
+		additionalClubs, _ := services.RecommendClubs(services.UserRespondent(uid), 20)
 		recommendedClubs = append(recommendedClubs, additionalClubs...)
 	}
 
@@ -399,95 +370,121 @@ func AutoMatchWithSimilarUsers(c *fiber.Ctx) error {
 		})
 	}
 
-	// 추천 클럽 셔플
-	rand.Shuffle(len(recommendedClubs), func(i, j int) {
-		recommendedClubs[i], recommendedClubs[j] = recommendedClubs[j], recommendedClubs[i]
-	})
-
-	// 1~3개의 클럽에 그룹 전체를 가입시키기
+	// 1~3개의 클럽에 그룹 전체를 가입시키기 (추천 순서대로, 그룹 전체가 들어갈 자리가 있는 클럽만)
+	candidates := services.RankedCandidates(recommendedClubs)
 	numClubsToJoin := rand.Intn(3) + 1 // 1~3개
-	if numClubsToJoin > len(recommendedClubs) {
-		numClubsToJoin = len(recommendedClubs)
+	if numClubsToJoin > len(candidates) {
+		numClubsToJoin = len(candidates)
+	}
+
+	dryRun := c.QueryBool("dry_run", false)
+	plan, err := services.AssignClubs([]services.AssignmentUnit{{
+		Key:        fmt.Sprintf("group:%d", uid),
+		UserIDs:    selectedUsers,
+		Candidates: candidates,
+		MaxClubs:   numClubsToJoin,
+	}}, dryRun)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to join clubs",
+			"details": err.Error(),
+		})
+	}
+
+	clubByID := make(map[uint]models.Club, len(recommendedClubs))
+	for _, club := range recommendedClubs {
+		clubByID[club.ID] = club
 	}
 
 	type JoinResult struct {
 		Club         models.Club `json:"club"`
 		JoinedUsers  []uint      `json:"joined_users"`
-		SkippedUsers []uint      `json:"skipped_users"` // 이미 가입된 사용자
+		SkippedUsers []uint      `json:"skipped_users"` // 이미 가입했거나 같은 카테고리 클럽에 가입된 사용자
 	}
 
 	var results []JoinResult
-
-	for clubIdx := 0; clubIdx < numClubsToJoin; clubIdx++ {
-		club := recommendedClubs[clubIdx]
-		var joinedUsers []uint
-		var skippedUsers []uint
-
-		// 그룹의 각 사용자를 클럽에 가입시키기
-		for _, userID := range selectedUsers {
-			// 이미 가입된 클럽인지 확인
-			var existingMember models.ClubMember
-			checkErr := database.DB.Where("club_id = ? AND user_id = ?", club.ID, userID).First(&existingMember).Error
-
-			if checkErr == nil {
-				skippedUsers = append(skippedUsers, userID)
-				continue
-			}
-
-			// 클럽 가입
-			newMember := models.ClubMember{
-				ClubID:   club.ID,
-				UserID:   userID,
-				JoinedAt: time.Now(),
-			}
-
-			if err := database.DB.Create(&newMember).Error; err != nil {
-				skippedUsers = append(skippedUsers, userID)
-				continue
-			}
-
-			joinedUsers = append(joinedUsers, userID)
-		}
-
-		// 멤버 수 업데이트
-		if len(joinedUsers) > 0 {
-			database.DB.Model(&models.Club{}).Where("id = ?", club.ID).
-				UpdateColumn("member_count", club.MemberCount+len(joinedUsers))
-
-			results = append(results, JoinResult{
-				Club:         club,
-				JoinedUsers:  joinedUsers,
-				SkippedUsers: skippedUsers,
-			})
-		}
+	for _, join := range plan.Units[0].Clubs {
+		results = append(results, JoinResult{
+			Club:         clubByID[join.ClubID],
+			JoinedUsers:  join.JoinedUsers,
+			SkippedUsers: append(append([]uint{}, join.AlreadyMembers...), join.CategoryConflicts...),
+		})
 	}
 
 	if len(results) == 0 {
 		return c.JSON(fiber.Map{
 			"success": false,
-			"message": "All users were already members of selected clubs",
+			"message": "No club has room for the whole group",
 			"data": fiber.Map{
 				"group_users":     selectedUsers,
 				"attempted_clubs": numClubsToJoin,
+				"dry_run":         dryRun,
+				"plan":            plan,
 			},
 		})
 	}
 
+	message := fmt.Sprintf("Successfully matched group to %d club(s)", len(results))
+	if dryRun {
+		message = fmt.Sprintf("Would match group to %d club(s)", len(results))
+	}
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": fmt.Sprintf("Successfully matched group to %d club(s)", len(results)),
+		"message": message,
 		"data": fiber.Map{
 			"group_size":      len(selectedUsers),
 			"group_users":     selectedUsers,
 			"matched_clubs":   results,
 			"total_attempted": numClubsToJoin,
+			"dry_run":         dryRun,
+			"plan":            plan,
 		},
 	})
 }
 
-// 전체 사용자 그룹 매칭 - 비슷한 성향의 사용자들을 그룹화하여 클럽에 매칭
+// 전체 사용자 그룹 매칭 - 비슷한 성향의 사용자들을 그룹화하여 클럽에 매칭 (dry_run이 아니면 202와 작업 ID 반환)
+// 본문(선택): {"k": 0, "max_group_size": 8, "seed": 42, "dry_run": false}
 func MatchAllUsersToClubs(c *fiber.Ctx) error {
-	err := services.MatchUsersToClubs()
+	var opts services.MatchOptions
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&opts); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if opts.K < 0 || opts.MaxGroupSize < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "k and max_group_size must not be negative",
+		})
+	}
+
+	// 실제 매칭은 백그라운드 작업으로 실행 (작업 실행기가 꺼진 인스턴스에서는 요청 안에서 실행)
+	if !opts.DryRun && services.GlobalJobRunner.Running() {
+		job, err := services.EnqueueJob(services.JobRematch, opts)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Failed to start matching job",
+				"details": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"success": true,
+			"message": "Matching started in background",
+			"data": fiber.Map{
+				"job":        job,
+				"status_url": jobStatusURL(job.ID),
+			},
+		})
+	}
+
+	report, err := services.MatchUsersToClubs(opts)
+	if errors.Is(err, utils.ErrClusterCapacity) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Too few groups for max_group_size",
+			"details": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to match users to clubs",
@@ -502,12 +499,17 @@ func MatchAllUsersToClubs(c *fiber.Ctx) error {
 	var totalClubs int64
 	database.DB.Model(&models.Club{}).Where("member_count > 0").Count(&totalClubs)
 
+	message := "Successfully matched users to clubs based on similar profiles"
+	if opts.DryRun {
+		message = "Proposed club assignment (dry run, nothing saved)"
+	}
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Successfully matched users to clubs based on similar profiles",
+		"message": message,
 		"data": fiber.Map{
 			"total_memberships": totalMembers,
 			"active_clubs":      totalClubs,
+			"clustering":        report,
 		},
 	})
 }
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"ongi-back/database"
+	"ongi-back/middleware"
 	"ongi-back/models"
 	"ongi-back/services"
 	"strconv"
@@ -12,6 +16,7 @@ import (
 )
 
 // WebSocketHandler WebSocket 연결 핸들러
+// 인증은 앞단의 middleware.RequireAuth에서 처리된다 (?token= 쿼리 허용)
 func WebSocketHandler(c *fiber.Ctx) error {
 	// WebSocket 업그레이드
 	if websocket.IsWebSocketUpgrade(c) {
@@ -23,9 +28,8 @@ func WebSocketHandler(c *fiber.Ctx) error {
 
 // HandleWebSocket WebSocket 연결 처리
 func HandleWebSocket(c *websocket.Conn) {
-	// URL 파라미터에서 roomID와 userID 가져오기
+	// URL 파라미터에서 roomID, 토큰에서 userID 가져오기
 	roomIDStr := c.Params("roomId")
-	userIDStr := c.Query("user_id")
 
 	roomID, err := strconv.ParseUint(roomIDStr, 10, 32)
 	if err != nil {
@@ -34,9 +38,16 @@ func HandleWebSocket(c *websocket.Conn) {
 		return
 	}
 
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		log.Printf("Invalid user ID: %v", err)
+	userID := middleware.GetWebSocketUserID(c)
+	if userID == 0 {
+		log.Printf("Unauthenticated WebSocket connection: roomID=%d", roomID)
+		c.Close()
+		return
+	}
+
+	// user_id 쿼리는 하위 호환용이며, 지정 시 토큰 사용자와 일치해야 함
+	if userIDStr := c.Query("user_id"); userIDStr != "" && userIDStr != strconv.FormatUint(uint64(userID), 10) {
+		log.Printf("WebSocket user_id mismatch: token=%d, query=%s", userID, userIDStr)
 		c.Close()
 		return
 	}
@@ -50,12 +61,17 @@ func HandleWebSocket(c *websocket.Conn) {
 	}
 
 	// 클라이언트 생성
-	client := &services.Client{
-		Hub:    services.GlobalHub,
-		Conn:   c,
-		Send:   make(chan []byte, 256),
-		UserID: uint(userID),
-		RoomID: uint(roomID),
+	client := services.NewClient(services.GlobalHub, c, userID, uint(roomID), []uint{uint(roomID)})
+
+	// 재연결: last_seq 또는 last_message_id 이후 놓친 메시지와 멤버 이벤트를 먼저 전송
+	cursors, err := replayCursors(c, []uint{uint(roomID)})
+	if err != nil {
+		log.Printf("Invalid WebSocket replay cursor: roomID=%d, userID=%d: %v", roomID, userID, err)
+		c.Close()
+		return
+	}
+	if cursors != nil {
+		client.ReplayFrom(cursors)
 	}
 
 	// Hub에 등록
@@ -65,25 +81,129 @@ func HandleWebSocket(c *websocket.Conn) {
 	client.Hub.BroadcastMessage(
 		uint(roomID),
 		"member_online",
-		uint(userID),
+		userID,
 		fiber.Map{
 			"user_id": userID,
 			"status":  "online",
 		},
 	)
 
-	// 고루틴 시작
-	go client.WritePump()
-	client.ReadPump()
+	// 연결이 끝날 때까지 읽기/쓰기
+	client.Serve()
 
 	// 퇴장 메시지 브로드캐스트
 	client.Hub.BroadcastMessage(
 		uint(roomID),
 		"member_offline",
-		uint(userID),
+		userID,
 		fiber.Map{
 			"user_id": userID,
 			"status":  "offline",
 		},
 	)
 }
+
+// HandleUserWebSocket 사용자의 모든 채팅방을 하나의 연결로 처리 (/ws)
+// 연결 시 멤버인 채팅방을 모두 구독하고 subscribed 프레임으로 채팅방 목록을 보낸다.
+// 이후 채팅방에 들어가거나 나가면 room_added/room_removed, 새 메시지와 읽음은 room_update로 알린다.
+func HandleUserWebSocket(c *websocket.Conn) {
+	userID := middleware.GetWebSocketUserID(c)
+	if userID == 0 {
+		log.Printf("Unauthenticated WebSocket connection")
+		c.Close()
+		return
+	}
+
+	roomIDs := []uint{}
+	if err := database.DB.Model(&models.ChatRoomMember{}).
+		Where("user_id = ?", userID).
+		Order("chat_room_id").
+		Pluck("chat_room_id", &roomIDs).Error; err != nil {
+		log.Printf("Failed to load chat rooms: userID=%d: %v", userID, err)
+		c.Close()
+		return
+	}
+
+	client := services.NewClient(services.GlobalHub, c, userID, 0, roomIDs)
+
+	// 재연결: last_message_id 이후 각 채팅방의 놓친 메시지와 멤버 이벤트를 먼저 전송
+	cursors, err := replayCursors(c, roomIDs)
+	if err != nil {
+		log.Printf("Invalid WebSocket replay cursor: userID=%d: %v", userID, err)
+		c.Close()
+		return
+	}
+	if cursors != nil {
+		client.ReplayFrom(cursors)
+	}
+
+	// 구독 목록은 등록 전에 넣어 두어 첫 프레임으로 전송
+	subscribed, err := json.Marshal(&services.Message{
+		Type:   "subscribed",
+		UserID: userID,
+		Data:   fiber.Map{"room_ids": roomIDs},
+	})
+	if err == nil {
+		client.Send <- subscribed
+	}
+
+	client.Hub.Register <- client
+
+	// 입장 알림
+	for _, roomID := range roomIDs {
+		client.Hub.BroadcastMessage(roomID, "member_online", userID, fiber.Map{
+			"user_id": userID,
+			"status":  "online",
+		})
+	}
+
+	client.Serve()
+
+	// 퇴장 알림 (연결할 때 구독한 채팅방 기준)
+	for _, roomID := range roomIDs {
+		client.Hub.BroadcastMessage(roomID, "member_offline", userID, fiber.Map{
+			"user_id": userID,
+			"status":  "offline",
+		})
+	}
+}
+
+// replayCursors 재연결 쿼리로 채팅방별 재전송 시작 순번 (쿼리가 없으면 nil)
+// last_seq는 채팅방 하나에 연결할 때만 쓸 수 있고, last_message_id는 메시지 ID로 채팅방마다 순번을 찾는다.
+func replayCursors(c *websocket.Conn, roomIDs []uint) (map[uint]int64, error) {
+	if lastSeqStr := c.Query("last_seq"); lastSeqStr != "" {
+		if len(roomIDs) != 1 {
+			return nil, errors.New("last_seq is only supported on /ws/chat/:roomId")
+		}
+		lastSeq, err := strconv.ParseInt(lastSeqStr, 10, 64)
+		if err != nil || lastSeq < 0 {
+			return nil, fmt.Errorf("invalid last_seq %q", lastSeqStr)
+		}
+		return map[uint]int64{roomIDs[0]: lastSeq}, nil
+	}
+
+	if lastMessageIDStr := c.Query("last_message_id"); lastMessageIDStr != "" {
+		lastMessageID, err := strconv.ParseUint(lastMessageIDStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid last_message_id %q", lastMessageIDStr)
+		}
+		return services.ReplayCursorsFromMessage(uint(lastMessageID), roomIDs)
+	}
+	return nil, nil
+}
+
+// AdminGetWebSocketStats 이 인스턴스의 WebSocket 연결 수와 전송 통계 (버린 프레임, 끊은 느린 연결 등)
+// GET /admin/websocket/stats
+func AdminGetWebSocketStats(c *fiber.Ctx) error {
+	if services.GlobalHub == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"success": false,
+			"error":   "WebSocket hub is not running",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    services.GlobalHub.Stats(),
+	})
+}
@@ -0,0 +1,85 @@
+// Package i18n API 응답 메시지 카탈로그와 언어 협상
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 지원 언어
+const (
+	LocaleKorean   = "ko"
+	LocaleEnglish  = "en"
+	LocaleJapanese = "ja"
+)
+
+// DefaultLocale 기본 언어 (DB에 저장되는 원문 텍스트의 언어)
+const DefaultLocale = LocaleKorean
+
+// SupportedLocales 지원 언어 목록 (협상 시 앞쪽이 우선)
+var SupportedLocales = []string{LocaleKorean, LocaleEnglish, LocaleJapanese}
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// catalogs 언어별 메시지 (키 -> 문구)
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	loaded := make(map[string]map[string]string, len(SupportedLocales))
+	for _, locale := range SupportedLocales {
+		data, err := localeFiles.ReadFile("locales/" + locale + ".yaml")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", locale, err))
+		}
+
+		messages := make(map[string]string)
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", locale, err))
+		}
+		loaded[locale] = messages
+	}
+	return loaded
+}
+
+// IsSupported 지원하는 언어인지 확인
+func IsSupported(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize 언어 태그를 지원 언어로 변환 (예: "en-US" -> "en"), 지원하지 않으면 빈 문자열
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i != -1 {
+		tag = tag[:i]
+	}
+	if IsSupported(tag) {
+		return tag
+	}
+	return ""
+}
+
+// T 메시지 키를 해당 언어로 변환 (없으면 기본 언어, 그래도 없으면 키 그대로)
+// args가 있으면 fmt.Sprintf 형식으로 채운다.
+func T(locale, key string, args ...interface{}) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"ongi-back/database"
+	"ongi-back/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireAdmin 관리자 권한 미들웨어 (RequireAuth 뒤에 사용)
+// 권한 변경이 즉시 반영되도록 토큰이 아닌 DB의 사용자 권한을 확인한다.
+func RequireAdmin() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := GetUserID(c)
+		if userID == 0 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Authorization token is required",
+			})
+		}
+
+		var user models.User
+		if err := database.DB.Select("id", "role", "is_banned").First(&user, userID).Error; err != nil ||
+			user.Role != models.RoleAdmin || user.IsBanned {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   "Admin permission required",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"strings"
+
+	"ongi-back/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// ClaimsKey c.Locals에 저장되는 JWT 클레임 키
+const ClaimsKey = "claims"
+
+// RequireAuth JWT 인증 미들웨어
+// Authorization: Bearer {token} 헤더를 검증하고 클레임을 c.Locals에 저장한다.
+// WebSocket 업그레이드 요청은 브라우저가 헤더를 설정할 수 없으므로 ?token= 쿼리도 허용한다.
+func RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tokenString := extractToken(c)
+		if tokenString == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Authorization token is required",
+			})
+		}
+
+		claims, err := utils.ValidateJWT(tokenString)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid or expired token",
+			})
+		}
+
+		c.Locals(ClaimsKey, claims)
+		return c.Next()
+	}
+}
+
+// GetClaims 요청 컨텍스트에서 인증된 사용자의 클레임 조회
+func GetClaims(c *fiber.Ctx) *utils.Claims {
+	claims, _ := c.Locals(ClaimsKey).(*utils.Claims)
+	return claims
+}
+
+// GetUserID 요청 컨텍스트에서 인증된 사용자 ID 조회 (인증되지 않은 경우 0)
+func GetUserID(c *fiber.Ctx) uint {
+	if claims := GetClaims(c); claims != nil {
+		return claims.UserID
+	}
+	return 0
+}
+
+// GetWebSocketUserID WebSocket 연결에서 인증된 사용자 ID 조회 (인증되지 않은 경우 0)
+func GetWebSocketUserID(c *websocket.Conn) uint {
+	if claims, ok := c.Locals(ClaimsKey).(*utils.Claims); ok && claims != nil {
+		return claims.UserID
+	}
+	return 0
+}
+
+// extractToken Authorization 헤더 또는 (WebSocket인 경우) token 쿼리에서 토큰 추출
+func extractToken(c *fiber.Ctx) string {
+	authHeader := c.Get(fiber.HeaderAuthorization)
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+	}
+
+	if websocket.IsWebSocketUpgrade(c) {
+		return c.Query("token")
+	}
+
+	return ""
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"ongi-back/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocaleKey c.Locals에 저장되는 응답 언어 키
+const LocaleKey = "locale"
+
+// Locale 응답 언어 결정 미들웨어
+// ?lang= 쿼리가 우선이고, 없으면 Accept-Language 헤더로 협상한다. 지원하지 않으면 기본 언어(ko).
+func Locale() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		locale := i18n.Normalize(c.Query("lang"))
+		if locale == "" {
+			locale = c.AcceptsLanguages(i18n.SupportedLocales...)
+		}
+		if locale == "" {
+			locale = i18n.DefaultLocale
+		}
+
+		c.Locals(LocaleKey, locale)
+		c.Set(fiber.HeaderContentLanguage, locale)
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
+
+// GetLocale 요청의 응답 언어 (Locale 미들웨어가 없으면 기본 언어)
+func GetLocale(c *fiber.Ctx) string {
+	if locale, ok := c.Locals(LocaleKey).(string); ok && locale != "" {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"ongi-back/database"
 	"ongi-back/models"
+	"time"
 )
 
 // SeedQuestions - 초기 설문 데이터 생성 (예/아니오 형식)
@@ -15,11 +16,11 @@ func SeedQuestions() error {
 			Order:        1,
 			Category:     "sociality",
 			Options: []models.Option{
-				{OptionText: "항상 먼저 말을 건다", Score: 5, Weight: "sociality"},
-				{OptionText: "자주 먼저 말을 거는 편이다", Score: 4, Weight: "sociality"},
-				{OptionText: "상황에 따라 다르다", Score: 3, Weight: "flexibility"},
-				{OptionText: "거의 말을 걸지 않는다", Score: 2, Weight: "intimacy"},
-				{OptionText: "전혀 먼저 말을 걸지 않는다", Score: 1, Weight: "intimacy"},
+				{OptionText: "항상 먼저 말을 건다", Weights: models.DimensionWeights{"sociality": 5}},
+				{OptionText: "자주 먼저 말을 거는 편이다", Weights: models.DimensionWeights{"sociality": 4}},
+				{OptionText: "상황에 따라 다르다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "거의 말을 걸지 않는다", Weights: models.DimensionWeights{"intimacy": 2}},
+				{OptionText: "전혀 먼저 말을 걸지 않는다", Weights: models.DimensionWeights{"intimacy": 1}},
 			},
 		},
 		{
@@ -27,11 +28,11 @@ func SeedQuestions() error {
 			Order:        2,
 			Category:     "sociality",
 			Options: []models.Option{
-				{OptionText: "항상 소규모 모임을 선호한다", Score: 1, Weight: "intimacy"},
-				{OptionText: "대체로 소규모 모임을 선호한다", Score: 2, Weight: "intimacy"},
-				{OptionText: "상황에 따라 다르다", Score: 3, Weight: "flexibility"},
-				{OptionText: "큰 모임이 더 좋다", Score: 4, Weight: "sociality"},
-				{OptionText: "큰 모임을 매우 선호한다", Score: 5, Weight: "sociality"},
+				{OptionText: "항상 소규모 모임을 선호한다", Weights: models.DimensionWeights{"intimacy": 1}},
+				{OptionText: "대체로 소규모 모임을 선호한다", Weights: models.DimensionWeights{"intimacy": 2}},
+				{OptionText: "상황에 따라 다르다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "큰 모임이 더 좋다", Weights: models.DimensionWeights{"sociality": 4}},
+				{OptionText: "큰 모임을 매우 선호한다", Weights: models.DimensionWeights{"sociality": 5}},
 			},
 		},
 		{
@@ -39,11 +40,11 @@ func SeedQuestions() error {
 			Order:        3,
 			Category:     "intimacy",
 			Options: []models.Option{
-				{OptionText: "많은 사람들과 넓은 인맥을 유지하는 것", Score: 5, Weight: "sociality"},
-				{OptionText: "다양한 친구들과 즐거운 시간을 보내는 것", Score: 4, Weight: "activity"},
-				{OptionText: "상황에 맞게 다양한 관계를 유지하는 것", Score: 3, Weight: "flexibility"},
-				{OptionText: "소수의 친구들과 깊은 대화를 나누는 것", Score: 2, Weight: "intimacy"},
-				{OptionText: "오랜 시간 함께한 친구와의 신뢰", Score: 1, Weight: "intimacy"},
+				{OptionText: "많은 사람들과 넓은 인맥을 유지하는 것", Weights: models.DimensionWeights{"sociality": 5}},
+				{OptionText: "다양한 친구들과 즐거운 시간을 보내는 것", Weights: models.DimensionWeights{"activity": 4}},
+				{OptionText: "상황에 맞게 다양한 관계를 유지하는 것", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "소수의 친구들과 깊은 대화를 나누는 것", Weights: models.DimensionWeights{"intimacy": 2}},
+				{OptionText: "오랜 시간 함께한 친구와의 신뢰", Weights: models.DimensionWeights{"intimacy": 1}},
 			},
 		},
 		{
@@ -51,11 +52,11 @@ func SeedQuestions() error {
 			Order:        4,
 			Category:     "immersion",
 			Options: []models.Option{
-				{OptionText: "시간 가는 줄 모르고 완전히 몰입한다", Score: 5, Weight: "immersion"},
-				{OptionText: "집중해서 완성도 높게 끝낸다", Score: 4, Weight: "immersion"},
-				{OptionText: "집중하다가 다른 일도 병행한다", Score: 3, Weight: "flexibility"},
-				{OptionText: "여러 가지를 동시에 처리하는 편이다", Score: 2, Weight: "activity"},
-				{OptionText: "짧게 집중하고 다른 일로 전환한다", Score: 1, Weight: "activity"},
+				{OptionText: "시간 가는 줄 모르고 완전히 몰입한다", Weights: models.DimensionWeights{"immersion": 5}},
+				{OptionText: "집중해서 완성도 높게 끝낸다", Weights: models.DimensionWeights{"immersion": 4}},
+				{OptionText: "집중하다가 다른 일도 병행한다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "여러 가지를 동시에 처리하는 편이다", Weights: models.DimensionWeights{"activity": 2}},
+				{OptionText: "짧게 집중하고 다른 일로 전환한다", Weights: models.DimensionWeights{"activity": 1}},
 			},
 		},
 		{
@@ -63,11 +64,11 @@ func SeedQuestions() error {
 			Order:        5,
 			Category:     "flexibility",
 			Options: []models.Option{
-				{OptionText: "즐겁게 받아들이고 새로운 계획을 세운다", Score: 5, Weight: "flexibility"},
-				{OptionText: "빠르게 적응하고 대처한다", Score: 4, Weight: "flexibility"},
-				{OptionText: "약간 당황하지만 적응한다", Score: 3, Weight: "flexibility"},
-				{OptionText: "원래 계획을 유지하려고 노력한다", Score: 2, Weight: "immersion"},
-				{OptionText: "스트레스를 받고 불편해한다", Score: 1, Weight: "immersion"},
+				{OptionText: "즐겁게 받아들이고 새로운 계획을 세운다", Weights: models.DimensionWeights{"flexibility": 5}},
+				{OptionText: "빠르게 적응하고 대처한다", Weights: models.DimensionWeights{"flexibility": 4}},
+				{OptionText: "약간 당황하지만 적응한다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "원래 계획을 유지하려고 노력한다", Weights: models.DimensionWeights{"immersion": 2}},
+				{OptionText: "스트레스를 받고 불편해한다", Weights: models.DimensionWeights{"immersion": 1}},
 			},
 		},
 		{
@@ -75,11 +76,11 @@ func SeedQuestions() error {
 			Order:        6,
 			Category:     "activity",
 			Options: []models.Option{
-				{OptionText: "여러 명이 모여 액티비티를 즐긴다", Score: 5, Weight: "activity"},
-				{OptionText: "친구들과 외출하거나 새로운 곳을 탐험한다", Score: 4, Weight: "activity"},
-				{OptionText: "기분에 따라 외출하거나 집에서 쉰다", Score: 3, Weight: "flexibility"},
-				{OptionText: "소수의 친구들과 조용히 만난다", Score: 2, Weight: "intimacy"},
-				{OptionText: "집에서 혼자만의 시간을 보낸다", Score: 1, Weight: "intimacy"},
+				{OptionText: "여러 명이 모여 액티비티를 즐긴다", Weights: models.DimensionWeights{"activity": 5}},
+				{OptionText: "친구들과 외출하거나 새로운 곳을 탐험한다", Weights: models.DimensionWeights{"activity": 4}},
+				{OptionText: "기분에 따라 외출하거나 집에서 쉰다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "소수의 친구들과 조용히 만난다", Weights: models.DimensionWeights{"intimacy": 2}},
+				{OptionText: "집에서 혼자만의 시간을 보낸다", Weights: models.DimensionWeights{"intimacy": 1}},
 			},
 		},
 		{
@@ -87,11 +88,11 @@ func SeedQuestions() error {
 			Order:        7,
 			Category:     "sociality",
 			Options: []models.Option{
-				{OptionText: "리더 역할을 맡아 팀을 이끈다", Score: 5, Weight: "sociality"},
-				{OptionText: "적극적으로 의견을 제시하고 참여한다", Score: 4, Weight: "sociality"},
-				{OptionText: "상황에 따라 역할을 조절한다", Score: 3, Weight: "flexibility"},
-				{OptionText: "맡은 부분에 집중하며 완성도를 높인다", Score: 2, Weight: "immersion"},
-				{OptionText: "조용히 자신의 일을 처리한다", Score: 1, Weight: "immersion"},
+				{OptionText: "리더 역할을 맡아 팀을 이끈다", Weights: models.DimensionWeights{"sociality": 5}},
+				{OptionText: "적극적으로 의견을 제시하고 참여한다", Weights: models.DimensionWeights{"sociality": 4}},
+				{OptionText: "상황에 따라 역할을 조절한다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "맡은 부분에 집중하며 완성도를 높인다", Weights: models.DimensionWeights{"immersion": 2}},
+				{OptionText: "조용히 자신의 일을 처리한다", Weights: models.DimensionWeights{"immersion": 1}},
 			},
 		},
 		{
@@ -99,11 +100,11 @@ func SeedQuestions() error {
 			Order:        8,
 			Category:     "sociality",
 			Options: []models.Option{
-				{OptionText: "먼저 다가가 대화를 시작한다", Score: 5, Weight: "sociality"},
-				{OptionText: "자연스럽게 소통한다", Score: 4, Weight: "sociality"},
-				{OptionText: "상황을 보고 행동한다", Score: 3, Weight: "flexibility"},
-				{OptionText: "상대방이 먼저 말을 걸기를 기다린다", Score: 2, Weight: "intimacy"},
-				{OptionText: "낯을 많이 가리는 편이다", Score: 1, Weight: "intimacy"},
+				{OptionText: "먼저 다가가 대화를 시작한다", Weights: models.DimensionWeights{"sociality": 5}},
+				{OptionText: "자연스럽게 소통한다", Weights: models.DimensionWeights{"sociality": 4}},
+				{OptionText: "상황을 보고 행동한다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "상대방이 먼저 말을 걸기를 기다린다", Weights: models.DimensionWeights{"intimacy": 2}},
+				{OptionText: "낯을 많이 가리는 편이다", Weights: models.DimensionWeights{"intimacy": 1}},
 			},
 		},
 		{
@@ -111,11 +112,11 @@ func SeedQuestions() error {
 			Order:        9,
 			Category:     "immersion",
 			Options: []models.Option{
-				{OptionText: "다양한 경험과 새로운 시도", Score: 5, Weight: "activity"},
-				{OptionText: "여러 가지를 조금씩 경험하는 것", Score: 4, Weight: "activity"},
-				{OptionText: "상황에 따라 깊이와 폭을 조절", Score: 3, Weight: "flexibility"},
-				{OptionText: "한 가지를 깊이 있게 파고드는 것", Score: 2, Weight: "immersion"},
-				{OptionText: "전문가 수준까지 도달하는 것", Score: 1, Weight: "immersion"},
+				{OptionText: "다양한 경험과 새로운 시도", Weights: models.DimensionWeights{"activity": 5}},
+				{OptionText: "여러 가지를 조금씩 경험하는 것", Weights: models.DimensionWeights{"activity": 4}},
+				{OptionText: "상황에 따라 깊이와 폭을 조절", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "한 가지를 깊이 있게 파고드는 것", Weights: models.DimensionWeights{"immersion": 2}},
+				{OptionText: "전문가 수준까지 도달하는 것", Weights: models.DimensionWeights{"immersion": 1}},
 			},
 		},
 		{
@@ -123,18 +124,38 @@ func SeedQuestions() error {
 			Order:        10,
 			Category:     "intimacy",
 			Options: []models.Option{
-				{OptionText: "10명 이상의 큰 모임", Score: 5, Weight: "sociality"},
-				{OptionText: "5-10명 정도의 모임", Score: 4, Weight: "sociality"},
-				{OptionText: "상황에 따라 다르다", Score: 3, Weight: "flexibility"},
-				{OptionText: "2-4명의 소규모 모임", Score: 2, Weight: "intimacy"},
-				{OptionText: "1:1 만남이 가장 편하다", Score: 1, Weight: "intimacy"},
+				{OptionText: "10명 이상의 큰 모임", Weights: models.DimensionWeights{"sociality": 5}},
+				{OptionText: "5-10명 정도의 모임", Weights: models.DimensionWeights{"sociality": 4}},
+				{OptionText: "상황에 따라 다르다", Weights: models.DimensionWeights{"flexibility": 3}},
+				{OptionText: "2-4명의 소규모 모임", Weights: models.DimensionWeights{"intimacy": 2}},
+				{OptionText: "1:1 만남이 가장 편하다", Weights: models.DimensionWeights{"intimacy": 1}},
 			},
 		},
 	}
 
+	// 초기 질문은 1번 설문 버전으로 발행
+	var questionnaire models.Questionnaire
+	if err := database.DB.Where("version = ?", 1).First(&questionnaire).Error; err != nil {
+		now := time.Now()
+		questionnaire = models.Questionnaire{
+			Version:       1,
+			Title:         "온기 성향 설문",
+			Status:        models.QuestionnaireStatusPublished,
+			ScoringMethod: models.ScoringMethodAverage, // 옵션마다 차원 하나에 1-5점 (평균 방식)
+			PublishedAt:   &now,
+		}
+		if err := database.DB.Create(&questionnaire).Error; err != nil {
+			return err
+		}
+		log.Printf("Created questionnaire version %d", questionnaire.Version)
+	}
+
 	for _, question := range questions {
+		question.QuestionnaireID = questionnaire.ID
+		applySeedTranslations(&question)
+
 		var existingQuestion models.Question
-		result := database.DB.Where("\"order\" = ?", question.Order).First(&existingQuestion)
+		result := database.DB.Preload("Options").Where("questionnaire_id = ? AND \"order\" = ?", questionnaire.ID, question.Order).First(&existingQuestion)
 
 		if result.Error != nil {
 			// 질문이 없으면 생성
@@ -143,6 +164,10 @@ func SeedQuestions() error {
 			}
 			log.Printf("Created question %d: %s", question.Order, question.QuestionText)
 		} else {
+			// 번역문만 없는 기존 질문은 번역문 추가 (점수에 영향 없음)
+			if err := backfillSeedTranslations(&existingQuestion); err != nil {
+				return err
+			}
 			log.Printf("Question %d already exists, skipping", question.Order)
 		}
 	}
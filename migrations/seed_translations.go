@@ -0,0 +1,108 @@
+package migrations
+
+import (
+	"ongi-back/database"
+	"ongi-back/models"
+)
+
+// seedTranslations 초기 설문 질문/옵션의 번역문 (원문 -> 언어별 번역)
+var seedTranslations = map[string]models.Translations{
+	// 질문
+	"처음 만난 사람에게 먼저 말을 거는 편인가요?":     {"en": "Do you usually start conversations with people you've just met?", "ja": "初対面の人に自分から話しかける方ですか？"},
+	"큰 모임(10명 이상)보다 소규모 모임을 선호하나요?": {"en": "Do you prefer small gatherings over large ones (10+ people)?", "ja": "大人数（10人以上）より少人数の集まりが好きですか？"},
+	"친구들과의 관계에서 중요하게 생각하는 것은?":      {"en": "What matters most to you in friendships?", "ja": "友人関係で大切にしていることは？"},
+	"한 가지 일에 집중할 때 당신은?":            {"en": "When you focus on a task, you...", "ja": "一つのことに集中するとき、あなたは？"},
+	"갑자기 계획이 변경되면 당신은?":             {"en": "When plans suddenly change, you...", "ja": "急に予定が変わったとき、あなたは？"},
+	"주말에 선호하는 활동은?":                 {"en": "What do you like to do on weekends?", "ja": "週末に好きな過ごし方は？"},
+	"팀 프로젝트에서 당신의 스타일은?":            {"en": "What is your style in team projects?", "ja": "チームプロジェクトでのあなたのスタイルは？"},
+	"새로운 사람을 만날 때 당신은?":             {"en": "When you meet someone new, you...", "ja": "新しい人に会うとき、あなたは？"},
+	"취미 활동을 할 때 중요하게 생각하는 것은?":      {"en": "What matters most to you in a hobby?", "ja": "趣味活動で大切にしていることは？"},
+	"모임의 규모는 어느 정도가 가장 편한가요?":       {"en": "What group size feels most comfortable to you?", "ja": "どのくらいの規模の集まりが一番気楽ですか？"},
+
+	// 옵션
+	"항상 먼저 말을 건다":            {"en": "I always speak first", "ja": "いつも自分から話しかける"},
+	"자주 먼저 말을 거는 편이다":        {"en": "I often speak first", "ja": "よく自分から話しかける方だ"},
+	"상황에 따라 다르다":             {"en": "It depends on the situation", "ja": "状況によって違う"},
+	"거의 말을 걸지 않는다":           {"en": "I rarely speak first", "ja": "ほとんど話しかけない"},
+	"전혀 먼저 말을 걸지 않는다":        {"en": "I never speak first", "ja": "自分からはまったく話しかけない"},
+	"항상 소규모 모임을 선호한다":        {"en": "I always prefer small gatherings", "ja": "いつも少人数の集まりが好きだ"},
+	"대체로 소규모 모임을 선호한다":       {"en": "I mostly prefer small gatherings", "ja": "たいてい少人数の集まりが好きだ"},
+	"큰 모임이 더 좋다":             {"en": "I like large gatherings better", "ja": "大人数の集まりの方が好きだ"},
+	"큰 모임을 매우 선호한다":          {"en": "I strongly prefer large gatherings", "ja": "大人数の集まりがとても好きだ"},
+	"많은 사람들과 넓은 인맥을 유지하는 것":  {"en": "Keeping a wide network of many people", "ja": "多くの人と広い人脈を保つこと"},
+	"다양한 친구들과 즐거운 시간을 보내는 것": {"en": "Having fun with all kinds of friends", "ja": "いろいろな友人と楽しい時間を過ごすこと"},
+	"상황에 맞게 다양한 관계를 유지하는 것":  {"en": "Keeping different relationships to suit the situation", "ja": "状況に合わせていろいろな関係を保つこと"},
+	"소수의 친구들과 깊은 대화를 나누는 것":  {"en": "Having deep conversations with a few friends", "ja": "少数の友人と深い会話をすること"},
+	"오랜 시간 함께한 친구와의 신뢰":      {"en": "Trust with friends I've known for a long time", "ja": "長く付き合った友人との信頼"},
+	"시간 가는 줄 모르고 완전히 몰입한다":   {"en": "I get completely absorbed and lose track of time", "ja": "時間を忘れて完全に没頭する"},
+	"집중해서 완성도 높게 끝낸다":        {"en": "I focus and finish it to a high standard", "ja": "集中して完成度高く仕上げる"},
+	"집중하다가 다른 일도 병행한다":       {"en": "I focus, but also handle other things alongside", "ja": "集中しつつ他のことも並行する"},
+	"짧게 집중하고 다른 일로 전환한다":     {"en": "I focus briefly, then switch to something else", "ja": "短く集中して別のことに切り替える"},
+	"여러 가지를 동시에 처리하는 편이다":    {"en": "I tend to handle several things at once", "ja": "いくつものことを同時にこなす方だ"},
+	"즐겁게 받아들이고 새로운 계획을 세운다":  {"en": "I happily accept it and make a new plan", "ja": "楽しく受け入れて新しい計画を立てる"},
+	"빠르게 적응하고 대처한다":          {"en": "I adapt and respond quickly", "ja": "すぐに適応して対処する"},
+	"약간 당황하지만 적응한다":          {"en": "I'm a little flustered, but I adapt", "ja": "少し戸惑うが適応する"},
+	"원래 계획을 유지하려고 노력한다":      {"en": "I try to stick to the original plan", "ja": "元の計画を守ろうとする"},
+	"스트레스를 받고 불편해한다":         {"en": "I get stressed and uncomfortable", "ja": "ストレスを感じて落ち着かない"},
+	"여러 명이 모여 액티비티를 즐긴다":     {"en": "Getting a group together for activities", "ja": "大勢で集まってアクティビティを楽しむ"},
+	"친구들과 외출하거나 새로운 곳을 탐험한다": {"en": "Going out with friends or exploring new places", "ja": "友人と出かけたり新しい場所を探検したりする"},
+	"기분에 따라 외출하거나 집에서 쉰다":    {"en": "Going out or resting at home, depending on my mood", "ja": "気分次第で出かけたり家で休んだりする"},
+	"소수의 친구들과 조용히 만난다":       {"en": "Meeting a few friends quietly", "ja": "少数の友人と静かに会う"},
+	"집에서 혼자만의 시간을 보낸다":       {"en": "Spending time alone at home", "ja": "家で一人の時間を過ごす"},
+	"리더 역할을 맡아 팀을 이끈다":       {"en": "I take the lead and guide the team", "ja": "リーダー役を担ってチームを引っ張る"},
+	"적극적으로 의견을 제시하고 참여한다":    {"en": "I actively share ideas and take part", "ja": "積極的に意見を出して参加する"},
+	"상황에 따라 역할을 조절한다":        {"en": "I adjust my role to the situation", "ja": "状況に応じて役割を調整する"},
+	"맡은 부분에 집중하며 완성도를 높인다":   {"en": "I focus on my part and polish it", "ja": "担当部分に集中して完成度を高める"},
+	"조용히 자신의 일을 처리한다":        {"en": "I quietly take care of my own work", "ja": "黙々と自分の仕事をこなす"},
+	"먼저 다가가 대화를 시작한다":        {"en": "I approach them and start the conversation", "ja": "自分から近づいて会話を始める"},
+	"자연스럽게 소통한다":             {"en": "I communicate naturally", "ja": "自然にコミュニケーションをとる"},
+	"상황을 보고 행동한다":            {"en": "I read the situation before acting", "ja": "状況を見て行動する"},
+	"상대방이 먼저 말을 걸기를 기다린다":    {"en": "I wait for them to speak first", "ja": "相手が話しかけてくれるのを待つ"},
+	"낯을 많이 가리는 편이다":          {"en": "I'm quite shy with strangers", "ja": "人見知りする方だ"},
+	"다양한 경험과 새로운 시도":         {"en": "Varied experiences and trying new things", "ja": "さまざまな経験と新しい挑戦"},
+	"여러 가지를 조금씩 경험하는 것":      {"en": "Trying a little of many things", "ja": "いろいろなことを少しずつ経験すること"},
+	"상황에 따라 깊이와 폭을 조절":       {"en": "Balancing depth and breadth as it suits me", "ja": "状況に応じて深さと幅を調整すること"},
+	"한 가지를 깊이 있게 파고드는 것":     {"en": "Digging deep into one thing", "ja": "一つのことを深く掘り下げること"},
+	"전문가 수준까지 도달하는 것":        {"en": "Reaching an expert level", "ja": "専門家レベルに到達すること"},
+	"10명 이상의 큰 모임":           {"en": "A large group of 10 or more", "ja": "10人以上の大きな集まり"},
+	"5-10명 정도의 모임":           {"en": "A group of about 5-10", "ja": "5〜10人くらいの集まり"},
+	"2-4명의 소규모 모임":           {"en": "A small group of 2-4", "ja": "2〜4人の少人数の集まり"},
+	"1:1 만남이 가장 편하다":         {"en": "One-on-one feels most comfortable", "ja": "1対1で会うのが一番楽だ"},
+}
+
+// applySeedTranslations 질문과 옵션에 번역문 설정 (이미 번역이 있으면 유지)
+func applySeedTranslations(question *models.Question) {
+	if len(question.Translations) == 0 {
+		question.Translations = seedTranslations[question.QuestionText]
+	}
+	for i := range question.Options {
+		option := &question.Options[i]
+		if len(option.Translations) == 0 {
+			option.Translations = seedTranslations[option.OptionText]
+		}
+	}
+}
+
+// backfillSeedTranslations 번역문이 없는 기존 질문/옵션에 번역문 저장
+func backfillSeedTranslations(question *models.Question) error {
+	if len(question.Translations) == 0 {
+		if translations, ok := seedTranslations[question.QuestionText]; ok {
+			if err := database.DB.Model(question).Update("translations", translations).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range question.Options {
+		option := &question.Options[i]
+		if len(option.Translations) > 0 {
+			continue
+		}
+		if translations, ok := seedTranslations[option.OptionText]; ok {
+			if err := database.DB.Model(option).Update("translations", translations).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
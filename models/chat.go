@@ -15,6 +15,7 @@ type ChatRoom struct {
 	MemberCount int              `json:"member_count" gorm:"default:0"`      // 멤버 수
 	LastMessage *string          `json:"last_message"`                       // 마지막 메시지
 	LastMessageAt *time.Time     `json:"last_message_at"`                    // 마지막 메시지 시간
+	LastSeq     int64            `json:"last_seq" gorm:"not null;default:0"` // 마지막 순번 (메시지와 멤버 이벤트 공통)
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
 	Members     []ChatRoomMember `json:"members,omitempty" gorm:"foreignKey:ChatRoomID"`
@@ -38,14 +39,27 @@ type ChatRoomMember struct {
 // ChatMessage 채팅 메시지
 type ChatMessage struct {
 	ID         uint      `json:"id" gorm:"primaryKey"`
-	ChatRoomID uint      `json:"chat_room_id" gorm:"not null;index"`
+	ChatRoomID uint      `json:"chat_room_id" gorm:"not null;index;uniqueIndex:idx_chat_messages_client_id;uniqueIndex:idx_chat_messages_room_seq"`
 	ChatRoom   ChatRoom  `json:"-" gorm:"foreignKey:ChatRoomID"`
-	UserID     uint      `json:"user_id" gorm:"not null;index"`
+	Seq        int64     `json:"seq" gorm:"not null;uniqueIndex:idx_chat_messages_room_seq"` // 채팅방 안의 순번 (빈 번호가 있으면 놓친 메시지/이벤트)
+	UserID     uint      `json:"user_id" gorm:"not null;index;uniqueIndex:idx_chat_messages_client_id"`
 	User       User      `json:"user" gorm:"foreignKey:UserID"`
 	Message    string    `json:"message" gorm:"type:text;not null"`         // 메시지 내용
 	MessageType string   `json:"message_type" gorm:"default:'text'"`        // text, image, file, system
 	FileURL    *string   `json:"file_url"`                                  // 파일/이미지 URL (nullable)
+	ClientID   *string   `json:"client_id,omitempty" gorm:"uniqueIndex:idx_chat_messages_client_id"` // 클라이언트가 생성한 메시지 ID (재전송 멱등 키)
 	IsRead     bool      `json:"is_read" gorm:"default:false"`              // 읽음 여부
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
+
+// ChatRoomEvent 채팅방 멤버 이벤트 (재연결한 클라이언트에 놓친 이벤트를 다시 보내기 위해 기록)
+type ChatRoomEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ChatRoomID uint      `json:"chat_room_id" gorm:"not null;uniqueIndex:idx_chat_room_events_room_seq"`
+	Seq        int64     `json:"seq" gorm:"not null;uniqueIndex:idx_chat_room_events_room_seq"` // 메시지와 같은 채팅방 순번
+	Type       string    `json:"type" gorm:"not null"`                                          // member_join, member_leave
+	UserID     uint      `json:"user_id" gorm:"not null"`
+	Data       string    `json:"data" gorm:"type:text"` // 브로드캐스트한 data (JSON)
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -14,7 +14,8 @@ type Club struct {
 	MemberCount      int          `json:"member_count"`     // 현재 멤버 수
 	MaxMembers       int          `json:"max_members"`      // 최대 멤버 수
 	Tags             string       `json:"tags" gorm:"type:text"` // JSON 배열 형태로 저장
-	PreferredScores  string       `json:"preferred_scores" gorm:"type:text"` // 선호 성향 점수 (JSON)
+	PreferredScores  string       `json:"preferred_scores" gorm:"type:text"` // 선호 성향 점수 (JSON, 차원별 0-100)
+	Tolerances       string       `json:"tolerances" gorm:"type:text"`       // 선호 성향 점수의 차원별 허용 오차 (JSON)
 	CreatedAt        time.Time    `json:"created_at"`
 	UpdatedAt        time.Time    `json:"updated_at"`
 	Members          []ClubMember `json:"members" gorm:"foreignKey:ClubID"`
@@ -22,9 +23,9 @@ type Club struct {
 
 type ClubMember struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
-	ClubID    uint      `json:"club_id" gorm:"not null"`
+	ClubID    uint      `json:"club_id" gorm:"not null;uniqueIndex:idx_club_members_club_user"`
 	Club      Club      `json:"-" gorm:"foreignKey:ClubID"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_club_members_club_user"`
 	User      User      `json:"user" gorm:"foreignKey:UserID"`
 	JoinedAt  time.Time `json:"joined_at"`
 	CreatedAt time.Time `json:"created_at"`
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 실험 상태
+const (
+	ExperimentRunning = "running" // 추천 요청을 변형에 배정 중
+	ExperimentStopped = "stopped" // 종료 (기본 추천 전략 사용)
+)
+
+// Experiment 클럽 추천 전략 A/B 실험
+// 실행 중인 실험은 하나뿐이며, 응답자는 실험 이름과 응답자 키의 해시로 항상 같은 변형에 배정된다.
+type Experiment struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Name        string     `json:"name" gorm:"uniqueIndex;not null"`
+	Description string     `json:"description"`
+	Variants    string     `json:"variants" gorm:"type:text;not null"` // [{"name":"control","strategy":"feedback","weight":50}, ...]
+	Status      string     `json:"status" gorm:"not null;index"`       // running, stopped
+	StartedAt   time.Time  `json:"started_at"`
+	StoppedAt   *time.Time `json:"stopped_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ExperimentExposure 응답자가 실험 변형의 추천을 처음 받은 기록 (실험당 응답자 하나)
+type ExperimentExposure struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ExperimentID uint      `json:"experiment_id" gorm:"not null;uniqueIndex:idx_experiment_exposures_subject"`
+	Subject      string    `json:"subject" gorm:"not null;uniqueIndex:idx_experiment_exposures_subject"` // user:1, guest:<session_id>
+	Variant      string    `json:"variant" gorm:"not null"`
+	UserID       *uint     `json:"user_id" gorm:"index"` // 비회원 세션은 계정 연동 시 채워짐
+	SessionID    string    `json:"session_id,omitempty" gorm:"index"`
+	CreatedAt    time.Time `json:"created_at"`
+}
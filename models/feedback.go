@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// 추천 항목 종류
+const (
+	RecommendationItemClub    = "club"
+	RecommendationItemMeeting = "meeting"
+)
+
+// 추천 피드백 이벤트 종류
+const (
+	FeedbackImpression = "impression" // 추천 목록에 표시됨
+	FeedbackClick      = "click"      // 상세 보기
+	FeedbackDismiss    = "dismiss"    // 관심 없음
+	FeedbackJoin       = "join"       // 가입/참여
+)
+
+// RecommendationEvent 추천 항목에 대한 응답자 반응 (회원은 UserID, 비회원은 SessionID)
+type RecommendationEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    *uint     `json:"user_id" gorm:"index"`
+	SessionID string    `json:"session_id,omitempty" gorm:"index"`
+	ItemType  string    `json:"item_type" gorm:"not null"` // club, meeting
+	ItemID    uint      `json:"item_id" gorm:"not null"`
+	Event     string    `json:"event" gorm:"not null"` // impression, click, dismiss, join
+	Source    string    `json:"source,omitempty"`      // 추천 목록 (clubs, similar_clubs, meetings 등)
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UserIdentity 외부 로그인 계정 (provider + subject로 식별)
+// 하나의 User가 여러 로그인 수단(kakao, naver, google, apple, email)을 연결할 수 있다.
+type UserIdentity struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	User          User       `json:"-" gorm:"foreignKey:UserID"`
+	Provider      string     `json:"provider" gorm:"not null;uniqueIndex:idx_identity_provider_subject"` // kakao, naver, google, apple, email
+	Subject       string     `json:"subject" gorm:"not null;uniqueIndex:idx_identity_provider_subject"`  // 제공자 내 고유 ID (email은 정규화된 이메일)
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified" gorm:"default:false"`
+	PasswordHash  string     `json:"-"` // email 제공자 전용 (bcrypt)
+	LastLoginAt   *time.Time `json:"last_login_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
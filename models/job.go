@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// 백그라운드 작업 상태
+const (
+	JobQueued    = "queued"    // 실행 대기 (재시도 대기 포함)
+	JobRunning   = "running"   // 실행 중
+	JobSucceeded = "succeeded" // 성공
+	JobFailed    = "failed"    // 재시도까지 모두 실패
+)
+
+// 백그라운드 작업 실행 계기
+const (
+	JobTriggerSchedule = "schedule" // 스케줄에 따라 자동 실행
+	JobTriggerManual   = "manual"   // API로 직접 실행
+)
+
+// Job 백그라운드 작업 실행 기록
+// Instance가 비어 있으면 단일 실행 잠금을 가진 인스턴스가, 있으면 해당 인스턴스만 실행한다.
+type Job struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Name            string     `json:"name" gorm:"not null;index"`
+	Trigger         string     `json:"trigger" gorm:"not null"` // schedule, manual
+	Status          string     `json:"status" gorm:"not null;index"`
+	Instance        string     `json:"instance"`                // 실행할(실행한) 인스턴스
+	Params          string     `json:"params" gorm:"type:text"` // 작업 입력 (JSON)
+	Result          string     `json:"result" gorm:"type:text"` // 작업 결과 (JSON)
+	Error           string     `json:"error" gorm:"type:text"`  // 마지막 실패 사유
+	Progress        int        `json:"progress"`                // 진행률 (0-100)
+	ProgressMessage string     `json:"progress_message"`        // 현재 단계
+	Attempt         int        `json:"attempt"`                 // 지금까지 시도한 횟수
+	MaxAttempts     int        `json:"max_attempts"`            // 최대 시도 횟수 (재시도 포함)
+	RunAt           time.Time  `json:"run_at" gorm:"index"`     // 이 시각 이후 실행 (재시도 대기)
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// 프로필 규칙 종류
+const (
+	ProfileRuleKindType        = "profile_type" // 프로필 타입 (우선순위가 가장 높은 일치 규칙 하나)
+	ProfileRuleKindDescription = "description"  // 설명 문구 (그룹별로 우선순위가 가장 높은 일치 규칙 하나)
+	ProfileRuleKindLevel       = "level"        // 차원 점수 수준 (예: 높음, 보통)
+)
+
+// ProfileRuleScoreKey level 규칙에서 평가 대상 차원 점수를 가리키는 조건 키
+const ProfileRuleScoreKey = "score"
+
+// ScoreRange 점수 구간 (Min 이상, Max 미만, 비어 있으면 제한 없음)
+type ScoreRange struct {
+	Min *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max *float64 `json:"max,omitempty" yaml:"max,omitempty"`
+}
+
+// Contains 점수가 구간에 속하는지 확인
+func (r ScoreRange) Contains(score float64) bool {
+	if r.Min != nil && score < *r.Min {
+		return false
+	}
+	if r.Max != nil && score >= *r.Max {
+		return false
+	}
+	return true
+}
+
+// DimensionRanges 차원별 점수 구간 조건 (모든 조건을 만족해야 일치, 비어 있으면 항상 일치)
+type DimensionRanges map[string]ScoreRange
+
+// ProfileRule - 프로필 타입/설명/수준 판정 규칙
+type ProfileRule struct {
+	ID           uint            `json:"id" gorm:"primaryKey"`
+	Kind         string          `json:"kind" gorm:"not null;index"`
+	Group        string          `json:"group,omitempty" gorm:"column:rule_group"` // description 규칙의 그룹
+	Label        string          `json:"label" gorm:"not null;type:text"`          // 프로필 타입 이름, 설명 문구 또는 수준 이름
+	Priority     int             `json:"priority" gorm:"not null;default:0"`
+	Conditions   DimensionRanges `json:"conditions" gorm:"type:jsonb;serializer:json;not null"`
+	Translations Translations    `json:"translations,omitempty" gorm:"type:jsonb;not null"` // label 번역문
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
@@ -1,12 +1,92 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// 성향 측정 차원
+const (
+	DimensionSociality   = "sociality"   // 사교성
+	DimensionActivity    = "activity"    // 활동성
+	DimensionIntimacy    = "intimacy"    // 친밀도
+	DimensionImmersion   = "immersion"   // 몰입도
+	DimensionFlexibility = "flexibility" // 유연성
+)
+
+// Dimensions 모든 성향 측정 차원 (점수 벡터 순서)
+var Dimensions = []string{
+	DimensionSociality,
+	DimensionActivity,
+	DimensionIntimacy,
+	DimensionImmersion,
+	DimensionFlexibility,
+}
+
+// DimensionWeights 옵션이 각 성향 차원에 주는 가중치 (예: {"sociality": 2, "intimacy": -1})
+// 없는 차원은 0으로 취급한다.
+type DimensionWeights map[string]float64
+
+// Translations 언어별 번역문 (예: {"en": "...", "ja": "..."})
+// 원문은 기본 언어(ko)로 저장하고, 번역이 없는 언어는 원문을 사용한다.
+type Translations map[string]string
+
+// Text 해당 언어의 번역문 (없으면 원문)
+func (t Translations) Text(locale, original string) string {
+	if text, ok := t[locale]; ok && text != "" {
+		return text
+	}
+	return original
+}
+
+// Value 번역문이 없어도 NOT NULL 컬럼에 빈 객체로 저장
+func (t Translations) Value() (driver.Value, error) {
+	if t == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan DB의 JSONB 값을 번역문으로 변환
+func (t *Translations) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported translations value type %T", value)
+	}
+	return json.Unmarshal(data, t)
+}
+
+// IsValidDimension 알려진 성향 차원인지 확인
+func IsValidDimension(dimension string) bool {
+	for _, d := range Dimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
 
 type Question struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
+	QuestionnaireID uint  `json:"questionnaire_id" gorm:"not null;index"` // 소속 설문 버전
 	QuestionText string   `json:"question_text" gorm:"not null;type:text"`
 	Order       int       `json:"order" gorm:"not null"` // 질문 순서 (1-10)
 	Category    string    `json:"category"`              // 측정 카테고리 (sociality, activity, intimacy, immersion, flexibility)
+	Translations Translations `json:"translations,omitempty" gorm:"type:jsonb;not null"` // 질문 번역문
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Options     []Option  `json:"options" gorm:"foreignKey:QuestionID"`
@@ -16,8 +96,8 @@ type Option struct {
 	ID         uint      `json:"id" gorm:"primaryKey"`
 	QuestionID uint      `json:"question_id" gorm:"not null"`
 	OptionText string    `json:"option_text" gorm:"not null;type:text"`
-	Score      int       `json:"score"`       // 점수 (1-5)
-	Weight     string    `json:"weight"`      // 가중치 카테고리
+	Weights    DimensionWeights `json:"weights" gorm:"type:jsonb;serializer:json;not null"` // 차원별 가중치 벡터
+	Translations Translations `json:"translations,omitempty" gorm:"type:jsonb;not null"` // 옵션 번역문
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -32,6 +112,7 @@ type UserAnswer struct {
 	ID         uint      `json:"id" gorm:"primaryKey"`
 	UserID     uint      `json:"user_id" gorm:"not null"`
 	User       User      `json:"-" gorm:"foreignKey:UserID"`
+	QuestionnaireID uint `json:"questionnaire_id" gorm:"not null"` // 응답한 설문 버전
 	QuestionID uint      `json:"question_id" gorm:"not null"`
 	Question   Question  `json:"question" gorm:"foreignKey:QuestionID"`
 	OptionID   uint      `json:"option_id" gorm:"not null"`
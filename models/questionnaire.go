@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// 설문 버전 상태
+const (
+	QuestionnaireStatusDraft     = "draft"     // 편집 중 (응답 불가)
+	QuestionnaireStatusPublished = "published" // 현재 사용 중인 버전
+	QuestionnaireStatusArchived  = "archived"  // 이전 버전 (기존 결과 해석용으로 보존)
+)
+
+// 설문 점수 계산 방식
+const (
+	ScoringMethodAverage  = "average"  // 차원별로 선택한 옵션 가중치(1-5)의 평균을 0-100으로 변환 (단일 점수 옵션에서 변환된 버전)
+	ScoringMethodWeighted = "weighted" // 질문별 최소/최대 가중치 합으로 정규화 (가중치 벡터로 작성한 버전)
+)
+
+// Questionnaire - 설문 버전
+// 발행된 버전의 질문/옵션은 수정하지 않고 새 버전을 발행하므로, 과거 응답의 점수를 언제든 재현할 수 있다.
+type Questionnaire struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Version       int        `json:"version" gorm:"uniqueIndex;not null"`
+	Title         string     `json:"title"`
+	Status        string     `json:"status" gorm:"not null;default:draft"`
+	ScoringMethod string     `json:"scoring_method" gorm:"not null;default:weighted"` // average, weighted
+	PublishedAt   *time.Time `json:"published_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Questions     []Question `json:"questions,omitempty" gorm:"foreignKey:QuestionnaireID"`
+}
@@ -14,6 +14,7 @@ type GuestSession struct {
 	FlexibilityScore float64  `json:"flexibility_score"`
 	ProfileType     string    `json:"profile_type"`
 	ResultSummary   string    `json:"result_summary" gorm:"type:text"`
+	QuestionnaireID *uint     `json:"questionnaire_id"`               // 점수 계산에 사용된 설문 버전
 	IsLinked        bool      `json:"is_linked" gorm:"default:false"` // 계정 연동 여부
 	LinkedUserID    *uint     `json:"linked_user_id"`                 // 연동된 사용자 ID (nullable)
 	ExpiresAt       time.Time `json:"expires_at"`                     // 세션 만료 시간
@@ -26,6 +27,7 @@ type GuestAnswer struct {
 	ID         uint         `json:"id" gorm:"primaryKey"`
 	SessionID  string       `json:"session_id" gorm:"not null"`
 	Session    GuestSession `json:"-" gorm:"foreignKey:SessionID"`
+	QuestionnaireID uint    `json:"questionnaire_id" gorm:"not null"` // 응답한 설문 버전
 	QuestionID uint         `json:"question_id" gorm:"not null"`
 	Question   Question     `json:"question" gorm:"foreignKey:QuestionID"`
 	OptionID   uint         `json:"option_id" gorm:"not null"`
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// RefreshToken 리프레시 토큰 (원문은 저장하지 않고 SHA-256 해시만 저장)
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	User      User       `json:"-" gorm:"foreignKey:UserID"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	FamilyID  string     `json:"family_id" gorm:"index;not null"` // 같은 로그인에서 회전된 토큰 묶음
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`    // 회전에 사용된 시간 (재사용 감지용)
+	RevokedAt *time.Time `json:"revoked_at"` // 폐기 시간
+	UserAgent string     `json:"user_agent"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RevokedToken 액세스 토큰 폐기 목록
+// JTI가 있으면 해당 토큰만, RevokeBefore가 있으면 그 시점 이전에 발급된 사용자의 모든 토큰을 폐기한다.
+type RevokedToken struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	JTI          string     `json:"jti" gorm:"index"`
+	UserID       uint       `json:"user_id" gorm:"index;not null"`
+	RevokeBefore *time.Time `json:"revoke_before"`
+	Reason       string     `json:"reason"`                  // logout, reuse_detected, banned
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"index"` // 이 시간 이후에는 정리 가능
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -4,10 +4,18 @@ import (
 	"time"
 )
 
+// 사용자 권한
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin" // 설문 등 콘텐츠 관리
+)
+
 type User struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
-	Email     string    `json:"email" gorm:"unique;not null"`
+	Email     string    `json:"email" gorm:"index"` // 연락용 이메일 (로그인 식별은 UserIdentity로 함, 중복/빈 값 허용)
 	Name      string    `json:"name" gorm:"not null"`
+	IsBanned  bool      `json:"is_banned" gorm:"default:false"` // 정지된 계정
+	Role      string    `json:"role" gorm:"not null;default:user"` // user, admin
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -23,6 +31,7 @@ type UserProfile struct {
 	FlexibilityScore float64 `json:"flexibility_score"` // 유연성
 	ResultSummary   string  `json:"result_summary" gorm:"type:text"`
 	ProfileType     string  `json:"profile_type"` // 성향 유형
+	QuestionnaireID *uint   `json:"questionnaire_id"` // 점수 계산에 사용된 설문 버전 (직접 입력한 프로필은 null)
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -2,43 +2,54 @@ package routes
 
 import (
 	"ongi-back/handlers"
+	"ongi-back/middleware"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 )
 
 func Setup(app *fiber.App) {
-	api := app.Group("/api/v1")
+	api := app.Group("/api/v1", middleware.Locale()) // 응답 언어 (Accept-Language, ?lang=)
+	requireAuth := middleware.RequireAuth()
+	requireAdmin := middleware.RequireAdmin()
 
 	// Auth routes (인증)
 	auth := api.Group("/auth")
 	auth.Post("/kakao/login", handlers.KakaoLogin)       // 클라이언트사이드 OAuth
 	auth.Get("/kakao/callback", handlers.KakaoCallback)  // 서버사이드 OAuth 콜백
+	auth.Post("/refresh", handlers.RefreshToken)         // 토큰 재발급 (리프레시 토큰 회전)
+	auth.Post("/logout", requireAuth, handlers.Logout)   // 로그아웃 (토큰 폐기)
+	auth.Post("/email/register", handlers.EmailRegister) // 이메일 회원가입
+	auth.Get("/identities", requireAuth, handlers.GetIdentities)          // 연결된 로그인 수단 목록
+	auth.Post("/:provider/login", handlers.ProviderLogin)                 // kakao, naver, google, apple, email 로그인
+	auth.Post("/:provider/link", requireAuth, handlers.LinkProvider)      // 로그인 수단 연결
+	auth.Delete("/:provider/link", requireAuth, handlers.UnlinkProvider)  // 로그인 수단 연결 해제
 
 	// Guest/Session routes (비회원 설문)
 	guest := api.Group("/guest")
 	guest.Post("/session", handlers.CreateGuestSession)           // 세션 생성
 	guest.Post("/answers", handlers.SubmitGuestAnswers)            // 답변 제출
 	guest.Get("/result/:sessionId", handlers.GetGuestResult)       // 결과 조회
+	guest.Get("/result/:sessionId/clubs", handlers.GetRecommendedClubsForSession) // 성향 기반 클럽 추천 (필터)
+	guest.Post("/result/:sessionId/events", handlers.RecordGuestRecommendationFeedback) // 추천 피드백 (노출/클릭/관심 없음/가입)
 	guest.Get("/session/:sessionId", handlers.GetSessionInfo)      // 세션 정보
-	guest.Post("/link", handlers.LinkSessionToAccount)             // 계정 연동
+	guest.Post("/link", requireAuth, handlers.LinkSessionToAccount) // 계정 연동 (인증 필요)
 	guest.Post("/compatibility", handlers.GetCompatibility)        // 궁합 계산
 
 	// User routes
-	users := api.Group("/users")
+	users := api.Group("/users", requireAuth)
 	users.Get("/", handlers.GetUsers)
-	users.Post("/", handlers.CreateUser)
 	users.Get("/:id", handlers.GetUser)
 	users.Post("/profile", handlers.CreateOrUpdateUserProfile)
 	users.Get("/:id/profile", handlers.GetUserProfile)
 	users.Post("/:id/auto-match", handlers.AutoMatchClubs)
 	users.Post("/:id/auto-match-group", handlers.AutoMatchWithSimilarUsers)
 
-	// Matching routes - 전체 사용자 그룹 매칭
-	api.Post("/match-all", handlers.MatchAllUsersToClubs)
+	// Recommendation feedback routes (추천 순위에 반영)
+	api.Post("/recommendations/events", requireAuth, handlers.RecordRecommendationFeedback)
 
 	// Chat routes (그룹 채팅)
-	chat := api.Group("/chat")
+	chat := api.Group("/chat", requireAuth)
 	chat.Post("/rooms", handlers.CreateChatRoom)                         // 채팅방 생성
 	chat.Get("/rooms", handlers.GetChatRooms)                            // 채팅방 목록 조회
 	chat.Get("/rooms/:id", handlers.GetChatRoom)                         // 채팅방 상세 조회
@@ -49,33 +60,69 @@ func Setup(app *fiber.App) {
 	chat.Delete("/rooms/:id/members/:userId", handlers.RemoveChatRoomMember) // 멤버 제거
 
 	// WebSocket route (실시간 채팅)
-	app.Use("/ws", handlers.WebSocketHandler)
+	app.Use("/ws", handlers.WebSocketHandler, requireAuth)
 	app.Get("/ws/chat/:roomId", websocket.New(handlers.HandleWebSocket))
+	app.Get("/ws", websocket.New(handlers.HandleUserWebSocket))
 
-	// Question routes
+	// Question routes (비회원 설문에서도 사용하므로 공개)
 	questions := api.Group("/questions")
 	questions.Get("/", handlers.GetQuestions)
 	questions.Get("/:id", handlers.GetQuestion)
 
+	// Questionnaire routes (설문 버전)
+	questionnaires := api.Group("/questionnaires")
+	questionnaires.Get("/", handlers.GetQuestionnaires)
+	questionnaires.Get("/:id", handlers.GetQuestionnaire)
+
+	// Admin routes (설문 작성 및 프로필 규칙, 관리자 권한 필요)
+	admin := api.Group("/admin", requireAuth, requireAdmin)
+	admin.Get("/questionnaires", handlers.AdminGetQuestionnaires)
+	admin.Post("/questionnaires", handlers.AdminCreateQuestionnaire)             // 초안 생성 (기존 버전 복사 가능)
+	admin.Get("/questionnaires/:id", handlers.AdminGetQuestionnaire)             // 질문/옵션 및 차원 커버리지
+	admin.Delete("/questionnaires/:id", handlers.AdminDeleteQuestionnaire)       // 초안 삭제
+	admin.Post("/questionnaires/:id/publish", handlers.AdminPublishQuestionnaire) // 발행
+	admin.Post("/questionnaires/:id/questions", handlers.AdminCreateQuestion)
+	admin.Put("/questions/:id", handlers.AdminUpdateQuestion)
+	admin.Delete("/questions/:id", handlers.AdminDeleteQuestion)
+	admin.Post("/questions/:id/options", handlers.AdminCreateOption)
+	admin.Put("/options/:id", handlers.AdminUpdateOption)
+	admin.Delete("/options/:id", handlers.AdminDeleteOption)
+	admin.Get("/profile-rules", handlers.AdminGetProfileRules)
+	admin.Post("/profile-rules/preview", handlers.AdminPreviewProfileRules) // 점수 벡터의 프로필 타입 미리보기
+	admin.Post("/profile-rules/reload", handlers.AdminReloadProfileRules)   // 파일/DB에서 다시 로드
+	admin.Post("/match-all", handlers.MatchAllUsersToClubs)                 // 전체 사용자 그룹 매칭 (모든 사용자의 클럽 가입을 바꾸므로 관리자 전용)
+	admin.Get("/jobs", handlers.AdminGetJobs)                               // 작업 스케줄/최근 실행 기록
+	admin.Get("/jobs/:id", handlers.AdminGetJob)                            // 작업 상태/진행률/결과 (match-all 진행률 등)
+	admin.Post("/jobs/:name/run", handlers.AdminRunJob)                     // 작업 즉시 실행 (session_cleanup, rematch, reindex)
+	admin.Get("/experiments", handlers.AdminGetExperiments)                 // 추천 전략 실험 목록과 전략 목록
+	admin.Post("/experiments", handlers.AdminCreateExperiment)              // 실험 생성과 시작
+	admin.Post("/experiments/:id/stop", handlers.AdminStopExperiment)       // 실험 종료
+	admin.Get("/experiments/:id/report", handlers.AdminGetExperimentReport) // 변형별 가입 전환율
+	admin.Get("/websocket/stats", handlers.AdminGetWebSocketStats)          // 이 인스턴스의 WebSocket 연결/전송 통계
+	admin.Post("/users", handlers.AdminCreateUser)                          // 로그인 수단 없는 사용자 생성 (이메일 중복 확인 없음)
+	admin.Post("/users/:id/ban", handlers.AdminBanUser)                     // 계정 정지 (모든 토큰 즉시 폐기)
+	admin.Delete("/users/:id/ban", handlers.AdminUnbanUser)                 // 계정 정지 해제
+
 	// Answer routes
-	answers := api.Group("/answers")
+	answers := api.Group("/answers", requireAuth)
 	answers.Post("/", handlers.SubmitAnswer)
 	answers.Post("/batch", handlers.SubmitAnswers)
 	answers.Get("/user/:userId", handlers.GetUserAnswers)
 
 	// Result routes
-	results := api.Group("/results")
+	results := api.Group("/results", requireAuth)
 	results.Get("/:userId", handlers.GetAnalysisResult)
 
 	// Club routes
-	clubs := api.Group("/clubs")
+	clubs := api.Group("/clubs", requireAuth)
 	clubs.Get("/", handlers.GetClubs)
 	clubs.Post("/", handlers.CreateClub)
+	clubs.Get("/recommended", handlers.GetRecommendedClubs) // 성향 기반 클럽 추천 (필터)
 	clubs.Get("/:id", handlers.GetClub)
 	clubs.Post("/join", handlers.JoinClub)
 
 	// Meeting routes
-	meetings := api.Group("/meetings")
+	meetings := api.Group("/meetings", requireAuth)
 	meetings.Get("/", handlers.GetMeetings)
 	meetings.Post("/", handlers.CreateMeeting)
 	meetings.Get("/:id", handlers.GetMeeting)
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	appleIssuer  = "https://appleid.apple.com"
+	appleKeysURL = "https://appleid.apple.com/auth/keys"
+)
+
+// AppleIDClaims 애플 ID 토큰 클레임
+type AppleIDClaims struct {
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"` // bool 또는 "true"/"false"
+	jwt.RegisteredClaims
+}
+
+// appleProvider 애플 로그인 제공자 (ID 토큰을 애플 공개키로 직접 검증)
+type appleProvider struct {
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func newAppleProvider() *appleProvider {
+	return &appleProvider{}
+}
+
+func (p *appleProvider) Name() string { return "apple" }
+
+func (p *appleProvider) Authenticate(cred Credential) (*ExternalIdentity, error) {
+	if cred.IDToken == "" {
+		return nil, ErrInvalidCredential
+	}
+
+	clientIDs := os.Getenv("APPLE_CLIENT_ID")
+	if clientIDs == "" {
+		return nil, fmt.Errorf("APPLE_CLIENT_ID is not set")
+	}
+
+	claims := &AppleIDClaims{}
+	_, err := jwt.ParseWithClaims(cred.IDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(kid)
+	}, jwt.WithIssuer(appleIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify apple id token: %w", err)
+	}
+
+	if !audienceMatches(claims.Audience, strings.Split(clientIDs, ",")) {
+		return nil, fmt.Errorf("apple token audience mismatch")
+	}
+
+	return &ExternalIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: fmt.Sprint(claims.EmailVerified) == "true",
+		Name:          cred.Name, // 애플은 이름을 토큰에 담지 않고 최초 로그인 시 클라이언트로만 전달
+	}, nil
+}
+
+// publicKey kid에 해당하는 애플 공개키 조회 (1시간 캐시, 모르는 kid면 재조회)
+func (p *appleProvider) publicKey(kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok && time.Since(p.fetchedAt) < time.Hour {
+		return key, nil
+	}
+
+	keys, err := fetchAppleKeys()
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.fetchedAt = time.Now()
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown apple key id: %s", kid)
+	}
+	return key, nil
+}
+
+func fetchAppleKeys() (map[string]*rsa.PublicKey, error) {
+	resp, err := http.Get(appleKeysURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch apple keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read apple keys: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("apple keys API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse apple keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+func audienceMatches(audience jwt.ClaimStrings, clientIDs []string) bool {
+	for _, aud := range audience {
+		for _, clientID := range clientIDs {
+			if strings.TrimSpace(clientID) == aud {
+				return true
+			}
+		}
+	}
+	return false
+}
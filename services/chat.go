@@ -0,0 +1,324 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ongi-back/database"
+	"ongi-back/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxChatMessageLength 메시지 최대 길이 (문자 수)
+const maxChatMessageLength = 4000
+
+// maxClientIDLength 클라이언트 메시지 ID 최대 길이
+const maxClientIDLength = 64
+
+// ErrChatRoomNotFound 채팅방 없음
+var ErrChatRoomNotFound = errors.New("chat room not found")
+
+// ErrNotChatMember 채팅방 멤버가 아님
+var ErrNotChatMember = errors.New("user is not a member of this chat room")
+
+// errDuplicateClientID 같은 client_id로 이미 저장된 메시지 (트랜잭션을 되돌려 순번을 쓰지 않음)
+var errDuplicateClientID = errors.New("duplicate client_id")
+
+var chatMessageTypes = map[string]bool{
+	"text":   true,
+	"image":  true,
+	"file":   true,
+	"system": true,
+}
+
+// ChatMessageInput 메시지 전송 입력
+type ChatMessageInput struct {
+	Message     string `json:"message"`
+	MessageType string `json:"message_type"` // text, image, file, system (기본 text)
+	FileURL     string `json:"file_url"`
+	ClientID    string `json:"client_id"` // 클라이언트가 생성한 메시지 ID (선택, 같은 ID로 다시 보내면 처음 저장한 메시지 반환)
+}
+
+// ChatMessageError 메시지 입력 오류
+type ChatMessageError struct {
+	Reason string
+}
+
+func (e *ChatMessageError) Error() string {
+	return "invalid message: " + e.Reason
+}
+
+// SendChatMessage 메시지 저장과 채팅방 브로드캐스트 (HTTP와 WebSocket 공통)
+// 같은 사용자가 같은 client_id로 다시 보내면 저장하거나 브로드캐스트하지 않고 처음 메시지를 반환한다 (duplicate=true).
+func SendChatMessage(roomID, userID uint, input ChatMessageInput) (*models.ChatMessage, bool, error) {
+	input.ClientID = strings.TrimSpace(input.ClientID)
+	if strings.TrimSpace(input.Message) == "" && input.FileURL == "" {
+		return nil, false, &ChatMessageError{Reason: "message is required"}
+	}
+	if utf8.RuneCountInString(input.Message) > maxChatMessageLength {
+		return nil, false, &ChatMessageError{Reason: fmt.Sprintf("message must be at most %d characters", maxChatMessageLength)}
+	}
+	if len(input.ClientID) > maxClientIDLength {
+		return nil, false, &ChatMessageError{Reason: fmt.Sprintf("client_id must be at most %d characters", maxClientIDLength)}
+	}
+	if input.MessageType == "" {
+		input.MessageType = "text"
+	}
+	if !chatMessageTypes[input.MessageType] {
+		return nil, false, &ChatMessageError{Reason: fmt.Sprintf("unknown message_type %q", input.MessageType)}
+	}
+
+	message := models.ChatMessage{
+		ChatRoomID:  roomID,
+		UserID:      userID,
+		Message:     input.Message,
+		MessageType: input.MessageType,
+	}
+	if input.FileURL != "" {
+		message.FileURL = &input.FileURL
+	}
+	if input.ClientID != "" {
+		message.ClientID = &input.ClientID
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkChatMember(tx, roomID, userID); err != nil {
+			return err
+		}
+
+		seq, err := nextRoomSeq(tx, roomID)
+		if err != nil {
+			return err
+		}
+		message.Seq = seq
+
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chat_room_id"}, {Name: "user_id"}, {Name: "client_id"}},
+			DoNothing: true,
+		}).Create(&message)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errDuplicateClientID
+		}
+
+		// 채팅방의 마지막 메시지와 다른 멤버들의 읽지 않은 메시지 수
+		if err := tx.Model(&models.ChatRoom{}).Where("id = ?", roomID).Updates(map[string]interface{}{
+			"last_message":    message.Message,
+			"last_message_at": message.CreatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ChatRoomMember{}).
+			Where("chat_room_id = ? AND user_id <> ?", roomID, userID).
+			UpdateColumn("unread_count", gorm.Expr("unread_count + 1")).Error
+	})
+
+	duplicate := errors.Is(err, errDuplicateClientID)
+	if duplicate {
+		message = models.ChatMessage{}
+		err = database.DB.Where("chat_room_id = ? AND user_id = ? AND client_id = ?", roomID, userID, input.ClientID).First(&message).Error
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	database.DB.Preload("User").First(&message, message.ID)
+
+	if !duplicate && GlobalHub != nil {
+		GlobalHub.BroadcastEvent(&Message{
+			Type:   "message",
+			RoomID: roomID,
+			UserID: userID,
+			Seq:    message.Seq,
+			Data:   message,
+		})
+		publishRoomUpdate(roomID, "message")
+	}
+	return &message, duplicate, nil
+}
+
+// PublishMemberEvent 멤버 이벤트(member_join, member_leave)를 채팅방 순번과 함께 기록하고 브로드캐스트
+// 기록한 이벤트는 다시 연결한 클라이언트에 놓친 이벤트로 다시 보낸다.
+func PublishMemberEvent(roomID uint, eventType string, userID uint, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	event := models.ChatRoomEvent{
+		ChatRoomID: roomID,
+		Type:       eventType,
+		UserID:     userID,
+		Data:       string(payload),
+	}
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		seq, err := nextRoomSeq(tx, roomID)
+		if err != nil {
+			return err
+		}
+		event.Seq = seq
+		return tx.Create(&event).Error
+	}); err != nil {
+		return err
+	}
+
+	if GlobalHub != nil {
+		GlobalHub.BroadcastEvent(&Message{
+			Type:   eventType,
+			RoomID: roomID,
+			UserID: userID,
+			Seq:    event.Seq,
+			Data:   data,
+		})
+	}
+	return nil
+}
+
+// nextRoomSeq 채팅방 순번 증가 (메시지와 멤버 이벤트 공통)
+// 채팅방 행이 트랜잭션 끝까지 잠기고 되돌리면 순번도 되돌아가므로, 같은 채팅방의 순번은 커밋 순서대로 빈틈없이 이어진다.
+func nextRoomSeq(tx *gorm.DB, roomID uint) (int64, error) {
+	var seqs []int64
+	if err := tx.Raw("UPDATE chat_rooms SET last_seq = last_seq + 1 WHERE id = ? RETURNING last_seq", roomID).Scan(&seqs).Error; err != nil {
+		return 0, err
+	}
+	if len(seqs) == 0 {
+		return 0, ErrChatRoomNotFound
+	}
+	return seqs[0], nil
+}
+
+// MarkChatRoomRead 읽음 처리 (읽지 않은 메시지 수 초기화) 후 채팅방에 브로드캐스트
+func MarkChatRoomRead(roomID, userID uint) (time.Time, error) {
+	now := time.Now()
+	result := database.DB.Model(&models.ChatRoomMember{}).
+		Where("chat_room_id = ? AND user_id = ?", roomID, userID).
+		Updates(map[string]interface{}{
+			"last_read_at": now,
+			"unread_count": 0,
+		})
+	if result.Error != nil {
+		return now, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return now, ErrNotChatMember
+	}
+
+	if GlobalHub != nil {
+		GlobalHub.BroadcastMessage(roomID, "read", userID, map[string]interface{}{
+			"user_id":      userID,
+			"last_read_at": now,
+		})
+		publishRoomUpdate(roomID, "read", userID)
+	}
+	return now, nil
+}
+
+// NotifyChatRoomJoined 채팅방에 들어온 사용자들의 /ws 연결에 구독을 추가하고 room_added 전송
+func NotifyChatRoomJoined(roomID uint, userIDs []uint) {
+	if GlobalHub == nil || len(userIDs) == 0 {
+		return
+	}
+
+	var room models.ChatRoom
+	if err := database.DB.Preload("Club").First(&room, roomID).Error; err != nil {
+		log.Printf("Failed to load chat room %d for room_added: %v", roomID, err)
+		return
+	}
+
+	GlobalHub.SubscribeUsers(roomID, userIDs)
+	messages := make(map[uint]*Message, len(userIDs))
+	for _, userID := range userIDs {
+		messages[userID] = &Message{
+			Type:   "room_added",
+			RoomID: roomID,
+			UserID: userID,
+			Data:   room,
+		}
+	}
+	GlobalHub.SendToUsers(messages)
+}
+
+// NotifyChatRoomLeft 채팅방에서 나간 사용자의 연결에서 구독을 해제하고 room_removed 전송
+func NotifyChatRoomLeft(roomID, userID uint) {
+	if GlobalHub == nil {
+		return
+	}
+	GlobalHub.UnsubscribeUsers(roomID, []uint{userID})
+	GlobalHub.SendToUsers(map[uint]*Message{
+		userID: {
+			Type:   "room_removed",
+			RoomID: roomID,
+			UserID: userID,
+			Data:   map[string]interface{}{"room_id": roomID},
+		},
+	})
+}
+
+// publishRoomUpdate 멤버들의 채팅방 목록 갱신 (마지막 메시지와 각자의 읽지 않은 메시지 수)
+// userIDs를 지정하면 해당 멤버에게만 보낸다.
+func publishRoomUpdate(roomID uint, event string, userIDs ...uint) {
+	query := database.DB.Model(&models.ChatRoomMember{}).Select("user_id, unread_count").Where("chat_room_id = ?", roomID)
+	if len(userIDs) > 0 {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+	var members []models.ChatRoomMember
+	if err := query.Find(&members).Error; err != nil {
+		log.Printf("Failed to load members of chat room %d for room_update: %v", roomID, err)
+		return
+	}
+	if len(members) == 0 {
+		return
+	}
+
+	var room models.ChatRoom
+	if err := database.DB.Select("id, last_message, last_message_at").First(&room, roomID).Error; err != nil {
+		log.Printf("Failed to load chat room %d for room_update: %v", roomID, err)
+		return
+	}
+
+	messages := make(map[uint]*Message, len(members))
+	for _, member := range members {
+		messages[member.UserID] = &Message{
+			Type:   "room_update",
+			RoomID: roomID,
+			UserID: member.UserID,
+			Data: map[string]interface{}{
+				"room_id":         roomID,
+				"event":           event,
+				"last_message":    room.LastMessage,
+				"last_message_at": room.LastMessageAt,
+				"unread_count":    member.UnreadCount,
+			},
+		}
+	}
+	GlobalHub.SendToUsers(messages)
+}
+
+// checkChatMember 채팅방이 있고 사용자가 멤버인지 확인
+func checkChatMember(tx *gorm.DB, roomID, userID uint) error {
+	var room models.ChatRoom
+	if err := tx.Select("id").First(&room, roomID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChatRoomNotFound
+		}
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.ChatRoomMember{}).Where("chat_room_id = ? AND user_id = ?", roomID, userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotChatMember
+	}
+	return nil
+}
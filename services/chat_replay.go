@@ -0,0 +1,94 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+
+	"ongi-back/database"
+	"ongi-back/models"
+)
+
+// maxReplayPerRoom 채팅방별로 다시 보내는 최대 메시지/이벤트 수 (더 있으면 truncated, 나머지는 HTTP로 조회)
+const maxReplayPerRoom = 100
+
+// LoadRoomReplay 채팅방의 afterSeq 이후 메시지와 멤버 이벤트를 순번 순으로 (최대 limit개, 더 있으면 truncated=true)
+// 실시간으로 보낸 것과 같은 message, member_join, member_leave 프레임으로 만든다.
+func LoadRoomReplay(roomID uint, afterSeq int64, limit int) ([]*Message, bool, error) {
+	var messages []models.ChatMessage
+	if err := database.DB.Preload("User").
+		Where("chat_room_id = ? AND seq > ?", roomID, afterSeq).
+		Order("seq").
+		Limit(limit + 1).
+		Find(&messages).Error; err != nil {
+		return nil, false, err
+	}
+
+	var events []models.ChatRoomEvent
+	if err := database.DB.
+		Where("chat_room_id = ? AND seq > ?", roomID, afterSeq).
+		Order("seq").
+		Limit(limit + 1).
+		Find(&events).Error; err != nil {
+		return nil, false, err
+	}
+
+	frames := make([]*Message, 0, len(messages)+len(events))
+	for _, message := range messages {
+		frames = append(frames, &Message{
+			Type:   "message",
+			RoomID: roomID,
+			UserID: message.UserID,
+			Seq:    message.Seq,
+			Data:   message,
+		})
+	}
+	for _, event := range events {
+		var data interface{}
+		if event.Data != "" {
+			data = json.RawMessage(event.Data)
+		}
+		frames = append(frames, &Message{
+			Type:   event.Type,
+			RoomID: roomID,
+			UserID: event.UserID,
+			Seq:    event.Seq,
+			Data:   data,
+		})
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].Seq < frames[j].Seq })
+
+	truncated := len(frames) > limit
+	if truncated {
+		frames = frames[:limit]
+	}
+	return frames, truncated, nil
+}
+
+// ReplayCursorsFromMessage last_message_id 기준 채팅방별 재전송 시작 순번
+// 메시지 ID는 모든 채팅방에서 증가하므로, 채팅방마다 그 ID 이하인 마지막 메시지의 순번 다음부터 다시 보낸다.
+// 그 뒤의 멤버 이벤트는 이미 받았더라도 다시 보내므로 클라이언트는 받은 순번 이하 프레임을 무시해야 한다.
+func ReplayCursorsFromMessage(lastMessageID uint, roomIDs []uint) (map[uint]int64, error) {
+	cursors := make(map[uint]int64, len(roomIDs))
+	if len(roomIDs) == 0 {
+		return cursors, nil
+	}
+	for _, roomID := range roomIDs {
+		cursors[roomID] = 0
+	}
+
+	var rows []struct {
+		ChatRoomID uint
+		Seq        int64
+	}
+	if err := database.DB.Model(&models.ChatMessage{}).
+		Select("chat_room_id, MAX(seq) AS seq").
+		Where("chat_room_id IN ? AND id <= ?", roomIDs, lastMessageID).
+		Group("chat_room_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		cursors[row.ChatRoomID] = row.Seq
+	}
+	return cursors, nil
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"ongi-back/database"
+	"ongi-back/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 클럽 배정 실패 이유
+const (
+	AssignmentNoCandidates   = "no_candidates"    // 후보 클럽 없음
+	AssignmentNoEligibleClub = "no_eligible_club" // 정원, 카테고리 중복 또는 기존 가입으로 배정할 클럽 없음
+)
+
+// 후보 클럽을 건너뛴 이유
+const (
+	SkipAlreadyMember    = "already_member"    // 모두 이미 가입
+	SkipCategoryConflict = "category_conflict" // 가입하지 않은 사용자가 모두 같은 카테고리 클럽에 가입되어 있음
+	SkipFull             = "full"              // 묶음 전체가 들어갈 자리가 없음
+)
+
+// 직접 가입 실패 이유
+var (
+	ErrClubNotFound  = errors.New("club not found")
+	ErrClubFull      = errors.New("club is full")
+	ErrAlreadyMember = errors.New("already a member of this club")
+)
+
+// AssignmentUnit 함께 배정할 사용자 묶음 (개인 또는 그룹)
+type AssignmentUnit struct {
+	Key        string // 결과 식별용 (예: "user:1", "group:3")
+	UserIDs    []uint
+	Candidates []AssignmentCandidate // 후보 클럽 (점수가 높을수록 선호)
+	MaxClubs   int                   // 최대 배정 클럽 수 (0이면 1)
+}
+
+// AssignmentCandidate 후보 클럽과 선호 점수
+type AssignmentCandidate struct {
+	ClubID uint
+	Score  float64
+}
+
+// ClubJoin 한 클럽에 대한 배정
+type ClubJoin struct {
+	ClubID            uint    `json:"club_id"`
+	ClubName          string  `json:"club_name"`
+	Category          string  `json:"category"`
+	Score             float64 `json:"score"`
+	JoinedUsers       []uint  `json:"joined_users"`                 // 새로 가입하는 사용자
+	AlreadyMembers    []uint  `json:"already_members,omitempty"`    // 이미 가입한 사용자
+	CategoryConflicts []uint  `json:"category_conflicts,omitempty"` // 같은 카테고리 클럽에 이미 가입해 제외된 사용자
+}
+
+// SkippedClub 검토했지만 배정하지 않은 후보 클럽
+type SkippedClub struct {
+	ClubID uint   `json:"club_id"`
+	Reason string `json:"reason"`
+}
+
+// UnitAssignment 묶음별 배정 결과
+type UnitAssignment struct {
+	Key     string        `json:"key"`
+	UserIDs []uint        `json:"user_ids"`
+	Clubs   []ClubJoin    `json:"clubs"`
+	Skipped []SkippedClub `json:"skipped,omitempty"`
+	Reason  string        `json:"reason,omitempty"` // 배정된 클럽이 없을 때 이유
+}
+
+// AssignmentPlan 클럽 배정 계획 (DryRun이면 저장되지 않은 제안)
+type AssignmentPlan struct {
+	DryRun     bool             `json:"dry_run"`
+	Units      []UnitAssignment `json:"units"`
+	TotalJoins int              `json:"total_joins"`
+}
+
+// clubMembership 사용자의 기존 클럽 가입 (카테고리 중복 확인용)
+type clubMembership struct {
+	UserID   uint
+	ClubID   uint
+	Category string
+}
+
+// AssignClubs 제약 조건을 지키며 사용자 묶음을 클럽에 배정
+// - 정원: 클럽의 MaxMembers를 넘지 않음 (0이면 제한 없음)
+// - 카테고리: 사용자마다 카테고리당 클럽 하나 (기존 가입 포함)
+// - 선호: 모든 (묶음, 후보) 쌍 중 점수가 높은 쌍부터 배정
+// 후보 클럽 행을 잠근 하나의 트랜잭션에서 계획과 저장을 하므로 동시에 실행되어도 정원을 넘지 않는다.
+// dryRun이면 잠금과 저장 없이 계획만 반환한다.
+func AssignClubs(units []AssignmentUnit, dryRun bool) (*AssignmentPlan, error) {
+	clubIDSet := map[uint]bool{}
+	userIDSet := map[uint]bool{}
+	for _, unit := range units {
+		for _, candidate := range unit.Candidates {
+			clubIDSet[candidate.ClubID] = true
+		}
+		for _, userID := range unit.UserIDs {
+			userIDSet[userID] = true
+		}
+	}
+	clubIDs := sortedIDs(clubIDSet)
+	userIDs := sortedIDs(userIDSet)
+
+	var plan *AssignmentPlan
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 1. 후보 클럽 잠금 (교착 상태를 피하려고 ID 순)
+		var clubs []models.Club
+		if len(clubIDs) > 0 {
+			query := tx.Where("id IN ?", clubIDs).Order("id")
+			if !dryRun {
+				query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+			}
+			if err := query.Find(&clubs).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. 기존 가입 (클럽 잠금 이후에 읽어야 동시 배정이 반영됨)
+		var memberships []clubMembership
+		if len(userIDs) > 0 {
+			if err := tx.Table("club_members").
+				Select("club_members.user_id, club_members.club_id, clubs.category").
+				Joins("JOIN clubs ON clubs.id = club_members.club_id").
+				Where("club_members.user_id IN ?", userIDs).
+				Scan(&memberships).Error; err != nil {
+				return err
+			}
+		}
+
+		plan = planClubAssignments(units, clubs, memberships)
+		plan.DryRun = dryRun
+		if dryRun {
+			return nil
+		}
+
+		// 3. 클럽별로 한 번에 저장하고 실제 추가된 행 수만큼 멤버 수 증가
+		now := time.Now()
+		joins := map[uint][]models.ClubMember{}
+		for _, unit := range plan.Units {
+			for _, join := range unit.Clubs {
+				for _, userID := range join.JoinedUsers {
+					joins[join.ClubID] = append(joins[join.ClubID], models.ClubMember{ClubID: join.ClubID, UserID: userID, JoinedAt: now})
+				}
+			}
+		}
+		for _, clubID := range clubIDs {
+			rows := joins[clubID]
+			if len(rows) == 0 {
+				continue
+			}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				if err := tx.Model(&models.Club{}).Where("id = ?", clubID).
+					UpdateColumn("member_count", gorm.Expr("member_count + ?", result.RowsAffected)).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// JoinClub 사용자가 직접 클럽에 가입 (카테고리 제한 없이 정원만 확인)
+// AssignClubs와 같이 클럽 행을 잠근 트랜잭션에서 정원 확인, 가입, 멤버 수 증가를 하므로 동시에 가입해도 정원을 넘지 않는다.
+func JoinClub(userID, clubID uint) (*models.ClubMember, error) {
+	member := models.ClubMember{UserID: userID, ClubID: clubID, JoinedAt: time.Now()}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var club models.Club
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&club, clubID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrClubNotFound
+			}
+			return err
+		}
+		if club.MaxMembers > 0 && club.MemberCount >= club.MaxMembers {
+			return ErrClubFull
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&member)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyMember
+		}
+
+		return tx.Model(&club).UpdateColumn("member_count", gorm.Expr("member_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// planClubAssignments 탐욕적 최대 점수 배정 (점수 내림차순, 같으면 묶음 순서와 후보 순서)
+func planClubAssignments(units []AssignmentUnit, clubs []models.Club, memberships []clubMembership) *AssignmentPlan {
+	clubMap := make(map[uint]*models.Club, len(clubs))
+	remaining := make(map[uint]int, len(clubs)) // -1이면 제한 없음
+	for i := range clubs {
+		clubMap[clubs[i].ID] = &clubs[i]
+		remaining[clubs[i].ID] = -1
+		if clubs[i].MaxMembers > 0 {
+			remaining[clubs[i].ID] = clubs[i].MaxMembers - clubs[i].MemberCount
+		}
+	}
+
+	memberOf := map[uint]map[uint]bool{}
+	categoriesOf := map[uint]map[string]bool{}
+	join := func(userID, clubID uint, category string) {
+		if memberOf[userID] == nil {
+			memberOf[userID] = map[uint]bool{}
+			categoriesOf[userID] = map[string]bool{}
+		}
+		memberOf[userID][clubID] = true
+		if category != "" {
+			categoriesOf[userID][category] = true
+		}
+	}
+	for _, m := range memberships {
+		join(m.UserID, m.ClubID, m.Category)
+	}
+
+	type pair struct {
+		unit, order int
+		candidate   AssignmentCandidate
+	}
+	var pairs []pair
+	for u, unit := range units {
+		seen := map[uint]bool{}
+		for order, candidate := range unit.Candidates {
+			if clubMap[candidate.ClubID] == nil || seen[candidate.ClubID] {
+				continue
+			}
+			seen[candidate.ClubID] = true
+			pairs = append(pairs, pair{unit: u, order: order, candidate: candidate})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		if a.candidate.Score != b.candidate.Score {
+			return a.candidate.Score > b.candidate.Score
+		}
+		if a.unit != b.unit {
+			return a.unit < b.unit
+		}
+		return a.order < b.order
+	})
+
+	plan := &AssignmentPlan{Units: make([]UnitAssignment, len(units))}
+	for u, unit := range units {
+		plan.Units[u] = UnitAssignment{Key: unit.Key, UserIDs: unit.UserIDs, Clubs: []ClubJoin{}}
+	}
+
+	for _, p := range pairs {
+		unit := units[p.unit]
+		maxClubs := unit.MaxClubs
+		if maxClubs <= 0 {
+			maxClubs = 1
+		}
+		if len(plan.Units[p.unit].Clubs) >= maxClubs {
+			continue
+		}
+
+		club := clubMap[p.candidate.ClubID]
+		assignment := ClubJoin{ClubID: club.ID, ClubName: club.Name, Category: club.Category, Score: p.candidate.Score}
+		for _, userID := range unit.UserIDs {
+			switch {
+			case memberOf[userID][club.ID]:
+				assignment.AlreadyMembers = append(assignment.AlreadyMembers, userID)
+			case club.Category != "" && categoriesOf[userID][club.Category]:
+				assignment.CategoryConflicts = append(assignment.CategoryConflicts, userID)
+			default:
+				assignment.JoinedUsers = append(assignment.JoinedUsers, userID)
+			}
+		}
+
+		// 새로 가입할 사람이 없거나 함께 들어갈 자리가 없으면 다음 후보
+		skip := ""
+		switch {
+		case len(assignment.JoinedUsers) == 0 && len(assignment.CategoryConflicts) == 0:
+			skip = SkipAlreadyMember
+		case len(assignment.JoinedUsers) == 0:
+			skip = SkipCategoryConflict
+		case remaining[club.ID] >= 0 && remaining[club.ID] < len(assignment.JoinedUsers):
+			skip = SkipFull
+		}
+		if skip != "" {
+			plan.Units[p.unit].Skipped = append(plan.Units[p.unit].Skipped, SkippedClub{ClubID: club.ID, Reason: skip})
+			continue
+		}
+
+		if remaining[club.ID] >= 0 {
+			remaining[club.ID] -= len(assignment.JoinedUsers)
+		}
+		for _, userID := range assignment.JoinedUsers {
+			join(userID, club.ID, club.Category)
+		}
+		plan.Units[p.unit].Clubs = append(plan.Units[p.unit].Clubs, assignment)
+		plan.TotalJoins += len(assignment.JoinedUsers)
+	}
+
+	for u, unit := range units {
+		if len(plan.Units[u].Clubs) > 0 {
+			continue
+		}
+		if len(unit.Candidates) == 0 {
+			plan.Units[u].Reason = AssignmentNoCandidates
+		} else {
+			plan.Units[u].Reason = AssignmentNoEligibleClub
+		}
+	}
+	return plan
+}
+
+// RankedCandidates 추천 순서대로 후보 점수 부여 (앞일수록 높음, 중복 클럽은 첫 번째만)
+func RankedCandidates(clubs []models.Club) []AssignmentCandidate {
+	candidates := make([]AssignmentCandidate, 0, len(clubs))
+	seen := map[uint]bool{}
+	for _, club := range clubs {
+		if seen[club.ID] {
+			continue
+		}
+		seen[club.ID] = true
+		candidates = append(candidates, AssignmentCandidate{ClubID: club.ID})
+	}
+	for i := range candidates {
+		candidates[i].Score = float64(len(candidates) - i)
+	}
+	return candidates
+}
+
+func sortedIDs(set map[uint]bool) []uint {
+	ids := make([]uint, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"ongi-back/models"
+)
+
+func TestPlanClubAssignments(t *testing.T) {
+	clubs := []models.Club{
+		{ID: 1, Name: "러닝", Category: "운동", MaxMembers: 3, MemberCount: 2},
+		{ID: 2, Name: "독서", Category: "학습", MaxMembers: 10},
+		{ID: 3, Name: "등산", Category: "운동"},
+		{ID: 4, Name: "영화", Category: "문화", MaxMembers: 5, MemberCount: 5},
+	}
+
+	tests := []struct {
+		name        string
+		units       []AssignmentUnit
+		memberships []clubMembership
+		wantClubs   [][]uint        // 묶음별 배정 클럽
+		wantSkipped [][]SkippedClub // 묶음별 건너뛴 후보
+		wantReasons []string
+		wantJoins   int
+	}{
+		{
+			name: "group does not fit the last seat",
+			units: []AssignmentUnit{
+				{Key: "group:1", UserIDs: []uint{10, 11}, Candidates: []AssignmentCandidate{{ClubID: 1, Score: 90}, {ClubID: 2, Score: 80}}},
+			},
+			wantClubs:   [][]uint{{2}},
+			wantSkipped: [][]SkippedClub{{{ClubID: 1, Reason: SkipFull}}},
+			wantReasons: []string{""},
+			wantJoins:   2,
+		},
+		{
+			name: "higher score takes the last seat",
+			units: []AssignmentUnit{
+				{Key: "user:10", UserIDs: []uint{10}, Candidates: []AssignmentCandidate{{ClubID: 1, Score: 50}, {ClubID: 2, Score: 40}}},
+				{Key: "user:11", UserIDs: []uint{11}, Candidates: []AssignmentCandidate{{ClubID: 1, Score: 70}}},
+			},
+			wantClubs:   [][]uint{{2}, {1}},
+			wantSkipped: [][]SkippedClub{{{ClubID: 1, Reason: SkipFull}}, nil},
+			wantReasons: []string{"", ""},
+			wantJoins:   2,
+		},
+		{
+			name: "existing membership in the same category",
+			units: []AssignmentUnit{
+				{Key: "user:10", UserIDs: []uint{10}, Candidates: []AssignmentCandidate{{ClubID: 1, Score: 90}, {ClubID: 2, Score: 10}}},
+			},
+			memberships: []clubMembership{{UserID: 10, ClubID: 3, Category: "운동"}},
+			wantClubs:   [][]uint{{2}},
+			wantSkipped: [][]SkippedClub{{{ClubID: 1, Reason: SkipCategoryConflict}}},
+			wantReasons: []string{""},
+			wantJoins:   1,
+		},
+		{
+			name: "club planned earlier in the same category",
+			units: []AssignmentUnit{
+				{Key: "user:10", UserIDs: []uint{10}, MaxClubs: 2, Candidates: []AssignmentCandidate{{ClubID: 3, Score: 90}, {ClubID: 1, Score: 80}, {ClubID: 2, Score: 70}}},
+			},
+			wantClubs:   [][]uint{{3, 2}},
+			wantSkipped: [][]SkippedClub{{{ClubID: 1, Reason: SkipCategoryConflict}}},
+			wantReasons: []string{""},
+			wantJoins:   2,
+		},
+		{
+			name: "already member",
+			units: []AssignmentUnit{
+				{Key: "user:10", UserIDs: []uint{10}, Candidates: []AssignmentCandidate{{ClubID: 2, Score: 90}}},
+			},
+			memberships: []clubMembership{{UserID: 10, ClubID: 2, Category: "학습"}},
+			wantClubs:   [][]uint{nil},
+			wantSkipped: [][]SkippedClub{{{ClubID: 2, Reason: SkipAlreadyMember}}},
+			wantReasons: []string{AssignmentNoEligibleClub},
+		},
+		{
+			name: "no eligible or unknown candidates",
+			units: []AssignmentUnit{
+				{Key: "user:10", UserIDs: []uint{10}, Candidates: []AssignmentCandidate{{ClubID: 4, Score: 90}, {ClubID: 99, Score: 80}}},
+				{Key: "user:11", UserIDs: []uint{11}},
+			},
+			wantClubs:   [][]uint{nil, nil},
+			wantSkipped: [][]SkippedClub{{{ClubID: 4, Reason: SkipFull}}, nil},
+			wantReasons: []string{AssignmentNoEligibleClub, AssignmentNoCandidates},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// planClubAssignments는 클럽 슬라이스를 바꾸지 않지만 케이스마다 새로 복사한다
+			plan := planClubAssignments(tt.units, append([]models.Club(nil), clubs...), tt.memberships)
+
+			for u, unit := range plan.Units {
+				var got []uint
+				for _, c := range unit.Clubs {
+					got = append(got, c.ClubID)
+				}
+				if !reflect.DeepEqual(got, tt.wantClubs[u]) {
+					t.Errorf("%s clubs = %v, want %v", unit.Key, got, tt.wantClubs[u])
+				}
+				if !reflect.DeepEqual(unit.Skipped, tt.wantSkipped[u]) {
+					t.Errorf("%s skipped = %v, want %v", unit.Key, unit.Skipped, tt.wantSkipped[u])
+				}
+				if unit.Reason != tt.wantReasons[u] {
+					t.Errorf("%s reason = %q, want %q", unit.Key, unit.Reason, tt.wantReasons[u])
+				}
+			}
+			if plan.TotalJoins != tt.wantJoins {
+				t.Errorf("TotalJoins = %d, want %d", plan.TotalJoins, tt.wantJoins)
+			}
+		})
+	}
+}
+
+func TestPlanClubAssignmentsNeverOverAllocates(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	categories := []string{"운동", "학습", "문화", ""}
+
+	var clubs []models.Club
+	for id := uint(1); id <= 12; id++ {
+		club := models.Club{ID: id, Name: fmt.Sprintf("club %d", id), Category: categories[rng.Intn(len(categories))]}
+		if id%4 != 0 {
+			club.MaxMembers = 2 + rng.Intn(6)
+			club.MemberCount = rng.Intn(club.MaxMembers + 1)
+		}
+		clubs = append(clubs, club)
+	}
+
+	var units []AssignmentUnit
+	var memberships []clubMembership
+	userID := uint(100)
+	for u := 0; u < 40; u++ {
+		unit := AssignmentUnit{Key: fmt.Sprintf("unit:%d", u), MaxClubs: 1 + rng.Intn(3)}
+		for n := 1 + rng.Intn(3); n > 0; n-- {
+			unit.UserIDs = append(unit.UserIDs, userID)
+			if rng.Intn(4) == 0 {
+				club := clubs[rng.Intn(len(clubs))]
+				memberships = append(memberships, clubMembership{UserID: userID, ClubID: club.ID, Category: club.Category})
+			}
+			userID++
+		}
+		for n := rng.Intn(6); n > 0; n-- {
+			unit.Candidates = append(unit.Candidates, AssignmentCandidate{ClubID: uint(1 + rng.Intn(len(clubs))), Score: float64(rng.Intn(100))})
+		}
+		units = append(units, unit)
+	}
+
+	plan := planClubAssignments(units, clubs, memberships)
+
+	joined := map[uint]int{}
+	categoriesOf := map[uint]map[string]bool{}
+	memberOf := map[[2]uint]bool{}
+	for _, m := range memberships {
+		memberOf[[2]uint{m.UserID, m.ClubID}] = true
+		if categoriesOf[m.UserID] == nil {
+			categoriesOf[m.UserID] = map[string]bool{}
+		}
+		if m.Category != "" {
+			categoriesOf[m.UserID][m.Category] = true
+		}
+	}
+
+	totalJoins, skips := 0, map[string]int{}
+	for u, unit := range plan.Units {
+		for _, s := range unit.Skipped {
+			skips[s.Reason]++
+		}
+		maxClubs := units[u].MaxClubs
+		if len(unit.Clubs) > maxClubs {
+			t.Errorf("%s got %d clubs, max %d", unit.Key, len(unit.Clubs), maxClubs)
+		}
+		for _, c := range unit.Clubs {
+			joined[c.ClubID] += len(c.JoinedUsers)
+			totalJoins += len(c.JoinedUsers)
+			for _, id := range c.JoinedUsers {
+				if memberOf[[2]uint{id, c.ClubID}] {
+					t.Errorf("user %d joined club %d twice", id, c.ClubID)
+				}
+				memberOf[[2]uint{id, c.ClubID}] = true
+				if c.Category == "" {
+					continue
+				}
+				if categoriesOf[id] == nil {
+					categoriesOf[id] = map[string]bool{}
+				}
+				if categoriesOf[id][c.Category] {
+					t.Errorf("user %d joined a second %q club (%d)", id, c.Category, c.ClubID)
+				}
+				categoriesOf[id][c.Category] = true
+			}
+		}
+	}
+
+	for _, club := range clubs {
+		if club.MaxMembers > 0 && club.MemberCount+joined[club.ID] > club.MaxMembers {
+			t.Errorf("club %d: %d members + %d joins exceeds max %d", club.ID, club.MemberCount, joined[club.ID], club.MaxMembers)
+		}
+	}
+	if plan.TotalJoins != totalJoins {
+		t.Errorf("TotalJoins = %d, want %d", plan.TotalJoins, totalJoins)
+	}
+	// 제약이 실제로 걸리는 입력인지 확인
+	if totalJoins == 0 || skips[SkipFull] == 0 || skips[SkipCategoryConflict] == 0 {
+		t.Errorf("expected joins and both full and category_conflict skips, got %d joins and skips %v", totalJoins, skips)
+	}
+}
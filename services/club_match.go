@@ -0,0 +1,257 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// DefaultClubTolerance 허용 오차를 지정하지 않은 차원의 기본값 (점수 단위)
+const DefaultClubTolerance = 15.0
+
+// ClubTarget 클럽이 선호하는 성향 (Club.PreferredScores, Club.Tolerances)
+// 목표 점수가 없는 차원은 비교하지 않는다.
+type ClubTarget struct {
+	Scores     map[string]float64 `json:"scores"`
+	Tolerances map[string]float64 `json:"tolerances,omitempty"`
+}
+
+// ClubTargetError 클럽 선호 성향 입력 오류
+type ClubTargetError struct {
+	Reason string
+}
+
+func (e *ClubTargetError) Error() string {
+	return "invalid club target: " + e.Reason
+}
+
+// ClubFilter 클럽 추천 조건 (빈 값은 조건 없음)
+type ClubFilter struct {
+	Tags        []string // 모든 태그를 포함하는 클럽만
+	Vibe        string   // 분위기 (cozy, energetic, casual, deep, chill)
+	Location    string   // 지역 (강남, 홍대 등)
+	ExcludeFull bool     // 정원이 찬 클럽 제외
+
+	// AsOf 이 시각 이전의 피드백과 가입만 전략에 반영 (오프라인 평가용, 비어 있으면 현재)
+	AsOf time.Time
+}
+
+// ClubMatch 클럽 추천 결과
+type ClubMatch struct {
+	Club            models.Club `json:"club"`
+	Score           float64     `json:"score"`            // 허용 오차를 벗어난 거리로 계산한 적합도 (0-100, 추천 시 피드백 반영)
+	Distance        float64     `json:"distance"`         // 목표 점수와의 유클리드 거리
+	WithinTolerance bool        `json:"within_tolerance"` // 모든 차원이 허용 오차 안에 있는지
+	HasTarget       bool        `json:"has_target"`       // 선호 성향이 설정된 클럽인지 (없으면 가장 뒤에 정렬)
+
+	// 전략이 반영하기 전 적합도와 반영한 신호 (RecommendClubMatches에서만 채움)
+	ContentScore       float64 `json:"content_score"`
+	CollaborativeScore float64 `json:"collaborative_score"` // 유사 응답자의 가입/클릭/관심 없음 (-1~1)
+	FeedbackScore      float64 `json:"feedback_score"`      // 본인의 분위기/카테고리 반응 (-1~1)
+}
+
+// ParseClubTarget 클럽의 선호 성향 파싱 (설정되지 않았으면 nil)
+func ParseClubTarget(club *models.Club) (*ClubTarget, error) {
+	if strings.TrimSpace(club.PreferredScores) == "" {
+		return nil, nil
+	}
+
+	target := &ClubTarget{}
+	if err := json.Unmarshal([]byte(club.PreferredScores), &target.Scores); err != nil {
+		return nil, &ClubTargetError{Reason: fmt.Sprintf("club %d preferred_scores: %v", club.ID, err)}
+	}
+	if strings.TrimSpace(club.Tolerances) != "" {
+		if err := json.Unmarshal([]byte(club.Tolerances), &target.Tolerances); err != nil {
+			return nil, &ClubTargetError{Reason: fmt.Sprintf("club %d tolerances: %v", club.ID, err)}
+		}
+	}
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	if len(target.Scores) == 0 {
+		return nil, nil
+	}
+	return target, nil
+}
+
+// Validate 목표 점수는 0-100, 허용 오차는 0-100이며 알려진 차원만 사용할 수 있다
+func (t *ClubTarget) Validate() error {
+	for dimension, score := range t.Scores {
+		if !models.IsValidDimension(dimension) {
+			return &ClubTargetError{Reason: "unknown dimension " + dimension}
+		}
+		if score < 0 || score > 100 || math.IsNaN(score) {
+			return &ClubTargetError{Reason: dimension + " score must be between 0 and 100"}
+		}
+	}
+	for dimension, tolerance := range t.Tolerances {
+		if !models.IsValidDimension(dimension) {
+			return &ClubTargetError{Reason: "unknown dimension " + dimension}
+		}
+		if tolerance < 0 || tolerance > 100 || math.IsNaN(tolerance) {
+			return &ClubTargetError{Reason: dimension + " tolerance must be between 0 and 100"}
+		}
+	}
+	return nil
+}
+
+// Encode Club.PreferredScores, Club.Tolerances에 저장할 JSON
+func (t *ClubTarget) Encode() (preferredScores, tolerances string, err error) {
+	if err := t.Validate(); err != nil {
+		return "", "", err
+	}
+	if len(t.Scores) > 0 {
+		data, _ := json.Marshal(t.Scores)
+		preferredScores = string(data)
+	}
+	if len(t.Tolerances) > 0 {
+		data, _ := json.Marshal(t.Tolerances)
+		tolerances = string(data)
+	}
+	return preferredScores, tolerances, nil
+}
+
+// Match 성향 벡터와 클럽 선호 성향 비교
+// 차원별로 허용 오차를 넘는 차이만 거리로 보고, 가능한 최대 초과 거리 대비 비율을 0-100점으로 변환한다.
+func (t *ClubTarget) Match(v *utils.Vector5D) (score, distance float64, within bool) {
+	scores := vectorByDimension(v)
+
+	var excessSq, maxExcessSq, distanceSq float64
+	within = true
+	for _, dimension := range models.Dimensions {
+		target, ok := t.Scores[dimension]
+		if !ok {
+			continue
+		}
+		tolerance, ok := t.Tolerances[dimension]
+		if !ok {
+			tolerance = DefaultClubTolerance
+		}
+
+		diff := math.Abs(scores[dimension] - target)
+		distanceSq += diff * diff
+		if excess := diff - tolerance; excess > 0 {
+			excessSq += excess * excess
+			within = false
+		}
+		if maxExcess := math.Max(target, 100-target) - tolerance; maxExcess > 0 {
+			maxExcessSq += maxExcess * maxExcess
+		}
+	}
+
+	score = 100
+	if maxExcessSq > 0 {
+		score = math.Max(0, (1-math.Sqrt(excessSq/maxExcessSq))*100)
+	}
+	return math.Round(score*10) / 10, math.Round(math.Sqrt(distanceSq)*10) / 10, within
+}
+
+// Matches 필터 조건을 만족하는지
+func (f ClubFilter) Matches(club *models.Club) bool {
+	if f.Vibe != "" && !strings.EqualFold(club.Vibe, f.Vibe) {
+		return false
+	}
+	if f.Location != "" && !strings.EqualFold(club.Location, f.Location) {
+		return false
+	}
+	if f.ExcludeFull && club.MaxMembers > 0 && club.MemberCount >= club.MaxMembers {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		tags := clubTags(club)
+		for _, tag := range f.Tags {
+			if !tags[strings.ToLower(tag)] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// clubTags 클럽 태그 (JSON 배열, 소문자로 비교)
+func clubTags(club *models.Club) map[string]bool {
+	var list []string
+	if club.Tags != "" {
+		_ = json.Unmarshal([]byte(club.Tags), &list)
+	}
+	tags := make(map[string]bool, len(list))
+	for _, tag := range list {
+		tags[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	return tags
+}
+
+// RankClubs 성향 벡터에 맞는 순서로 클럽 정렬 (적합도 내림차순, 같으면 거리 오름차순)
+// 필터를 만족하지 않는 클럽은 제외하고, 선호 성향이 없거나 잘못된 클럽은 가장 뒤에 둔다.
+func RankClubs(v *utils.Vector5D, clubs []models.Club, filter ClubFilter) []ClubMatch {
+	matches := make([]ClubMatch, 0, len(clubs))
+	for i := range clubs {
+		if !filter.Matches(&clubs[i]) {
+			continue
+		}
+
+		match := ClubMatch{Club: clubs[i]}
+		if target, err := ParseClubTarget(&clubs[i]); err == nil && target != nil {
+			match.Score, match.Distance, match.WithinTolerance = target.Match(v)
+			match.HasTarget = true
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.HasTarget != b.HasTarget {
+			return a.HasTarget
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Club.ID < b.Club.ID
+	})
+	return matches
+}
+
+// RecommendClubMatches - 응답자에게 배정된 추천 전략으로 클럽 추천
+// 실행 중인 실험이 있으면 배정된 변형의 전략, 없으면 RECOMMENDATION_STRATEGY (기본은 추천 피드백 반영)
+func RecommendClubMatches(r Respondent, limit int, filter ClubFilter) ([]ClubMatch, error) {
+	v, err := RespondentVector(r)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.DB.Preload("Members")
+	if filter.Vibe != "" {
+		query = query.Where("LOWER(vibe) = LOWER(?)", filter.Vibe)
+	}
+	if filter.Location != "" {
+		query = query.Where("LOWER(location) = LOWER(?)", filter.Location)
+	}
+
+	var clubs []models.Club
+	if err := query.Find(&clubs).Error; err != nil {
+		return nil, err
+	}
+
+	strategy, err := clubStrategyFor(r)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := strategy.Rank(r, v, clubs, filter)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
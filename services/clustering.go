@@ -0,0 +1,113 @@
+package services
+
+import (
+	"sort"
+
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// DefaultMaxGroupSize 그룹 매칭의 기본 최대 그룹 인원
+const DefaultMaxGroupSize = 8
+
+// MatchOptions 전체 사용자 그룹 매칭 옵션
+type MatchOptions struct {
+	K            int   `json:"k"`              // 그룹 수 (0이면 인원 / 최대 그룹 인원으로 계산)
+	MaxGroupSize int   `json:"max_group_size"` // 그룹 최대 인원 (0이면 DefaultMaxGroupSize, 클럽의 남은 정원을 넘지 않음)
+	Seed         int64 `json:"seed"`           // 군집화 시드 (같은 데이터와 시드면 같은 그룹)
+	DryRun       bool  `json:"dry_run"`        // 저장하지 않고 배정 계획만 반환
+}
+
+// GroupAssignment 그룹별 매칭 결과
+type GroupAssignment struct {
+	UserIDs  []uint          `json:"user_ids"`
+	Centroid *utils.Vector5D `json:"centroid"`          // 그룹 평균 성향
+	ClubID   *uint           `json:"club_id,omitempty"` // 배정된 클럽 (정원이 맞는 클럽이 없으면 nil)
+	Added    int             `json:"added"`             // 새로 가입한 인원 (이미 멤버이거나 같은 카테고리 클럽에 가입한 사용자 제외)
+	Reason   string          `json:"reason,omitempty"`  // 배정된 클럽이 없을 때 이유
+}
+
+// MatchReport 전체 사용자 그룹 매칭 결과
+type MatchReport struct {
+	Algorithm       string               `json:"algorithm"`
+	DryRun          bool                 `json:"dry_run"`
+	Seed            int64                `json:"seed"`
+	K               int                  `json:"k"`
+	MaxGroupSize    int                  `json:"max_group_size"`
+	Iterations      int                  `json:"iterations"`
+	Converged       bool                 `json:"converged"`
+	Quality         utils.ClusterQuality `json:"quality"`
+	Groups          []GroupAssignment    `json:"groups"`
+	MatchedUsers    int                  `json:"matched_users"`    // 클럽이 배정된 그룹의 인원
+	UnmatchedGroups int                  `json:"unmatched_groups"` // 배정할 수 있는 클럽이 없던 그룹 수
+	Assignment      *AssignmentPlan      `json:"assignment"`       // 클럽 배정 계획 (그룹 순서와 같음)
+}
+
+// clusterProfiles 프로필 벡터를 크기가 제한된 k-평균으로 그룹화
+// 그룹 수는 K 또는 ceil(인원 / 최대 인원)이고, 그룹 크기는 ceil(인원 / 그룹 수)를 넘지 않아 고르게 나뉜다.
+func clusterProfiles(profiles []models.UserProfile, opts MatchOptions, maxGroupSize int) ([]UserGroup, *MatchReport, error) {
+	report := &MatchReport{Algorithm: "kmeans", Seed: opts.Seed, MaxGroupSize: maxGroupSize, Groups: []GroupAssignment{}}
+	if len(profiles) == 0 || maxGroupSize <= 0 {
+		report.Converged = true
+		return nil, report, nil
+	}
+
+	// DB 조회 순서와 무관하도록 사용자 ID 순으로 정렬
+	sort.SliceStable(profiles, func(i, j int) bool { return profiles[i].UserID < profiles[j].UserID })
+
+	n := len(profiles)
+	k := opts.K
+	if k <= 0 {
+		k = (n + maxGroupSize - 1) / maxGroupSize
+	}
+	if k > n {
+		k = n
+	}
+	capacity := (n + k - 1) / k
+	if capacity > maxGroupSize {
+		return nil, nil, utils.ErrClusterCapacity
+	}
+	report.K = k
+
+	points := make([]*utils.Vector5D, n)
+	for i := range profiles {
+		points[i] = profileVector(&profiles[i])
+	}
+
+	result, err := utils.KMeans(points, utils.ClusterOptions{K: k, MaxSize: capacity, Seed: opts.Seed})
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Iterations = result.Iterations
+	report.Converged = result.Converged
+	report.Quality = utils.EvaluateClusters(points, result)
+
+	groups := make([]UserGroup, len(result.Centroids))
+	for i, cluster := range result.Assignments {
+		groups[cluster].Users = append(groups[cluster].Users, profiles[i].User)
+	}
+	for cluster, centroid := range result.Centroids {
+		groups[cluster].AvgProfile = models.UserProfile{
+			SocialityScore:   centroid.Sociality,
+			ActivityScore:    centroid.Activity,
+			IntimacyScore:    centroid.Intimacy,
+			ImmersionScore:   centroid.Immersion,
+			FlexibilityScore: centroid.Flexibility,
+		}
+	}
+	return groups, report, nil
+}
+
+// remainingGroupCapacity 클럽들이 받을 수 있는 최대 그룹 인원 (정원 제한 없는 클럽이 있으면 -1)
+func remainingGroupCapacity(clubs []models.Club) int {
+	best := 0
+	for _, club := range clubs {
+		if club.MaxMembers <= 0 {
+			return -1
+		}
+		if remaining := club.MaxMembers - club.MemberCount; remaining > best {
+			best = remaining
+		}
+	}
+	return best
+}
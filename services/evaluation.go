@@ -0,0 +1,283 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// DefaultEvaluationK 평가할 추천 목록 길이 기본값
+const DefaultEvaluationK = 10
+
+// EvaluationOptions 오프라인 추천 평가 옵션
+type EvaluationOptions struct {
+	K          int                   // 상위 K개 추천으로 평가 (0이면 DefaultEvaluationK)
+	Strategies []string              // 평가할 전략 (비어 있으면 등록된 전략 전체)
+	Since      time.Time             // 이 시각 이후의 가입만 정답으로 사용 (이전 가입 클럽은 후보에서 제외)
+	MaxUsers   int                   // 평가할 최대 회원 수 (회원 ID 순, 0이면 전체)
+	Progress   func(done, total int) // 진행 상황 (선택)
+}
+
+// StrategyEvaluation 전략별 평균 지표
+type StrategyEvaluation struct {
+	Strategy     string  `json:"strategy"`
+	PrecisionAtK float64 `json:"precision_at_k"` // 상위 K개 중 실제 가입한 클럽 비율
+	RecallAtK    float64 `json:"recall_at_k"`    // 실제 가입한 클럽 중 상위 K개에 든 비율
+	NDCG         float64 `json:"ndcg"`           // 상위 K개의 순위 가중 적중 (0-1)
+	HitRate      float64 `json:"hit_rate"`       // 상위 K개에 가입한 클럽이 하나라도 있는 회원 비율
+	Coverage     float64 `json:"coverage"`       // 한 번이라도 상위 K개에 추천된 클럽 비율
+}
+
+// EvaluationReport 오프라인 추천 평가 결과
+type EvaluationReport struct {
+	K            int                  `json:"k"`
+	Since        *time.Time           `json:"since,omitempty"`
+	Clubs        int                  `json:"clubs"`         // 전체 클럽 수 (coverage 분모)
+	Users        int                  `json:"users"`         // 평가한 회원 수
+	Joins        int                  `json:"joins"`         // 정답으로 쓴 가입 수
+	FromSessions int                  `json:"from_sessions"` // 세션 벡터로 평가한 회원 수
+	FromProfiles int                  `json:"from_profiles"` // 프로필 벡터로 평가한 회원 수
+	Skipped      int                  `json:"skipped"`       // 성향 벡터가 없어 제외한 회원 수
+	Strategies   []StrategyEvaluation `json:"strategies"`
+	GeneratedAt  time.Time            `json:"generated_at"`
+}
+
+// evaluationCase 한 회원의 평가 입력
+type evaluationCase struct {
+	respondent Respondent
+	vector     *utils.Vector5D
+	known      map[uint]bool // Since 이전에 가입한 클럽 (후보에서 제외)
+	relevant   map[uint]bool // 정답 (Since 이후 가입한 클럽)
+	asOf       time.Time     // 정답 중 첫 가입 시각 (전략은 이 전의 피드백과 가입만 반영)
+}
+
+// EvaluateStrategies 과거 클럽 가입을 다시 재생해 추천 전략 비교
+// 회원마다 가입 전에 응답한 비회원 세션 벡터(연동된 세션)로 추천을 만들고, 없으면 프로필 벡터를 쓴다.
+// 전략에는 정답 중 첫 가입 이전에 기록된 본인/유사 응답자의 피드백과 가입만 넘기므로 정답을 미리 보지 않는다.
+// 유사 응답자를 찾는 성향 벡터는 현재 값을 쓰므로 실제 서비스 시점과 조금 다를 수 있다.
+func EvaluateStrategies(opts EvaluationOptions) (*EvaluationReport, error) {
+	k := opts.K
+	if k <= 0 {
+		k = DefaultEvaluationK
+	}
+
+	strategies := ClubStrategies()
+	if len(opts.Strategies) > 0 {
+		strategies = nil
+		for _, name := range opts.Strategies {
+			strategy, err := ResolveClubStrategy(name)
+			if err != nil {
+				return nil, err
+			}
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	var clubs []models.Club
+	if err := database.DB.Order("id").Find(&clubs).Error; err != nil {
+		return nil, err
+	}
+
+	cases, skipped, err := loadEvaluationCases(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &EvaluationReport{
+		K:           k,
+		Clubs:       len(clubs),
+		Users:       len(cases),
+		Skipped:     skipped,
+		Strategies:  make([]StrategyEvaluation, len(strategies)),
+		GeneratedAt: time.Now(),
+	}
+	if !opts.Since.IsZero() {
+		since := opts.Since
+		report.Since = &since
+	}
+
+	recommended := make([]map[uint]bool, len(strategies))
+	for s := range strategies {
+		report.Strategies[s].Strategy = strategies[s].Name()
+		recommended[s] = map[uint]bool{}
+	}
+
+	for i, c := range cases {
+		report.Joins += len(c.relevant)
+		if c.respondent.IsUser() {
+			report.FromProfiles++
+		} else {
+			report.FromSessions++
+		}
+
+		candidates := make([]models.Club, 0, len(clubs))
+		for _, club := range clubs {
+			if !c.known[club.ID] {
+				candidates = append(candidates, club)
+			}
+		}
+
+		for s, strategy := range strategies {
+			matches, err := strategy.Rank(c.respondent, c.vector, candidates, ClubFilter{AsOf: c.asOf})
+			if err != nil {
+				return nil, fmt.Errorf("%s for %s: %w", strategy.Name(), c.respondent, err)
+			}
+			if len(matches) > k {
+				matches = matches[:k]
+			}
+
+			ranked := make([]uint, len(matches))
+			for j, match := range matches {
+				ranked[j] = match.Club.ID
+				recommended[s][match.Club.ID] = true
+			}
+			precision, recall, ndcg := rankingMetrics(ranked, c.relevant, k)
+			report.Strategies[s].PrecisionAtK += precision
+			report.Strategies[s].RecallAtK += recall
+			report.Strategies[s].NDCG += ndcg
+			if precision > 0 {
+				report.Strategies[s].HitRate++
+			}
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(cases))
+		}
+	}
+
+	for s := range report.Strategies {
+		result := &report.Strategies[s]
+		if len(cases) > 0 {
+			n := float64(len(cases))
+			result.PrecisionAtK = roundMetric(result.PrecisionAtK / n)
+			result.RecallAtK = roundMetric(result.RecallAtK / n)
+			result.NDCG = roundMetric(result.NDCG / n)
+			result.HitRate = roundMetric(result.HitRate / n)
+		}
+		if len(clubs) > 0 {
+			result.Coverage = roundMetric(float64(len(recommended[s])) / float64(len(clubs)))
+		}
+	}
+	return report, nil
+}
+
+// loadEvaluationCases 정답 가입이 있는 회원별 평가 입력 (반환: 평가 입력, 벡터가 없어 제외한 회원 수)
+func loadEvaluationCases(opts EvaluationOptions) ([]evaluationCase, int, error) {
+	var members []models.ClubMember
+	if err := database.DB.Select("user_id, club_id, joined_at, created_at").Order("user_id, id").Find(&members).Error; err != nil {
+		return nil, 0, err
+	}
+
+	known := map[uint]map[uint]bool{}
+	relevant := map[uint]map[uint]bool{}
+	firstJoin := map[uint]time.Time{}
+	for _, member := range members {
+		joinedAt := member.JoinedAt
+		if joinedAt.IsZero() {
+			joinedAt = member.CreatedAt
+		}
+
+		target := relevant
+		if !opts.Since.IsZero() && joinedAt.Before(opts.Since) {
+			target = known
+		} else if first, ok := firstJoin[member.UserID]; !ok || joinedAt.Before(first) {
+			firstJoin[member.UserID] = joinedAt
+		}
+		if target[member.UserID] == nil {
+			target[member.UserID] = map[uint]bool{}
+		}
+		target[member.UserID][member.ClubID] = true
+	}
+
+	userIDs := make([]uint, 0, len(relevant))
+	for userID := range relevant {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	if opts.MaxUsers > 0 && len(userIDs) > opts.MaxUsers {
+		userIDs = userIDs[:opts.MaxUsers]
+	}
+	if len(userIDs) == 0 {
+		return nil, 0, nil
+	}
+
+	// 가입 전에 응답한 세션 중 가장 최근 것
+	var sessionVectors []models.SessionVector
+	if err := database.DB.Where("user_id IN ?", userIDs).Order("created_at").Find(&sessionVectors).Error; err != nil {
+		return nil, 0, err
+	}
+	sessions := map[uint]*models.SessionVector{}
+	for i := range sessionVectors {
+		sv := &sessionVectors[i]
+		if sv.UserID == nil || sv.CreatedAt.After(firstJoin[*sv.UserID]) || utils.FromSlice(sv.Vector) == nil {
+			continue
+		}
+		sessions[*sv.UserID] = sv
+	}
+
+	var profiles []models.UserProfile
+	if err := database.DB.Where("user_id IN ?", userIDs).Find(&profiles).Error; err != nil {
+		return nil, 0, err
+	}
+	profileByUser := make(map[uint]*models.UserProfile, len(profiles))
+	for i := range profiles {
+		profileByUser[profiles[i].UserID] = &profiles[i]
+	}
+
+	cases := make([]evaluationCase, 0, len(userIDs))
+	skipped := 0
+	for _, userID := range userIDs {
+		c := evaluationCase{known: known[userID], relevant: relevant[userID], asOf: firstJoin[userID]}
+		switch {
+		case sessions[userID] != nil:
+			c.respondent = GuestRespondent(sessions[userID].SessionID)
+			c.vector = utils.FromSlice(sessions[userID].Vector)
+		case profileByUser[userID] != nil:
+			c.respondent = UserRespondent(userID)
+			c.vector = profileVector(profileByUser[userID])
+		default:
+			skipped++
+			continue
+		}
+		cases = append(cases, c)
+	}
+	return cases, skipped, nil
+}
+
+// rankingMetrics 이진 정답 기준 precision@K, recall@K, NDCG@K
+func rankingMetrics(ranked []uint, relevant map[uint]bool, k int) (precision, recall, ndcg float64) {
+	if len(relevant) == 0 || k <= 0 {
+		return 0, 0, 0
+	}
+
+	hits := 0
+	dcg := 0.0
+	for i, id := range ranked {
+		if i >= k {
+			break
+		}
+		if relevant[id] {
+			hits++
+			dcg += 1 / math.Log2(float64(i+2))
+		}
+	}
+
+	idcg := 0.0
+	for i := 0; i < len(relevant) && i < k; i++ {
+		idcg += 1 / math.Log2(float64(i+2))
+	}
+
+	precision = float64(hits) / float64(k)
+	recall = float64(hits) / float64(len(relevant))
+	ndcg = dcg / idcg
+	return precision, recall, ndcg
+}
+
+func roundMetric(x float64) float64 {
+	return math.Round(x*10000) / 10000
+}
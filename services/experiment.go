@@ -0,0 +1,474 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"ongi-back/database"
+	"ongi-back/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// experimentCacheTTL 실행 중인 실험을 다시 읽는 주기 (다른 인스턴스에서 시작/종료한 실험 반영)
+const experimentCacheTTL = 30 * time.Second
+
+// maxCachedExposures 인스턴스가 기억하는 배정 수 (넘으면 비우고 DB에서 다시 확인)
+const maxCachedExposures = 100000
+
+// ErrExperimentNotFound 실험 없음
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+// ErrExperimentRunning 이미 실행 중인 실험이 있음
+var ErrExperimentRunning = errors.New("another experiment is already running")
+
+// ExperimentVariant 실험 변형 (Weight 비율로 응답자 배정)
+type ExperimentVariant struct {
+	Name     string `json:"name"`
+	Strategy string `json:"strategy"`
+	Weight   int    `json:"weight"`
+}
+
+// ExperimentInput 실험 생성 입력 (첫 번째 변형이 대조군)
+type ExperimentInput struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Variants    []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentDetails 실험과 파싱한 변형 목록
+type ExperimentDetails struct {
+	models.Experiment
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentError 실험 입력 오류
+type ExperimentError struct {
+	Reason string
+}
+
+func (e *ExperimentError) Error() string {
+	return "invalid experiment: " + e.Reason
+}
+
+// runningExperiment 실행 중인 실험 (배정에 필요한 정보만)
+type runningExperiment struct {
+	id       uint
+	name     string
+	variants []ExperimentVariant
+}
+
+// experimentState 실행 중인 실험 캐시와 응답자별 배정 캐시
+var experimentState struct {
+	mu        sync.Mutex
+	loadedAt  time.Time
+	running   *runningExperiment
+	exposures map[string]string // "실험ID/subject" -> 변형
+}
+
+// InitExperiments RECOMMENDATION_STRATEGY 검증과 실행 중인 실험 로드
+func InitExperiments() error {
+	if _, err := DefaultClubStrategy(); err != nil {
+		return fmt.Errorf("RECOMMENDATION_STRATEGY: %w", err)
+	}
+	_, err := currentExperiment()
+	return err
+}
+
+// clubStrategyFor 응답자의 클럽 추천 전략 (실행 중인 실험이 있으면 배정된 변형의 전략, 노출 기록)
+// 실험을 읽거나 노출을 기록하지 못하면 기본 전략으로 추천한다.
+func clubStrategyFor(r Respondent) (ClubStrategy, error) {
+	exp, err := currentExperiment()
+	if err != nil {
+		log.Printf("experiment: load running experiment: %v", err)
+	}
+	if exp == nil {
+		return DefaultClubStrategy()
+	}
+
+	variant, err := exposeRespondent(exp, r)
+	if err != nil {
+		log.Printf("experiment %s: record exposure of %s: %v", exp.name, r, err)
+		return DefaultClubStrategy()
+	}
+	strategy, err := ResolveClubStrategy(variant.Strategy)
+	if err != nil {
+		log.Printf("experiment %s: variant %s: %v", exp.name, variant.Name, err)
+		return DefaultClubStrategy()
+	}
+	return strategy, nil
+}
+
+// currentExperiment 실행 중인 실험 (없으면 nil, experimentCacheTTL 동안 캐시)
+func currentExperiment() (*runningExperiment, error) {
+	experimentState.mu.Lock()
+	defer experimentState.mu.Unlock()
+	if !experimentState.loadedAt.IsZero() && time.Since(experimentState.loadedAt) < experimentCacheTTL {
+		return experimentState.running, nil
+	}
+
+	var experiment models.Experiment
+	err := database.DB.Where("status = ?", models.ExperimentRunning).First(&experiment).Error
+	var running *runningExperiment
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		variants, err := parseVariants(&experiment)
+		if err != nil {
+			return nil, err
+		}
+		running = &runningExperiment{id: experiment.ID, name: experiment.Name, variants: variants}
+	}
+
+	if experimentState.running == nil || running == nil || experimentState.running.id != running.id {
+		experimentState.exposures = map[string]string{}
+	}
+	experimentState.running = running
+	experimentState.loadedAt = time.Now()
+	return running, nil
+}
+
+// invalidateExperimentCache 실험 시작/종료 후 다음 요청에서 다시 읽도록
+func invalidateExperimentCache() {
+	experimentState.mu.Lock()
+	experimentState.loadedAt = time.Time{}
+	experimentState.mu.Unlock()
+}
+
+// exposeRespondent 응답자의 변형 배정과 첫 노출 기록
+// 이미 노출된 응답자(계정 연동 전 비회원 세션 포함)는 처음 배정된 변형을 유지한다.
+func exposeRespondent(exp *runningExperiment, r Respondent) (*ExperimentVariant, error) {
+	subject := r.String()
+	cacheKey := fmt.Sprintf("%d/%s", exp.id, subject)
+
+	experimentState.mu.Lock()
+	name, cached := experimentState.exposures[cacheKey]
+	experimentState.mu.Unlock()
+
+	if !cached {
+		userID := linkedUserID(r)
+		query := database.DB.Where("experiment_id = ?", exp.id)
+		if userID != 0 {
+			query = query.Where("subject = ? OR user_id = ?", subject, userID)
+		} else {
+			query = query.Where("subject = ?", subject)
+		}
+
+		var existing models.ExperimentExposure
+		err := query.Order("created_at").First(&existing).Error
+		switch {
+		case err == nil:
+			name = existing.Variant
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			name = AssignVariant(exp.name, exp.variants, subject).Name
+			exposure := models.ExperimentExposure{
+				ExperimentID: exp.id,
+				Subject:      subject,
+				Variant:      name,
+				CreatedAt:    time.Now(),
+			}
+			if userID != 0 {
+				exposure.UserID = &userID
+			}
+			if !r.IsUser() {
+				exposure.SessionID = r.SessionID
+			}
+			if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&exposure).Error; err != nil {
+				return nil, err
+			}
+		default:
+			return nil, err
+		}
+
+		experimentState.mu.Lock()
+		if len(experimentState.exposures) >= maxCachedExposures {
+			experimentState.exposures = map[string]string{}
+		}
+		experimentState.exposures[cacheKey] = name
+		experimentState.mu.Unlock()
+	}
+
+	for i := range exp.variants {
+		if exp.variants[i].Name == name {
+			return &exp.variants[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown variant %q", name)
+}
+
+// AssignVariant 실험 이름과 응답자 키의 해시로 변형 선택 (같은 입력이면 항상 같은 변형)
+func AssignVariant(experiment string, variants []ExperimentVariant, subject string) ExperimentVariant {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(experiment + "/" + subject))
+	bucket := int(h.Sum64() % uint64(total))
+	for _, variant := range variants {
+		if bucket < variant.Weight {
+			return variant
+		}
+		bucket -= variant.Weight
+	}
+	return variants[len(variants)-1]
+}
+
+// CreateExperiment 실험 생성과 시작 (실행 중인 실험이 있으면 ErrExperimentRunning)
+func CreateExperiment(input ExperimentInput) (*ExperimentDetails, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, &ExperimentError{Reason: "name is required"}
+	}
+	if len(input.Variants) < 2 {
+		return nil, &ExperimentError{Reason: "at least 2 variants are required"}
+	}
+	names := map[string]bool{}
+	for i, variant := range input.Variants {
+		if strings.TrimSpace(variant.Name) == "" {
+			return nil, &ExperimentError{Reason: fmt.Sprintf("variants[%d]: name is required", i)}
+		}
+		if names[variant.Name] {
+			return nil, &ExperimentError{Reason: fmt.Sprintf("variants[%d]: duplicate name %q", i, variant.Name)}
+		}
+		names[variant.Name] = true
+		if _, err := ResolveClubStrategy(variant.Strategy); err != nil {
+			return nil, &ExperimentError{Reason: fmt.Sprintf("variants[%d]: %v", i, err)}
+		}
+		if variant.Weight <= 0 {
+			return nil, &ExperimentError{Reason: fmt.Sprintf("variants[%d]: weight must be positive", i)}
+		}
+	}
+
+	variants, err := json.Marshal(input.Variants)
+	if err != nil {
+		return nil, err
+	}
+	experiment := models.Experiment{
+		Name:        input.Name,
+		Description: input.Description,
+		Variants:    string(variants),
+		Status:      models.ExperimentRunning,
+		StartedAt:   time.Now(),
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Experiment{}).Where("name = ?", experiment.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return &ExperimentError{Reason: fmt.Sprintf("experiment %q already exists", experiment.Name)}
+		}
+		if err := tx.Model(&models.Experiment{}).Where("status = ?", models.ExperimentRunning).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrExperimentRunning
+		}
+		return tx.Create(&experiment).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateExperimentCache()
+	return &ExperimentDetails{Experiment: experiment, Variants: input.Variants}, nil
+}
+
+// StopExperiment 실험 종료 (이후 추천은 기본 전략, 전환율은 종료 시각까지만 집계)
+func StopExperiment(id uint) (*ExperimentDetails, error) {
+	experiment, err := getExperiment(id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.Status == models.ExperimentRunning {
+		now := time.Now()
+		if err := database.DB.Model(experiment).Updates(map[string]interface{}{
+			"status":     models.ExperimentStopped,
+			"stopped_at": now,
+		}).Error; err != nil {
+			return nil, err
+		}
+		experiment.Status = models.ExperimentStopped
+		experiment.StoppedAt = &now
+		invalidateExperimentCache()
+	}
+	return experimentDetails(experiment)
+}
+
+// ListExperiments 전체 실험 (최근 순)
+func ListExperiments() ([]ExperimentDetails, error) {
+	var experiments []models.Experiment
+	if err := database.DB.Order("created_at DESC").Find(&experiments).Error; err != nil {
+		return nil, err
+	}
+
+	details := make([]ExperimentDetails, 0, len(experiments))
+	for i := range experiments {
+		d, err := experimentDetails(&experiments[i])
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, *d)
+	}
+	return details, nil
+}
+
+// VariantReport 변형별 가입 전환율
+type VariantReport struct {
+	Variant   string   `json:"variant"`
+	Strategy  string   `json:"strategy"`
+	Weight    int      `json:"weight"`
+	Exposures int64    `json:"exposures"`         // 노출된 응답자 수
+	Joined    int64    `json:"joined"`            // 노출 이후 클럽에 가입한 응답자 수
+	JoinRate  float64  `json:"join_rate"`         // joined / exposures (0-1)
+	Lift      *float64 `json:"lift,omitempty"`    // 대조군 대비 전환율 변화 비율 (대조군 전환율이 0이면 없음)
+	ZScore    *float64 `json:"z_score,omitempty"` // 대조군과의 전환율 차이 z 값 (|z| >= 1.96이면 95% 유의)
+}
+
+// ExperimentReport 실험 결과
+type ExperimentReport struct {
+	Experiment ExperimentDetails `json:"experiment"`
+	Control    string            `json:"control"` // 대조군 변형 (첫 번째 변형)
+	Variants   []VariantReport   `json:"variants"`
+	Until      time.Time         `json:"until"` // 가입 집계 종료 시각 (종료된 실험은 종료 시각)
+}
+
+// GetExperimentReport 변형별 노출 응답자 수와 노출 이후 가입 전환율
+// 회원은 클럽 가입 기록, 비회원은 가입 피드백 이벤트(계정 연동 후에는 회원 가입 기록 포함)로 전환을 판단한다.
+func GetExperimentReport(id uint) (*ExperimentReport, error) {
+	experiment, err := getExperiment(id)
+	if err != nil {
+		return nil, err
+	}
+	details, err := experimentDetails(experiment)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now()
+	if experiment.StoppedAt != nil {
+		until = *experiment.StoppedAt
+	}
+
+	var rows []struct {
+		Variant   string
+		Exposures int64
+		Joined    int64
+	}
+	err = database.DB.Raw(`
+		SELECT e.variant,
+		       COUNT(*) AS exposures,
+		       COUNT(*) FILTER (WHERE
+		           EXISTS (
+		               SELECT 1 FROM club_members m
+		               WHERE m.user_id = e.user_id
+		                 AND COALESCE(m.joined_at, m.created_at) BETWEEN e.created_at AND ?
+		           )
+		           OR EXISTS (
+		               SELECT 1 FROM recommendation_events r
+		               WHERE r.item_type = ? AND r.event = ?
+		                 AND r.created_at BETWEEN e.created_at AND ?
+		                 AND (r.user_id = e.user_id OR (e.session_id <> '' AND r.session_id = e.session_id))
+		           )
+		       ) AS joined
+		FROM experiment_exposures e
+		WHERE e.experiment_id = ?
+		GROUP BY e.variant`,
+		until, models.RecommendationItemClub, models.FeedbackJoin, until, id,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for i, row := range rows {
+		counts[row.Variant] = i
+	}
+
+	report := &ExperimentReport{Experiment: *details, Until: until, Variants: []VariantReport{}}
+	for _, variant := range details.Variants {
+		vr := VariantReport{Variant: variant.Name, Strategy: variant.Strategy, Weight: variant.Weight}
+		if i, ok := counts[variant.Name]; ok {
+			vr.Exposures, vr.Joined = rows[i].Exposures, rows[i].Joined
+		}
+		if vr.Exposures > 0 {
+			vr.JoinRate = float64(vr.Joined) / float64(vr.Exposures)
+		}
+		report.Variants = append(report.Variants, vr)
+	}
+	if len(report.Variants) > 0 {
+		report.Control = report.Variants[0].Variant
+		control := report.Variants[0]
+		for i := 1; i < len(report.Variants); i++ {
+			compareVariant(&report.Variants[i], control)
+		}
+	}
+	return report, nil
+}
+
+// compareVariant 대조군 대비 상승률과 두 비율 z 검정 값
+func compareVariant(vr *VariantReport, control VariantReport) {
+	if control.JoinRate > 0 {
+		lift := math.Round((vr.JoinRate-control.JoinRate)/control.JoinRate*1000) / 1000
+		vr.Lift = &lift
+	}
+	if vr.Exposures == 0 || control.Exposures == 0 {
+		return
+	}
+	pooled := float64(vr.Joined+control.Joined) / float64(vr.Exposures+control.Exposures)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(vr.Exposures) + 1/float64(control.Exposures)))
+	if se == 0 {
+		return
+	}
+	z := math.Round((vr.JoinRate-control.JoinRate)/se*100) / 100
+	vr.ZScore = &z
+}
+
+func getExperiment(id uint) (*models.Experiment, error) {
+	var experiment models.Experiment
+	err := database.DB.First(&experiment, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrExperimentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+func experimentDetails(experiment *models.Experiment) (*ExperimentDetails, error) {
+	variants, err := parseVariants(experiment)
+	if err != nil {
+		return nil, err
+	}
+	return &ExperimentDetails{Experiment: *experiment, Variants: variants}, nil
+}
+
+// parseVariants 저장된 변형 목록
+func parseVariants(experiment *models.Experiment) ([]ExperimentVariant, error) {
+	var variants []ExperimentVariant
+	if err := json.Unmarshal([]byte(experiment.Variants), &variants); err != nil {
+		return nil, fmt.Errorf("experiment %d variants: %w", experiment.ID, err)
+	}
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("experiment %d variants: total weight must be positive", experiment.ID)
+	}
+	return variants, nil
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"ongi-back/i18n"
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// DimensionMatch 차원별 일치도
+type DimensionMatch struct {
+	Dimension string  `json:"dimension"`
+	Label     string  `json:"label"`  // 요청 언어의 차원 이름
+	Score     float64 `json:"score"`  // 응답자 점수
+	Target    float64 `json:"target"` // 비교 대상 점수 (클럽 선호 성향 또는 상대 프로필)
+	Match     float64 `json:"match"`  // 100 - 점수 차이
+	Close     bool    `json:"close"`  // 허용 오차 안인지 (추천 이유에 사용)
+}
+
+// Explanation 추천 이유 (점수 구성과 사람이 읽을 수 있는 문장)
+type Explanation struct {
+	Dimensions     []DimensionMatch `json:"dimensions,omitempty"`      // 일치도 높은 순
+	SimilarMembers int64            `json:"similar_members,omitempty"` // 가입한 유사 회원 수
+	VibeMatch      *bool            `json:"vibe_match,omitempty"`      // 요청한 분위기와 일치 (필터 지정 시)
+	LocationMatch  *bool            `json:"location_match,omitempty"`  // 요청한 지역과 일치 (필터 지정 시)
+	MatchedTags    []string         `json:"matched_tags,omitempty"`    // 요청한 태그 중 포함된 태그
+	Collaborative  float64          `json:"collaborative,omitempty"`   // 유사 응답자 반응 (-1~1)
+	Feedback       float64          `json:"feedback,omitempty"`        // 본인의 분위기/카테고리 반응 (-1~1)
+	Reason         string           `json:"reason"`
+}
+
+// ExplainedClub 추천 이유가 포함된 클럽
+type ExplainedClub struct {
+	models.Club
+	Explanation Explanation `json:"explanation"`
+}
+
+// ExplainedMeeting 추천 이유가 포함된 모임
+type ExplainedMeeting struct {
+	models.Meeting
+	Explanation Explanation `json:"explanation"`
+}
+
+// ExplainClubRecommendations 성향 기반 클럽 추천 (추천 이유 포함)
+func ExplainClubRecommendations(r Respondent, limit int, filter ClubFilter, locale string) ([]ExplainedClub, error) {
+	v, err := RespondentVector(r)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := RecommendClubMatches(r, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	clubs := make([]ExplainedClub, len(matches))
+	for i := range matches {
+		explanation := explainClub(v, &matches[i].Club, 0, filter, locale)
+		explainFeedback(&explanation, matches[i].CollaborativeScore, matches[i].FeedbackScore, locale)
+		clubs[i] = ExplainedClub{Club: matches[i].Club, Explanation: explanation}
+	}
+	return clubs, nil
+}
+
+// ExplainSimilarMemberClubs 유사한 회원이 많은 클럽 추천 (추천 이유 포함, 유사 회원이 없으면 성향 기반)
+func ExplainSimilarMemberClubs(r Respondent, limit int, locale string) ([]ExplainedClub, error) {
+	clubs, counts, err := similarMemberClubs(r, limit)
+	if err != nil {
+		return nil, err
+	}
+	if clubs == nil {
+		return ExplainClubRecommendations(r, limit, ClubFilter{}, locale)
+	}
+
+	v, err := RespondentVector(r)
+	if err != nil {
+		return nil, err
+	}
+
+	explained := make([]ExplainedClub, len(clubs))
+	for i := range clubs {
+		explained[i] = ExplainedClub{Club: clubs[i], Explanation: explainClub(v, &clubs[i], counts[clubs[i].ID], ClubFilter{}, locale)}
+	}
+	return explained, nil
+}
+
+// ExplainMeetingRecommendations 성향 기반 모임 추천 (추천 이유 포함)
+func ExplainMeetingRecommendations(r Respondent, limit int, locale string) ([]ExplainedMeeting, error) {
+	meetings, rule, err := recommendMeetings(r, limit)
+	if err != nil {
+		return nil, err
+	}
+	v, err := RespondentVector(r)
+	if err != nil {
+		return nil, err
+	}
+
+	explained := make([]ExplainedMeeting, len(meetings))
+	for i := range meetings {
+		explanation := Explanation{}
+		reasons := []string{i18n.T(locale, "reason.meeting_"+rule)}
+
+		// 모임을 여는 클럽의 선호 성향
+		if target, err := ParseClubTarget(&meetings[i].Club); err == nil && target != nil {
+			explanation.Dimensions = explainTarget(v, target, locale)
+			if labels := closeDimensionLabels(explanation.Dimensions, locale); labels != "" {
+				reasons = append(reasons, i18n.T(locale, "reason.club_dimensions", labels))
+			}
+		}
+
+		explanation.Reason = strings.Join(reasons, " ")
+		explained[i] = ExplainedMeeting{Meeting: meetings[i], Explanation: explanation}
+	}
+	return explained, nil
+}
+
+// ExplainSimilarProfiles 유사 프로필마다 추천 이유 추가 (v: 검색한 응답자의 벡터)
+func ExplainSimilarProfiles(v *utils.Vector5D, profiles []SimilarProfile, locale string) {
+	for i := range profiles {
+		if profiles[i].Vector == nil {
+			continue
+		}
+		explanation := explainProfile(v, profiles[i].Vector, profiles[i].Similarity, locale)
+		profiles[i].Explanation = &explanation
+	}
+}
+
+// ExplainSimilarUsers 유사 회원마다 추천 이유 추가 (v: 검색한 회원의 벡터)
+func ExplainSimilarUsers(v *utils.Vector5D, users []UserSimilarity, locale string) {
+	for i := range users {
+		if users[i].vector == nil {
+			continue
+		}
+		explanation := explainProfile(v, users[i].vector, users[i].Similarity, locale)
+		users[i].Explanation = &explanation
+	}
+}
+
+// explainClub 클럽 추천 이유 (선호 성향 일치, 유사 회원 수, 요청한 분위기/지역/태그)
+func explainClub(v *utils.Vector5D, club *models.Club, similarMembers int64, filter ClubFilter, locale string) Explanation {
+	explanation := Explanation{SimilarMembers: similarMembers}
+	var reasons []string
+
+	if target, err := ParseClubTarget(club); err == nil && target != nil {
+		explanation.Dimensions = explainTarget(v, target, locale)
+		if labels := closeDimensionLabels(explanation.Dimensions, locale); labels != "" {
+			reasons = append(reasons, i18n.T(locale, "reason.club_dimensions", labels))
+		}
+	}
+	if similarMembers > 0 {
+		reasons = append(reasons, i18n.T(locale, "reason.similar_members", similarMembers))
+	}
+	if filter.Vibe != "" {
+		match := strings.EqualFold(club.Vibe, filter.Vibe)
+		explanation.VibeMatch = &match
+		if match {
+			reasons = append(reasons, i18n.T(locale, "reason.vibe", club.Vibe))
+		}
+	}
+	if filter.Location != "" {
+		match := strings.EqualFold(club.Location, filter.Location)
+		explanation.LocationMatch = &match
+		if match {
+			reasons = append(reasons, i18n.T(locale, "reason.location", club.Location))
+		}
+	}
+	if len(filter.Tags) > 0 {
+		tags := clubTags(club)
+		for _, tag := range filter.Tags {
+			if tags[strings.ToLower(tag)] {
+				explanation.MatchedTags = append(explanation.MatchedTags, tag)
+			}
+		}
+		if len(explanation.MatchedTags) == len(filter.Tags) {
+			reasons = append(reasons, i18n.T(locale, "reason.tags", strings.Join(explanation.MatchedTags, i18n.T(locale, "reason.separator"))))
+		}
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, i18n.T(locale, "reason.club_default"))
+	}
+	explanation.Reason = strings.Join(reasons, " ")
+	return explanation
+}
+
+// feedbackReasonThreshold 추천 이유에 넣을 최소 피드백 신호
+const feedbackReasonThreshold = 0.1
+
+// explainFeedback 피드백 신호가 충분히 높으면 추천 이유 앞에 추가 (기본 문구는 대체)
+func explainFeedback(explanation *Explanation, collaborative, feedback float64, locale string) {
+	explanation.Collaborative, explanation.Feedback = collaborative, feedback
+
+	var reasons []string
+	if collaborative >= feedbackReasonThreshold {
+		reasons = append(reasons, i18n.T(locale, "reason.similar_liked"))
+	}
+	if feedback >= feedbackReasonThreshold {
+		reasons = append(reasons, i18n.T(locale, "reason.feedback_liked"))
+	}
+	if len(reasons) == 0 {
+		return
+	}
+	if explanation.Reason != i18n.T(locale, "reason.club_default") {
+		reasons = append(reasons, explanation.Reason)
+	}
+	explanation.Reason = strings.Join(reasons, " ")
+}
+
+// explainProfile 유사 프로필 추천 이유 (가까운 차원, 없으면 전체 유사도)
+func explainProfile(v, other *utils.Vector5D, similarity float64, locale string) Explanation {
+	target := &ClubTarget{Scores: vectorByDimension(other)}
+
+	explanation := Explanation{Dimensions: explainTarget(v, target, locale)}
+	if labels := closeDimensionLabels(explanation.Dimensions, locale); labels != "" {
+		explanation.Reason = i18n.T(locale, "reason.profile_dimensions", labels)
+	} else {
+		explanation.Reason = i18n.T(locale, "reason.profile_overall", similarity)
+	}
+	return explanation
+}
+
+// explainTarget 목표 점수가 있는 차원별 일치도 (일치도 높은 순, 같으면 차원 순서)
+func explainTarget(v *utils.Vector5D, target *ClubTarget, locale string) []DimensionMatch {
+	scores := vectorByDimension(v)
+
+	var dimensions []DimensionMatch
+	for _, dimension := range models.Dimensions {
+		goal, ok := target.Scores[dimension]
+		if !ok {
+			continue
+		}
+		tolerance, ok := target.Tolerances[dimension]
+		if !ok {
+			tolerance = DefaultClubTolerance
+		}
+
+		diff := math.Abs(scores[dimension] - goal)
+		dimensions = append(dimensions, DimensionMatch{
+			Dimension: dimension,
+			Label:     i18n.T(locale, "dimension."+dimension),
+			Score:     scores[dimension],
+			Target:    goal,
+			Match:     math.Round((100-diff)*10) / 10,
+			Close:     diff <= tolerance,
+		})
+	}
+
+	sort.SliceStable(dimensions, func(i, j int) bool { return dimensions[i].Match > dimensions[j].Match })
+	return dimensions
+}
+
+// maxReasonDimensions 추천 이유 문장에 넣을 최대 차원 수
+const maxReasonDimensions = 2
+
+// closeDimensionLabels 허용 오차 안인 차원 중 일치도가 높은 차원 이름 (없으면 빈 문자열)
+func closeDimensionLabels(dimensions []DimensionMatch, locale string) string {
+	var labels []string
+	for _, d := range dimensions {
+		if d.Close && len(labels) < maxReasonDimensions {
+			labels = append(labels, d.Label)
+		}
+	}
+	return strings.Join(labels, i18n.T(locale, "reason.separator"))
+}
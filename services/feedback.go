@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"ongi-back/database"
+	"ongi-back/models"
+)
+
+// maxFeedbackBatch 한 번에 기록할 수 있는 최대 이벤트 수
+const maxFeedbackBatch = 100
+
+// feedbackWindow 추천에 반영하는 피드백 기간 (이보다 오래된 이벤트는 정리 작업에서 삭제)
+const feedbackWindow = 180 * 24 * time.Hour
+
+// FeedbackEvent 추천 피드백 입력
+type FeedbackEvent struct {
+	ItemType string `json:"item_type"` // club, meeting
+	ItemID   uint   `json:"item_id"`
+	Event    string `json:"event"`  // impression, click, dismiss, join
+	Source   string `json:"source"` // 추천 목록 (선택)
+}
+
+// FeedbackError 추천 피드백 입력 오류
+type FeedbackError struct {
+	Reason string
+}
+
+func (e *FeedbackError) Error() string {
+	return "invalid feedback: " + e.Reason
+}
+
+var feedbackEvents = map[string]bool{
+	models.FeedbackImpression: true,
+	models.FeedbackClick:      true,
+	models.FeedbackDismiss:    true,
+	models.FeedbackJoin:       true,
+}
+
+// RecordFeedback 응답자의 추천 피드백 기록 (존재하지 않는 클럽/모임이 있으면 전체 거부)
+// 계정에 연동된 비회원 세션의 이벤트는 연동된 회원의 이벤트로도 기록된다.
+func RecordFeedback(r Respondent, events []FeedbackEvent) (int, error) {
+	if len(events) == 0 {
+		return 0, &FeedbackError{Reason: "events are required"}
+	}
+	if len(events) > maxFeedbackBatch {
+		return 0, &FeedbackError{Reason: fmt.Sprintf("at most %d events per request", maxFeedbackBatch)}
+	}
+
+	ids := map[string]map[uint]bool{
+		models.RecommendationItemClub:    {},
+		models.RecommendationItemMeeting: {},
+	}
+	for i, event := range events {
+		if _, ok := ids[event.ItemType]; !ok {
+			return 0, &FeedbackError{Reason: fmt.Sprintf("events[%d]: unknown item_type %q", i, event.ItemType)}
+		}
+		if !feedbackEvents[event.Event] {
+			return 0, &FeedbackError{Reason: fmt.Sprintf("events[%d]: unknown event %q", i, event.Event)}
+		}
+		if event.ItemID == 0 {
+			return 0, &FeedbackError{Reason: fmt.Sprintf("events[%d]: item_id is required", i)}
+		}
+		ids[event.ItemType][event.ItemID] = true
+	}
+
+	// 클럽/모임 존재 확인
+	for itemType, set := range ids {
+		if len(set) == 0 {
+			continue
+		}
+		var model interface{} = &models.Club{}
+		if itemType == models.RecommendationItemMeeting {
+			model = &models.Meeting{}
+		}
+		var count int64
+		if err := database.DB.Model(model).Where("id IN ?", sortedIDs(set)).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		if count != int64(len(set)) {
+			return 0, &FeedbackError{Reason: "unknown " + itemType + " id"}
+		}
+	}
+
+	var userID *uint
+	sessionID := ""
+	if r.IsUser() {
+		id := r.UserID
+		userID = &id
+	} else {
+		sessionID = r.SessionID
+		if linked := linkedUserID(r); linked != 0 {
+			userID = &linked
+		}
+	}
+
+	now := time.Now()
+	rows := make([]models.RecommendationEvent, len(events))
+	for i, event := range events {
+		rows[i] = models.RecommendationEvent{
+			UserID:    userID,
+			SessionID: sessionID,
+			ItemType:  event.ItemType,
+			ItemID:    event.ItemID,
+			Event:     event.Event,
+			Source:    event.Source,
+			CreatedAt: now,
+		}
+	}
+	if err := database.DB.Create(&rows).Error; err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// CleanOldFeedback 추천에 반영하지 않는 오래된 피드백 삭제
+func CleanOldFeedback() error {
+	return database.DB.
+		Where("created_at < ?", time.Now().Add(-feedbackWindow)).
+		Delete(&models.RecommendationEvent{}).Error
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GoogleTokenInfo 구글 ID 토큰 검증 응답
+type GoogleTokenInfo struct {
+	Issuer        string `json:"iss"`
+	Audience      string `json:"aud"`
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified string `json:"email_verified"` // "true" / "false"
+	Name          string `json:"name"`
+}
+
+// ValidateGoogleIDToken 구글 ID 토큰 검증 (tokeninfo 엔드포인트 사용)
+func ValidateGoogleIDToken(idToken string) (*GoogleTokenInfo, error) {
+	clientIDs := os.Getenv("GOOGLE_CLIENT_ID")
+	if clientIDs == "" {
+		return nil, fmt.Errorf("GOOGLE_CLIENT_ID is not set")
+	}
+
+	resp, err := http.Get("https://oauth2.googleapis.com/tokeninfo?id_token=" + url.QueryEscape(idToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call google API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var info GoogleTokenInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse google response: %w", err)
+	}
+
+	if info.Issuer != "accounts.google.com" && info.Issuer != "https://accounts.google.com" {
+		return nil, fmt.Errorf("unexpected google token issuer: %s", info.Issuer)
+	}
+
+	// GOOGLE_CLIENT_ID는 쉼표로 여러 개(웹/iOS/Android) 지정 가능
+	for _, clientID := range strings.Split(clientIDs, ",") {
+		if strings.TrimSpace(clientID) == info.Audience {
+			return &info, nil
+		}
+	}
+
+	return nil, fmt.Errorf("google token audience mismatch")
+}
+
+// googleProvider 구글 로그인 제공자
+type googleProvider struct{}
+
+func (googleProvider) Name() string { return "google" }
+
+func (googleProvider) Authenticate(cred Credential) (*ExternalIdentity, error) {
+	if cred.IDToken == "" {
+		return nil, ErrInvalidCredential
+	}
+
+	info, err := ValidateGoogleIDToken(cred.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExternalIdentity{
+		Subject:       info.Subject,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified == "true",
+		Name:          info.Name,
+	}, nil
+}
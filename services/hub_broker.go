@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ongi-back/config"
+	"ongi-back/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Hub 브로커 종류 (HUB_BROKER)
+const (
+	HubBrokerPostgres = "postgres" // LISTEN/NOTIFY (기본)
+	HubBrokerRedis    = "redis"    // Redis PUBLISH/SUBSCRIBE (REDIS_URL)
+	HubBrokerMemory   = "memory"   // 프로세스 내부 (단일 인스턴스, 테스트)
+)
+
+// ErrUnknownHubBroker 지원하지 않는 Hub 브로커
+var ErrUnknownHubBroker = errors.New("unknown hub broker")
+
+// HubBroker 인스턴스 간 Hub 이벤트 전달
+// 발행한 이벤트는 발행한 인스턴스를 포함한 모든 구독자에게 전달된다 (Hub가 자기 이벤트를 거른다).
+type HubBroker interface {
+	Name() string
+	// Start 구독을 시작하고 받은 이벤트를 handler로 전달 (연결이 끊기면 ctx가 끝날 때까지 다시 연결)
+	Start(ctx context.Context, handler func(payload []byte)) error
+	Publish(payload []byte) error
+}
+
+// NewHubBroker 이름으로 Hub 브로커 생성
+func NewHubBroker(name string) (HubBroker, error) {
+	channel := config.AppConfig.HubChannel
+	switch name {
+	case HubBrokerPostgres:
+		return NewPostgresBroker(channel), nil
+	case HubBrokerRedis:
+		return NewRedisBroker(config.AppConfig.RedisURL, channel)
+	case HubBrokerMemory:
+		return NewMemoryBroker(), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownHubBroker, name)
+}
+
+// MemoryBroker 같은 프로세스의 Hub끼리 이벤트 전달
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers []func(payload []byte)
+}
+
+// NewMemoryBroker 프로세스 내부 브로커 생성 (여러 Hub가 공유하면 여러 인스턴스처럼 동작)
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+func (b *MemoryBroker) Name() string { return HubBrokerMemory }
+
+func (b *MemoryBroker) Start(ctx context.Context, handler func(payload []byte)) error {
+	b.mu.Lock()
+	b.handlers = append(b.handlers, handler)
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *MemoryBroker) Publish(payload []byte) error {
+	b.mu.RLock()
+	handlers := append([]func([]byte){}, b.handlers...)
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(append([]byte(nil), payload...))
+	}
+	return nil
+}
+
+// maxNotifyPayload NOTIFY 페이로드 최대 크기 (PostgreSQL 제한 8000바이트)
+// 더 큰 이벤트는 hub_events 테이블에 저장하고 ID만 보낸다.
+const maxNotifyPayload = 7900
+
+// hubEventRefPrefix hub_events에 저장한 이벤트의 NOTIFY 페이로드 접두사 (이벤트 JSON은 '{'로 시작)
+const hubEventRefPrefix = "@"
+
+// hubEventRetention hub_events 보관 기간 (다른 인스턴스가 읽기 전에 지워지지 않을 만큼)
+const hubEventRetention = 10 * time.Minute
+
+// PostgresBroker PostgreSQL LISTEN/NOTIFY 브로커 (별도 인프라 없이 같은 DB를 쓰는 인스턴스끼리 전달)
+type PostgresBroker struct {
+	channel string
+}
+
+// NewPostgresBroker channel로 LISTEN/NOTIFY하는 브로커 생성
+func NewPostgresBroker(channel string) *PostgresBroker {
+	return &PostgresBroker{channel: channel}
+}
+
+func (b *PostgresBroker) Name() string { return HubBrokerPostgres }
+
+// Start 연결 풀에서 전용 연결을 받아 LISTEN (첫 연결이 실패하면 오류)
+func (b *PostgresBroker) Start(ctx context.Context, handler func(payload []byte)) error {
+	conn, err := b.listen(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			err := b.receive(ctx, conn, handler)
+			conn.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Hub broker (postgres): connection lost: %v", err)
+			if conn = b.reconnect(ctx); conn == nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// reconnect 다시 LISTEN할 때까지 재시도 (ctx가 끝나면 nil)
+func (b *PostgresBroker) reconnect(ctx context.Context) *sql.Conn {
+	for attempt := 0; ; attempt++ {
+		if !waitReconnect(ctx, attempt) {
+			return nil
+		}
+		conn, err := b.listen(ctx)
+		if err == nil {
+			log.Printf("Hub broker (postgres): reconnected")
+			return conn
+		}
+		log.Printf("Hub broker (postgres): reconnect failed: %v", err)
+	}
+}
+
+func (b *PostgresBroker) listen(ctx context.Context) (*sql.Conn, error) {
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("listen %s: %w", b.channel, err)
+	}
+	return conn, nil
+}
+
+// receive 연결이 끊기거나 ctx가 끝날 때까지 알림 수신
+// 끝나면 LISTEN 상태의 연결이 풀로 돌아가지 않도록 driver.ErrBadConn으로 버린다.
+func (b *PostgresBroker) receive(ctx context.Context, conn *sql.Conn, handler func(payload []byte)) error {
+	var receiveErr error
+	err := conn.Raw(func(driverConn interface{}) error {
+		pgConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			receiveErr = fmt.Errorf("unexpected driver connection %T", driverConn)
+			return driver.ErrBadConn
+		}
+		for {
+			notification, err := pgConn.Conn().WaitForNotification(ctx)
+			if err != nil {
+				receiveErr = err
+				return driver.ErrBadConn
+			}
+			payload, err := resolveHubEvent(notification.Payload)
+			if err != nil {
+				log.Printf("Hub broker (postgres): %v", err)
+				continue
+			}
+			handler(payload)
+		}
+	})
+	if receiveErr == nil {
+		receiveErr = err
+	}
+	return receiveErr
+}
+
+// Publish NOTIFY (페이로드가 크면 hub_events에 저장하고 ID 전달)
+func (b *PostgresBroker) Publish(payload []byte) error {
+	notification := string(payload)
+	if len(payload) > maxNotifyPayload {
+		var id int64
+		if err := database.DB.Raw("INSERT INTO hub_events (payload) VALUES (?) RETURNING id", notification).Scan(&id).Error; err != nil {
+			return fmt.Errorf("store hub event: %w", err)
+		}
+		notification = hubEventRefPrefix + strconv.FormatInt(id, 10)
+	}
+	return database.DB.Exec("SELECT pg_notify(?, ?)", b.channel, notification).Error
+}
+
+// resolveHubEvent NOTIFY 페이로드를 이벤트 JSON으로 (hub_events 참조면 테이블에서 읽음)
+func resolveHubEvent(notification string) ([]byte, error) {
+	if !strings.HasPrefix(notification, hubEventRefPrefix) {
+		return []byte(notification), nil
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(notification, hubEventRefPrefix), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hub event reference %q", notification)
+	}
+	var payloads []string
+	if err := database.DB.Raw("SELECT payload FROM hub_events WHERE id = ?", id).Scan(&payloads).Error; err != nil {
+		return nil, fmt.Errorf("load hub event %d: %w", id, err)
+	}
+	if len(payloads) == 0 {
+		return nil, fmt.Errorf("hub event %d not found", id)
+	}
+	return []byte(payloads[0]), nil
+}
+
+// CleanHubEvents 보관 기간이 지난 hub_events 정리
+func CleanHubEvents() error {
+	return database.DB.Exec("DELETE FROM hub_events WHERE created_at < ?", time.Now().Add(-hubEventRetention)).Error
+}
+
+// waitReconnect 재연결 전 대기 (1초부터 두 배씩 최대 30초, ctx가 끝나면 false)
+func waitReconnect(ctx context.Context, attempt int) bool {
+	delay := time.Second << uint(attempt)
+	if attempt > 5 || delay > 30*time.Second {
+		delay = 30 * time.Second
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
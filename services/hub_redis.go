@@ -0,0 +1,286 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redisTimeout Redis 연결과 발행 명령 제한 시간
+const redisTimeout = 5 * time.Second
+
+// RedisBroker Redis PUBLISH/SUBSCRIBE 브로커
+// 명령 두 개만 쓰므로 클라이언트 라이브러리 없이 RESP 프로토콜로 직접 통신한다.
+type RedisBroker struct {
+	addr     string
+	username string
+	password string
+	useTLS   bool
+	channel  string
+
+	mu     sync.Mutex // 발행용 연결 보호
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisBroker REDIS_URL(redis://[user:password@]host:port, TLS는 rediss://)로 브로커 생성
+func NewRedisBroker(rawURL, channel string) (*RedisBroker, error) {
+	if rawURL == "" {
+		return nil, errors.New("REDIS_URL is required for the redis hub broker")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("REDIS_URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("REDIS_URL: unsupported scheme %q", u.Scheme)
+	}
+
+	b := &RedisBroker{addr: u.Host, useTLS: u.Scheme == "rediss", channel: channel}
+	if u.Port() == "" {
+		b.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		b.username = u.User.Username()
+		b.password, _ = u.User.Password()
+	}
+	return b, nil
+}
+
+func (b *RedisBroker) Name() string { return HubBrokerRedis }
+
+// Start 구독 연결을 열고 SUBSCRIBE (첫 연결이 실패하면 오류)
+func (b *RedisBroker) Start(ctx context.Context, handler func(payload []byte)) error {
+	conn, reader, err := b.subscribe(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			err := b.receive(ctx, conn, reader, handler)
+			conn.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Hub broker (redis): connection lost: %v", err)
+			if conn, reader = b.resubscribe(ctx); conn == nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// resubscribe 다시 SUBSCRIBE할 때까지 재시도 (ctx가 끝나면 nil)
+func (b *RedisBroker) resubscribe(ctx context.Context) (net.Conn, *bufio.Reader) {
+	for attempt := 0; ; attempt++ {
+		if !waitReconnect(ctx, attempt) {
+			return nil, nil
+		}
+		conn, reader, err := b.subscribe(ctx)
+		if err == nil {
+			log.Printf("Hub broker (redis): reconnected")
+			return conn, reader
+		}
+		log.Printf("Hub broker (redis): reconnect failed: %v", err)
+	}
+}
+
+func (b *RedisBroker) subscribe(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	conn, reader, err := b.dial(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(redisTimeout))
+	if err := writeRedisCommand(conn, "SUBSCRIBE", b.channel); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	reply, err := readRedisReply(reader)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("subscribe %s: %w", b.channel, err)
+	}
+	if fields, ok := reply.([]interface{}); !ok || len(fields) == 0 || fields[0] != "subscribe" {
+		conn.Close()
+		return nil, nil, fmt.Errorf("subscribe %s: unexpected reply %v", b.channel, reply)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, reader, nil
+}
+
+// receive 연결이 끊기거나 ctx가 끝날 때까지 메시지 수신
+func (b *RedisBroker) receive(ctx context.Context, conn net.Conn, reader *bufio.Reader, handler func(payload []byte)) error {
+	// ctx가 끝나면 연결을 닫아 읽기를 깨움
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return err
+		}
+		fields, ok := reply.([]interface{})
+		if !ok || len(fields) != 3 || fields[0] != "message" {
+			continue
+		}
+		if payload, ok := fields[2].(string); ok {
+			handler([]byte(payload))
+		}
+	}
+}
+
+// Publish PUBLISH (연결이 끊겼으면 한 번 다시 연결해 재시도)
+func (b *RedisBroker) Publish(payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if b.conn == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+			b.conn, b.reader, err = b.dial(ctx)
+			cancel()
+			if err != nil {
+				b.conn = nil
+				return err
+			}
+		}
+
+		b.conn.SetDeadline(time.Now().Add(redisTimeout))
+		if err = writeRedisCommand(b.conn, "PUBLISH", b.channel, string(payload)); err == nil {
+			if _, err = readRedisReply(b.reader); err == nil {
+				return nil
+			}
+		}
+		var replyErr redisError
+		if errors.As(err, &replyErr) {
+			return err
+		}
+		b.conn.Close()
+		b.conn, b.reader = nil, nil
+	}
+	return err
+}
+
+// dial 연결 후 비밀번호가 있으면 AUTH
+func (b *RedisBroker) dial(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	dialer := &net.Dialer{Timeout: redisTimeout, KeepAlive: 30 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", b.addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b.useTLS {
+		host, _, _ := net.SplitHostPort(b.addr)
+		conn = tls.Client(conn, &tls.Config{ServerName: host})
+	}
+	reader := bufio.NewReader(conn)
+
+	if b.password != "" {
+		args := []string{"AUTH", b.password}
+		if b.username != "" {
+			args = []string{"AUTH", b.username, b.password}
+		}
+		conn.SetDeadline(time.Now().Add(redisTimeout))
+		err := writeRedisCommand(conn, args...)
+		if err == nil {
+			_, err = readRedisReply(reader)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("redis auth: %w", err)
+		}
+		conn.SetDeadline(time.Time{})
+	}
+	return conn, reader, nil
+}
+
+// redisError Redis 오류 응답 (-ERR ...)
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// writeRedisCommand RESP 배열로 명령 전송
+func writeRedisCommand(w io.Writer, args ...string) error {
+	var sb strings.Builder
+	sb.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		sb.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n")
+		sb.WriteString(arg)
+		sb.WriteString("\r\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// readRedisReply RESP 응답 하나 읽기 (문자열, 정수, 배열, nil, 오류 응답은 redisError)
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := redisLength(line)
+		if err != nil || n < 0 {
+			return nil, err // $-1은 nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := redisLength(line)
+		if err != nil || n < 0 {
+			return nil, err // *-1은 nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRedisReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
+
+// redisLength 문자열/배열 길이 ($n, *n). nil을 뜻하는 -1 외의 음수는 오류
+func redisLength(line string) (int, error) {
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < -1 {
+		return 0, fmt.Errorf("invalid redis length %q", line)
+	}
+	return n, nil
+}
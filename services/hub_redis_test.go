@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteRedisCommand(t *testing.T) {
+	var sb strings.Builder
+	if err := writeRedisCommand(&sb, "PUBLISH", "ongi_hub", "{\"a\":\"b\r\n\"}"); err != nil {
+		t.Fatal(err)
+	}
+	want := "*3\r\n$7\r\nPUBLISH\r\n$8\r\nongi_hub\r\n$11\r\n{\"a\":\"b\r\n\"}\r\n"
+	if sb.String() != want {
+		t.Errorf("writeRedisCommand = %q, want %q", sb.String(), want)
+	}
+}
+
+func TestReadRedisReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  interface{}
+	}{
+		{"simple string", "+OK\r\n", "OK"},
+		{"integer", ":42\r\n", int64(42)},
+		{"bulk string", "$5\r\nhello\r\n", "hello"},
+		{"bulk with CRLF", "$4\r\na\r\nb\r\n", "a\r\nb"},
+		{"empty bulk", "$0\r\n\r\n", ""},
+		{"nil bulk", "$-1\r\n", nil},
+		{"nil array", "*-1\r\n", nil},
+		{"empty array", "*0\r\n", []interface{}{}},
+		{
+			"subscribe message",
+			"*3\r\n$7\r\nmessage\r\n$8\r\nongi_hub\r\n$2\r\n{}\r\n",
+			[]interface{}{"message", "ongi_hub", "{}"},
+		},
+		{
+			"nested array with nil",
+			"*2\r\n*2\r\n:1\r\n$-1\r\n+x\r\n",
+			[]interface{}{[]interface{}{int64(1), nil}, "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readRedisReply(bufio.NewReader(strings.NewReader(tt.input)))
+			if err != nil {
+				t.Fatalf("readRedisReply(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readRedisReply(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRedisReplyErrors(t *testing.T) {
+	reply, err := readRedisReply(bufio.NewReader(strings.NewReader("-ERR unknown command\r\n")))
+	var replyErr redisError
+	if !errors.As(err, &replyErr) || string(replyErr) != "ERR unknown command" {
+		t.Errorf("error reply: got %v, %v", reply, err)
+	}
+
+	// 배열 안의 오류 응답도 오류로 전달
+	if _, err := readRedisReply(bufio.NewReader(strings.NewReader("*2\r\n+OK\r\n-WRONGTYPE bad\r\n"))); !errors.As(err, &replyErr) {
+		t.Errorf("nested error reply: got %v", err)
+	}
+
+	invalid := []string{
+		"",
+		"\r\n",
+		"?what\r\n",
+		"$-2\r\n",
+		"*-5\r\n",
+		"$x\r\n",
+		":1.5\r\n",
+		"$5\r\nhi\r\n",  // 길이보다 짧음
+		"*2\r\n+OK\r\n", // 원소 부족
+	}
+	for _, input := range invalid {
+		if reply, err := readRedisReply(bufio.NewReader(strings.NewReader(input))); err == nil {
+			t.Errorf("readRedisReply(%q) = %#v, expected error", input, reply)
+		}
+	}
+}
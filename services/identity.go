@@ -0,0 +1,334 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ongi-back/database"
+	"ongi-back/models"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownProvider       = errors.New("unknown identity provider")
+	ErrInvalidCredential     = errors.New("invalid credential")
+	ErrIdentityLinkedToOther = errors.New("identity is already linked to another user")
+	ErrLastIdentity          = errors.New("cannot unlink the last login method")
+)
+
+// AccountExistsError 같은 이메일의 계정이 다른 로그인 수단으로 이미 존재하는 경우
+// 자동으로 병합하지 않고, 기존 수단으로 로그인한 뒤 연결하도록 안내한다.
+type AccountExistsError struct {
+	Email     string
+	Providers []string
+}
+
+func (e *AccountExistsError) Error() string {
+	return fmt.Sprintf("an account with email %s already exists (login with: %s)", e.Email, strings.Join(e.Providers, ", "))
+}
+
+// Credential 클라이언트가 전달하는 로그인 자격 증명 (제공자별로 필요한 필드만 사용)
+type Credential struct {
+	AccessToken string `json:"access_token"` // kakao, naver
+	IDToken     string `json:"id_token"`     // google, apple
+	Code        string `json:"code"`         // kakao 서버사이드 OAuth
+	Email       string `json:"email"`        // email
+	Password    string `json:"password"`     // email
+	Name        string `json:"name"`         // apple은 최초 로그인 시에만 이름을 전달
+}
+
+// ExternalIdentity 제공자가 확인해 준 사용자 정보
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// IdentityProvider 로그인 제공자 인터페이스
+type IdentityProvider interface {
+	// Name 제공자 이름 (kakao, naver, google, apple, email)
+	Name() string
+	// Authenticate 자격 증명을 검증하고 제공자 내 사용자 정보 반환
+	Authenticate(cred Credential) (*ExternalIdentity, error)
+}
+
+// legacyUserFinder 식별자 테이블 도입 이전에 생성된 계정을 찾을 수 있는 제공자
+type legacyUserFinder interface {
+	findLegacyUser(tx *gorm.DB, ext *ExternalIdentity) (*models.User, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]IdentityProvider{}
+)
+
+// RegisterIdentityProvider 로그인 제공자 등록
+func RegisterIdentityProvider(provider IdentityProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[provider.Name()] = provider
+}
+
+// GetIdentityProvider 이름으로 로그인 제공자 조회
+func GetIdentityProvider(name string) (IdentityProvider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	provider, ok := providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// IdentityProviderNames 등록된 제공자 이름 목록
+func IdentityProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterIdentityProvider(kakaoProvider{})
+	RegisterIdentityProvider(naverProvider{})
+	RegisterIdentityProvider(googleProvider{})
+	RegisterIdentityProvider(newAppleProvider())
+	RegisterIdentityProvider(emailProvider{})
+}
+
+// AuthenticateWithProvider 제공자로 자격 증명 검증
+func AuthenticateWithProvider(providerName string, cred Credential) (*ExternalIdentity, error) {
+	provider, err := GetIdentityProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, err := provider.Authenticate(cred)
+	if err != nil {
+		return nil, err
+	}
+	if ext.Subject == "" {
+		return nil, ErrInvalidCredential
+	}
+
+	ext.Provider = provider.Name()
+	ext.Email = normalizeEmail(ext.Email)
+	return ext, nil
+}
+
+// LoginWithIdentity 외부 계정으로 로그인/회원가입 처리
+// provider + subject로만 사용자를 식별하며, 같은 이메일의 다른 계정과 자동 병합하지 않는다.
+func LoginWithIdentity(ext *ExternalIdentity) (models.User, bool, error) {
+	var user models.User
+	isNewUser := false
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 1. 이미 연결된 식별자
+		var identity models.UserIdentity
+		err := tx.Where("provider = ? AND subject = ?", ext.Provider, ext.Subject).First(&identity).Error
+		if err == nil {
+			if err := tx.First(&user, identity.UserID).Error; err != nil {
+				return err
+			}
+			return touchIdentity(tx, &identity, ext)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// 2. 식별자 도입 이전 계정 (제공자별 규칙)
+		if finder, ok := mustProvider(ext.Provider).(legacyUserFinder); ok {
+			legacyUser, err := finder.findLegacyUser(tx, ext)
+			if err != nil {
+				return err
+			}
+			if legacyUser != nil {
+				user = *legacyUser
+				return createIdentity(tx, user.ID, ext, "")
+			}
+		}
+
+		// 3. 같은 이메일의 계정이 다른 수단으로 존재하면 병합하지 않고 안내
+		if err := checkEmailConflict(tx, ext.Email); err != nil {
+			return err
+		}
+
+		// 4. 신규 회원가입
+		user, err = createUserWithIdentity(tx, ext, "")
+		if err != nil {
+			return err
+		}
+		isNewUser = true
+		return nil
+	})
+
+	return user, isNewUser, err
+}
+
+// LinkIdentity 로그인된 사용자에게 새 로그인 수단 연결
+func LinkIdentity(userID uint, ext *ExternalIdentity) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("provider = ? AND subject = ?", ext.Provider, ext.Subject).First(&identity).Error
+		if err == nil {
+			if identity.UserID != userID {
+				return ErrIdentityLinkedToOther
+			}
+			return nil // 이미 연결됨
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		identity = newIdentity(userID, ext, "")
+		return tx.Create(&identity).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// UnlinkIdentity 로그인 수단 연결 해제 (마지막 수단은 해제 불가)
+func UnlinkIdentity(userID uint, provider string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		var identity models.UserIdentity
+		if err := tx.Where("user_id = ? AND provider = ?", userID, provider).First(&identity).Error; err != nil {
+			return err
+		}
+
+		if count <= 1 {
+			return ErrLastIdentity
+		}
+
+		return tx.Delete(&identity).Error
+	})
+}
+
+// GetUserIdentities 사용자에게 연결된 로그인 수단 목록
+func GetUserIdentities(userID uint) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	err := database.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
+
+// checkEmailConflict 같은 이메일을 인증한 로그인 수단이 있으면 AccountExistsError
+func checkEmailConflict(tx *gorm.DB, email string) error {
+	if email == "" {
+		return nil
+	}
+
+	var existing []models.UserIdentity
+	if err := tx.Where("email = ?", email).Find(&existing).Error; err != nil {
+		return err
+	}
+	return emailConflict(email, existing)
+}
+
+// emailConflict 이메일이 같은 식별자 중 제공자가 인증한 것만 충돌로 본다
+// 미인증 이메일(이메일/비밀번호 가입 등)은 주소를 점유하지 못하므로, 실제 소유자의 가입을 막지 않는다.
+// users.email은 인증 여부를 알 수 없고 회원가입 시 식별자와 같은 값으로 저장되므로 보지 않는다.
+func emailConflict(email string, identities []models.UserIdentity) error {
+	seen := map[string]bool{}
+	providerNames := []string{}
+	for _, identity := range identities {
+		if !identity.EmailVerified || seen[identity.Provider] {
+			continue
+		}
+		seen[identity.Provider] = true
+		providerNames = append(providerNames, identity.Provider)
+	}
+	if len(providerNames) == 0 {
+		return nil
+	}
+	sort.Strings(providerNames)
+
+	return &AccountExistsError{Email: email, Providers: providerNames}
+}
+
+func createUserWithIdentity(tx *gorm.DB, ext *ExternalIdentity, passwordHash string) (models.User, error) {
+	name := ext.Name
+	if name == "" {
+		name = fmt.Sprintf("User_%s", shortSubject(ext.Subject))
+	}
+
+	user := models.User{
+		Email: ext.Email,
+		Name:  name,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return user, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := createIdentity(tx, user.ID, ext, passwordHash); err != nil {
+		return user, err
+	}
+	return user, nil
+}
+
+func createIdentity(tx *gorm.DB, userID uint, ext *ExternalIdentity, passwordHash string) error {
+	identity := newIdentity(userID, ext, passwordHash)
+	if err := tx.Create(&identity).Error; err != nil {
+		return fmt.Errorf("failed to create identity: %w", err)
+	}
+	return nil
+}
+
+func newIdentity(userID uint, ext *ExternalIdentity, passwordHash string) models.UserIdentity {
+	now := time.Now()
+	return models.UserIdentity{
+		UserID:        userID,
+		Provider:      ext.Provider,
+		Subject:       ext.Subject,
+		Email:         ext.Email,
+		EmailVerified: ext.EmailVerified,
+		PasswordHash:  passwordHash,
+		LastLoginAt:   &now,
+	}
+}
+
+func touchIdentity(tx *gorm.DB, identity *models.UserIdentity, ext *ExternalIdentity) error {
+	updates := map[string]interface{}{
+		"last_login_at": time.Now(),
+	}
+	if ext.Email != "" && ext.Email != identity.Email {
+		updates["email"] = ext.Email
+		updates["email_verified"] = ext.EmailVerified
+	}
+	return tx.Model(identity).Updates(updates).Error
+}
+
+func mustProvider(name string) IdentityProvider {
+	provider, _ := GetIdentityProvider(name)
+	return provider
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func shortSubject(subject string) string {
+	if len(subject) > 10 {
+		return subject[:10]
+	}
+	return subject
+}
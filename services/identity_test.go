@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"ongi-back/models"
+)
+
+func TestEmailConflict(t *testing.T) {
+	const email = "owner@example.com"
+
+	// 이메일 인증 없이 가입한 비밀번호 계정
+	passwordExt, passwordHash, err := newEmailIdentity(email, "password123", "squatter")
+	if err != nil {
+		t.Fatalf("newEmailIdentity: %v", err)
+	}
+	password := newIdentity(1, passwordExt, passwordHash)
+	if password.EmailVerified {
+		t.Fatal("email/password identity must be stored as unverified")
+	}
+
+	google := newIdentity(2, &ExternalIdentity{Provider: "google", Subject: "g-1", Email: email, EmailVerified: true}, "")
+	kakao := newIdentity(3, &ExternalIdentity{Provider: "kakao", Subject: "1", Email: email, EmailVerified: true}, "")
+	unverifiedKakao := newIdentity(4, &ExternalIdentity{Provider: "kakao", Subject: "2", Email: email}, "")
+
+	tests := []struct {
+		name          string
+		existing      []models.UserIdentity
+		wantProviders []string // nil이면 충돌 없음
+	}{
+		{"no accounts", nil, nil},
+		{"unverified password signup, then verified google signup", []models.UserIdentity{password}, nil},
+		{"only unverified identities", []models.UserIdentity{password, unverifiedKakao}, nil},
+		{"verified google, then password signup", []models.UserIdentity{google}, []string{"google"}},
+		{"verified providers are listed once and sorted", []models.UserIdentity{kakao, password, google, kakao}, []string{"google", "kakao"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := emailConflict(email, tt.existing)
+			if tt.wantProviders == nil {
+				if err != nil {
+					t.Fatalf("emailConflict = %v, want nil", err)
+				}
+				return
+			}
+
+			var exists *AccountExistsError
+			if !errors.As(err, &exists) {
+				t.Fatalf("emailConflict = %v, want AccountExistsError", err)
+			}
+			if !reflect.DeepEqual(exists.Providers, tt.wantProviders) {
+				t.Errorf("Providers = %v, want %v", exists.Providers, tt.wantProviders)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"ongi-back/config"
+)
+
+// 기본 백그라운드 작업 이름
+const (
+	JobSessionCleanup = "session_cleanup" // 만료된 비회원 세션, 토큰, 오래된 추천 피드백, 전달이 끝난 Hub 이벤트 정리
+	JobRematch        = "rematch"         // 전체 사용자 군집화 후 클럽 재매칭 (입력: MatchOptions)
+	JobReindex        = "reindex"         // 벡터 인덱스 재구성 (인스턴스마다)
+)
+
+// scheduleOff 스케줄 설정값이 off면 수동 실행만
+const scheduleOff = "off"
+
+// InitJobs 기본 작업 등록 후 JOBS_ENABLED이면 스케줄러 시작
+// 작업 등록은 항상 하므로 스케줄러를 끈 인스턴스도 작업을 요청할 수 있다 (실행은 잠금을 가진 인스턴스).
+func InitJobs(ctx context.Context) error {
+	definitions := []JobDefinition{
+		{
+			Name:        JobSessionCleanup,
+			Description: "Delete expired guest sessions, expired tokens, old recommendation feedback and delivered hub events",
+			Schedule:    config.AppConfig.JobCleanupSchedule,
+			Run:         runSessionCleanup,
+		},
+		{
+			Name:        JobRematch,
+			Description: "Cluster all users and assign groups to clubs",
+			Schedule:    config.AppConfig.JobRematchSchedule,
+			Run:         runRematch,
+		},
+		{
+			Name:        JobReindex,
+			Description: "Rebuild this instance's similar-profile vector index",
+			Schedule:    config.AppConfig.JobReindexSchedule,
+			Local:       true,
+			Run:         runReindex,
+		},
+	}
+
+	for _, def := range definitions {
+		if def.Schedule == scheduleOff {
+			def.Schedule = ""
+		}
+		if err := GlobalJobRunner.Register(def); err != nil {
+			return err
+		}
+	}
+
+	if config.AppConfig.JobsEnabled {
+		GlobalJobRunner.Start(ctx)
+	}
+	return nil
+}
+
+func runSessionCleanup(ctx context.Context, run *JobRun) (interface{}, error) {
+	run.Progress(0, "sessions")
+	if err := CleanExpiredSessions(); err != nil {
+		return nil, fmt.Errorf("clean sessions: %w", err)
+	}
+	run.Progress(40, "tokens")
+	if err := CleanExpiredTokens(); err != nil {
+		return nil, fmt.Errorf("clean tokens: %w", err)
+	}
+	run.Progress(70, "feedback")
+	if err := CleanOldFeedback(); err != nil {
+		return nil, fmt.Errorf("clean feedback: %w", err)
+	}
+	run.Progress(90, "hub events")
+	if err := CleanHubEvents(); err != nil {
+		return nil, fmt.Errorf("clean hub events: %w", err)
+	}
+	return nil, nil
+}
+
+func runRematch(ctx context.Context, run *JobRun) (interface{}, error) {
+	var opts MatchOptions
+	if err := run.Params(&opts); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobParams, err)
+	}
+	if opts.K < 0 || opts.MaxGroupSize < 0 {
+		return nil, fmt.Errorf("%w: k and max_group_size must not be negative", ErrInvalidJobParams)
+	}
+
+	run.Progress(0, "clustering")
+	return MatchUsersToClubs(opts)
+}
+
+func runReindex(ctx context.Context, run *JobRun) (interface{}, error) {
+	if err := RebuildVectorIndex(); err != nil {
+		return nil, err
+	}
+	return map[string]int{"respondents": GlobalVectorIndex.Len()}, nil
+}
@@ -0,0 +1,609 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrUnknownJob  = errors.New("unknown job")
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrInvalidJobParams 입력이 잘못되어 다시 시도해도 실패하는 작업
+	ErrInvalidJobParams = errors.New("invalid job params")
+)
+
+// 여러 인스턴스 중 하나만 공유 작업을 실행하도록 사용하는 advisory lock 키 (세션 단위로 유지)
+const jobLockKey int64 = 7262810515
+
+const (
+	jobPollInterval       = 5 * time.Second  // 대기 중인 작업 확인 간격 (실행 중인 공유 작업의 heartbeat 간격)
+	jobLease              = 30 * time.Second // 실행 중인 공유 작업의 heartbeat가 이보다 오래 없으면 중단된 것으로 보고 다시 실행
+	jobDefaultMaxAttempts = 3
+	jobDefaultRetryDelay  = time.Minute // 첫 재시도 대기 (이후 두 배씩)
+	jobListLimit          = 50
+)
+
+// JobFunc 작업 본문 (반환값은 JSON으로 Job.Result에 저장)
+type JobFunc func(ctx context.Context, run *JobRun) (interface{}, error)
+
+// JobDefinition 등록된 백그라운드 작업
+type JobDefinition struct {
+	Name        string
+	Description string
+	Schedule    string        // cron 표현식 (비어 있으면 수동 실행만)
+	Local       bool          // 인스턴스마다 실행 (프로세스 메모리를 다루는 작업, 잠금 불필요)
+	MaxAttempts int           // 최대 시도 횟수 (0이면 jobDefaultMaxAttempts)
+	RetryDelay  time.Duration // 첫 재시도 대기 (0이면 jobDefaultRetryDelay)
+	Timeout     time.Duration // 한 번 실행의 제한 시간 (0이면 제한 없음)
+	Run         JobFunc
+
+	schedule utils.Schedule
+}
+
+// JobInfo 작업 정의와 이 인스턴스 기준 다음 실행 시각
+type JobInfo struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Schedule    string      `json:"schedule"`
+	Local       bool        `json:"local"`
+	MaxAttempts int         `json:"max_attempts"`
+	NextRun     *time.Time  `json:"next_run,omitempty"` // 이 인스턴스가 스케줄을 맡고 있을 때만
+	LastRun     *models.Job `json:"last_run,omitempty"`
+}
+
+// JobRunnerStatus 작업 실행기 상태
+type JobRunnerStatus struct {
+	Instance string    `json:"instance"`
+	Running  bool      `json:"running"`
+	Leader   bool      `json:"leader"` // 공유 작업 실행 잠금을 가지고 있는지
+	Jobs     []JobInfo `json:"jobs"`
+}
+
+// JobRun 실행 중인 작업 (진행률 기록, 입력 읽기)
+type JobRun struct {
+	Job *models.Job
+}
+
+// Progress 진행률(0-100)과 현재 단계 기록
+func (r *JobRun) Progress(percent int, message string) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	r.Job.Progress, r.Job.ProgressMessage = percent, message
+	if err := database.DB.Model(&models.Job{}).Where("id = ? AND attempt = ?", r.Job.ID, r.Job.Attempt).
+		Updates(map[string]interface{}{"progress": percent, "progress_message": message, "updated_at": time.Now()}).Error; err != nil {
+		log.Printf("Job %d: failed to record progress: %v", r.Job.ID, err)
+	}
+}
+
+// Params 작업 입력 JSON을 v로 읽기 (입력이 없으면 그대로 둠)
+func (r *JobRun) Params(v interface{}) error {
+	if r.Job.Params == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(r.Job.Params), v)
+}
+
+// JobRunner 프로세스 내 작업 스케줄러
+// 스케줄이 된 작업을 jobs 테이블에 넣고 차례로 실행한다. 공유 작업은 Postgres advisory lock을 가진
+// 인스턴스 하나만 실행하고, 실패하면 대기 시간을 두 배씩 늘리며 MaxAttempts까지 다시 시도한다.
+type JobRunner struct {
+	mu          sync.Mutex
+	definitions map[string]*JobDefinition
+	next        map[string]time.Time // 작업별 다음 스케줄 시각 (이 인스턴스가 맡은 작업만)
+	instance    string
+	running     bool
+	leader      bool
+	leaderConn  *sql.Conn // advisory lock을 잡은 연결 (연결이 끊기면 잠금도 풀림)
+	wake        chan struct{}
+}
+
+// NewJobRunner 작업 실행기 생성 (instance: 이 프로세스 식별자)
+func NewJobRunner(instance string) *JobRunner {
+	return &JobRunner{
+		definitions: make(map[string]*JobDefinition),
+		next:        make(map[string]time.Time),
+		instance:    instance,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// GlobalJobRunner 서버 전역 작업 실행기
+var GlobalJobRunner = NewJobRunner(jobInstanceName())
+
+func jobInstanceName() string {
+	if name, err := os.Hostname(); err == nil && name != "" {
+		return name
+	}
+	return "local"
+}
+
+// Register 작업 등록 (스케줄 표현식 검증)
+func (jr *JobRunner) Register(def JobDefinition) error {
+	if def.Name == "" || def.Run == nil {
+		return errors.New("job name and function are required")
+	}
+	if def.Schedule != "" {
+		schedule, err := utils.ParseSchedule(def.Schedule)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", def.Name, err)
+		}
+		if schedule.Next(time.Now()).IsZero() {
+			return fmt.Errorf("job %s: schedule %q never runs", def.Name, def.Schedule)
+		}
+		def.schedule = schedule
+	}
+	if def.MaxAttempts <= 0 {
+		def.MaxAttempts = jobDefaultMaxAttempts
+	}
+	if def.RetryDelay <= 0 {
+		def.RetryDelay = jobDefaultRetryDelay
+	}
+
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+	jr.definitions[def.Name] = &def
+	return nil
+}
+
+// Start 스케줄러 시작 (ctx가 끝나면 멈추고 잠금을 반납)
+func (jr *JobRunner) Start(ctx context.Context) {
+	jr.mu.Lock()
+	if jr.running {
+		jr.mu.Unlock()
+		return
+	}
+	jr.running = true
+	jr.mu.Unlock()
+
+	// 이전 프로세스가 이 인스턴스에서 실행하다 중단된 작업
+	recoverInterruptedJobs(jr.instance, time.Time{})
+
+	go jr.loop(ctx)
+	log.Printf("Job runner started (instance %s)", jr.instance)
+}
+
+// Running 이 인스턴스에서 스케줄러가 동작 중인지
+func (jr *JobRunner) Running() bool {
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+	return jr.running
+}
+
+func (jr *JobRunner) loop(ctx context.Context) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	defer jr.releaseLeader()
+
+	for {
+		jr.tick(ctx)
+		select {
+		case <-ctx.Done():
+			jr.mu.Lock()
+			jr.running = false
+			jr.mu.Unlock()
+			return
+		case <-ticker.C:
+		case <-jr.wake:
+		}
+	}
+}
+
+// tick 잠금 확인, 스케줄 도래한 작업 등록, 대기 중인 작업 실행
+func (jr *JobRunner) tick(ctx context.Context) {
+	leader := jr.ensureLeader(ctx)
+	now := time.Now()
+
+	jr.mu.Lock()
+	var due []*JobDefinition
+	for name, def := range jr.definitions {
+		if def.schedule == nil {
+			continue
+		}
+		if !def.Local && !leader {
+			delete(jr.next, name) // 잠금을 다시 얻으면 그 시점부터 스케줄
+			continue
+		}
+		next, ok := jr.next[name]
+		if !ok {
+			jr.next[name] = def.schedule.Next(now)
+			continue
+		}
+		if !now.Before(next) {
+			due = append(due, def)
+			jr.next[name] = def.schedule.Next(now)
+		}
+	}
+	jr.mu.Unlock()
+
+	// 이전 잠금 보유자가 실행하다 중단된 공유 작업 (잠금을 잃고도 아직 실행 중일 수 있으므로 lease가 지난 작업만)
+	if leader {
+		recoverInterruptedJobs("", now.Add(-jobLease))
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].Name < due[j].Name })
+	for _, def := range due {
+		if _, err := jr.enqueue(def, nil, models.JobTriggerSchedule, true); err != nil {
+			log.Printf("Job %s: failed to schedule: %v", def.Name, err)
+		}
+	}
+
+	for ctx.Err() == nil {
+		job, err := jr.claim(leader)
+		if err != nil {
+			log.Printf("Job runner: failed to claim job: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		jr.execute(ctx, job)
+	}
+}
+
+// ensureLeader 공유 작업 실행 잠금 확인 또는 획득
+func (jr *JobRunner) ensureLeader(ctx context.Context) bool {
+	if jr.leaderConn != nil {
+		if err := jr.leaderConn.PingContext(ctx); err == nil {
+			return true
+		}
+		log.Printf("Job runner: lost job lock (instance %s)", jr.instance)
+		jr.releaseLeader()
+	}
+
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return false
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", jobLockKey).Scan(&acquired); err != nil || !acquired {
+		conn.Close()
+		return false
+	}
+
+	jr.mu.Lock()
+	jr.leaderConn, jr.leader = conn, true
+	jr.mu.Unlock()
+	log.Printf("Job runner: acquired job lock (instance %s)", jr.instance)
+	return true
+}
+
+// releaseLeader 잠금 반납 (연결을 닫으면 세션 잠금도 풀림)
+func (jr *JobRunner) releaseLeader() {
+	jr.mu.Lock()
+	defer jr.mu.Unlock()
+	if jr.leaderConn == nil {
+		return
+	}
+	jr.leaderConn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", jobLockKey)
+	jr.leaderConn.Close()
+	jr.leaderConn, jr.leader = nil, false
+}
+
+// recoverInterruptedJobs 실행 중 상태로 남은 작업을 다시 대기시키거나 실패 처리
+// staleBefore가 있으면 그 전부터 heartbeat(updated_at)가 없는 작업만 처리한다.
+func recoverInterruptedJobs(instance string, staleBefore time.Time) {
+	now := time.Now()
+	running := database.DB.Model(&models.Job{}).Where("status = ? AND instance = ?", models.JobRunning, instance)
+	if !staleBefore.IsZero() {
+		running = running.Where("updated_at < ?", staleBefore)
+	}
+
+	retried := running.Session(&gorm.Session{}).Where("attempt < max_attempts").
+		Updates(map[string]interface{}{"status": models.JobQueued, "error": "interrupted", "run_at": now, "updated_at": now})
+	failed := running.Session(&gorm.Session{}).
+		Updates(map[string]interface{}{"status": models.JobFailed, "error": "interrupted", "finished_at": now, "updated_at": now})
+	if retried.Error != nil || failed.Error != nil {
+		log.Printf("Job runner: failed to recover interrupted jobs: %v", errors.Join(retried.Error, failed.Error))
+		return
+	}
+	if retried.RowsAffected+failed.RowsAffected > 0 {
+		log.Printf("Job runner: recovered %d interrupted job(s), %d failed", retried.RowsAffected+failed.RowsAffected, failed.RowsAffected)
+	}
+}
+
+// EnqueueJob 작업을 바로 실행하도록 등록 (params는 JSON으로 저장)
+// 인스턴스별 작업은 요청을 받은 인스턴스에서 실행된다.
+func EnqueueJob(name string, params interface{}) (*models.Job, error) {
+	jr := GlobalJobRunner
+	jr.mu.Lock()
+	def, ok := jr.definitions[name]
+	jr.mu.Unlock()
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	return jr.enqueue(def, params, models.JobTriggerManual, false)
+}
+
+// enqueue 작업 기록 생성 (skipIfPending이면 같은 작업이 이미 대기/실행 중일 때 건너뜀)
+func (jr *JobRunner) enqueue(def *JobDefinition, params interface{}, trigger string, skipIfPending bool) (*models.Job, error) {
+	job := &models.Job{
+		Name:        def.Name,
+		Trigger:     trigger,
+		Status:      models.JobQueued,
+		MaxAttempts: def.MaxAttempts,
+		RunAt:       time.Now(),
+	}
+	if def.Local {
+		job.Instance = jr.instance
+	}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		job.Params = string(data)
+	}
+
+	if skipIfPending {
+		var pending int64
+		if err := database.DB.Model(&models.Job{}).
+			Where("name = ? AND instance = ? AND status IN ?", def.Name, job.Instance, []string{models.JobQueued, models.JobRunning}).
+			Count(&pending).Error; err != nil {
+			return nil, err
+		}
+		if pending > 0 {
+			return nil, nil
+		}
+	}
+
+	if err := database.DB.Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	// 이 인스턴스가 실행할 수 있으면 다음 확인을 기다리지 않음
+	select {
+	case jr.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// claim 실행할 작업 하나를 실행 중으로 표시 (공유 작업은 잠금을 가진 경우만)
+func (jr *JobRunner) claim(leader bool) (*models.Job, error) {
+	var job models.Job
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", models.JobQueued, time.Now())
+		if leader {
+			query = query.Where("instance IN ?", []string{"", jr.instance})
+		} else {
+			query = query.Where("instance = ?", jr.instance)
+		}
+		if err := query.Order("run_at, id").First(&job).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = models.JobRunning
+		job.Attempt++
+		job.StartedAt = &now
+		job.Progress, job.ProgressMessage = 0, ""
+		return tx.Model(&job).Updates(map[string]interface{}{
+			"status":           job.Status,
+			"attempt":          job.Attempt,
+			"started_at":       now,
+			"progress":         0,
+			"progress_message": "",
+			"updated_at":       now,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// execute 작업 실행 후 결과 기록 (실패하면 남은 시도 횟수만큼 재시도 예약)
+func (jr *JobRunner) execute(ctx context.Context, job *models.Job) {
+	jr.mu.Lock()
+	def, ok := jr.definitions[job.Name]
+	jr.mu.Unlock()
+
+	var result interface{}
+	var err error
+	var lost atomic.Bool
+	if !ok {
+		err = ErrUnknownJob
+		job.MaxAttempts = job.Attempt // 다시 시도해도 실패
+	} else {
+		runCtx, cancel := context.WithCancel(ctx)
+		if def.Timeout > 0 {
+			runCtx, cancel = context.WithTimeout(ctx, def.Timeout)
+		}
+		if job.Instance == "" {
+			// 잠금을 잃으면 다른 인스턴스가 이어받으므로 중단
+			go jr.heartbeat(runCtx, cancel, job, &lost)
+		}
+		result, err = runJob(runCtx, def, &JobRun{Job: job})
+		cancel()
+	}
+
+	if lost.Load() {
+		// 결과를 기록하지 않음 (새 잠금 보유자가 lease가 지난 뒤 다시 실행)
+		log.Printf("Job %d (%s) stopped: lost job lock", job.ID, job.Name)
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"updated_at": now}
+	switch {
+	case err == nil:
+		updates["status"] = models.JobSucceeded
+		updates["progress"] = 100
+		updates["error"] = ""
+		updates["finished_at"] = now
+		if result != nil {
+			if data, marshalErr := json.Marshal(result); marshalErr == nil {
+				updates["result"] = string(data)
+			}
+		}
+		log.Printf("Job %d (%s) succeeded", job.ID, job.Name)
+	case ctx.Err() != nil:
+		// 종료 중: 다음 실행 때 이어서 실행
+		updates["status"] = models.JobQueued
+		updates["error"] = "interrupted"
+		updates["run_at"] = now
+		log.Printf("Job %d (%s) interrupted by shutdown", job.ID, job.Name)
+	case job.Attempt < job.MaxAttempts && !errors.Is(err, ErrInvalidJobParams):
+		delay := def.RetryDelay << uint(job.Attempt-1)
+		updates["status"] = models.JobQueued
+		updates["error"] = err.Error()
+		updates["run_at"] = now.Add(delay)
+		log.Printf("Job %d (%s) attempt %d/%d failed, retrying in %s: %v", job.ID, job.Name, job.Attempt, job.MaxAttempts, delay, err)
+	default:
+		updates["status"] = models.JobFailed
+		updates["error"] = err.Error()
+		updates["finished_at"] = now
+		log.Printf("Job %d (%s) failed: %v", job.ID, job.Name, err)
+	}
+
+	// 다른 인스턴스가 이미 이어받았으면 (시도 횟수가 바뀜) 덮어쓰지 않음
+	if err := database.DB.Model(&models.Job{}).
+		Where("id = ? AND status = ? AND attempt = ?", job.ID, models.JobRunning, job.Attempt).
+		Updates(updates).Error; err != nil {
+		log.Printf("Job %d: failed to record result: %v", job.ID, err)
+	}
+}
+
+// heartbeat 공유 작업 실행 중 잠금 연결을 확인하고 updated_at을 갱신
+// 잠금을 잃었거나 다른 인스턴스가 작업을 이어받았으면 lost를 표시하고 cancel한다.
+func (jr *JobRunner) heartbeat(ctx context.Context, cancel context.CancelFunc, job *models.Job, lost *atomic.Bool) {
+	jr.mu.Lock()
+	conn := jr.leaderConn
+	jr.mu.Unlock()
+	if conn == nil {
+		lost.Store(true)
+		cancel()
+		return
+	}
+
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := conn.PingContext(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Job runner: lost job lock while running job %d, cancelling: %v", job.ID, err)
+			lost.Store(true)
+			cancel()
+			return
+		}
+
+		result := database.DB.Model(&models.Job{}).
+			Where("id = ? AND status = ? AND attempt = ?", job.ID, models.JobRunning, job.Attempt).
+			Update("updated_at", time.Now())
+		if result.Error == nil && result.RowsAffected == 0 {
+			log.Printf("Job runner: job %d was taken over by another instance, cancelling", job.ID)
+			lost.Store(true)
+			cancel()
+			return
+		}
+	}
+}
+
+// runJob 작업 함수 실행 (panic은 실패로 처리)
+func runJob(ctx context.Context, def *JobDefinition, run *JobRun) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return def.Run(ctx, run)
+}
+
+// GetJob 작업 기록 조회
+func GetJob(id uint) (*models.Job, error) {
+	var job models.Job
+	err := database.DB.First(&job, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListJobs 최근 작업 기록 (name, status가 비어 있으면 전체)
+func ListJobs(name, status string, limit int) ([]models.Job, error) {
+	if limit <= 0 || limit > jobListLimit {
+		limit = jobListLimit
+	}
+
+	query := database.DB.Order("id DESC").Limit(limit)
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	jobs := []models.Job{}
+	if err := query.Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// JobStatus 작업 실행기 상태와 작업별 다음/마지막 실행
+func JobStatus() (*JobRunnerStatus, error) {
+	jr := GlobalJobRunner
+	jr.mu.Lock()
+	status := &JobRunnerStatus{Instance: jr.instance, Running: jr.running, Leader: jr.leader, Jobs: []JobInfo{}}
+	for name, def := range jr.definitions {
+		info := JobInfo{Name: name, Description: def.Description, Schedule: def.Schedule, Local: def.Local, MaxAttempts: def.MaxAttempts}
+		if next, ok := jr.next[name]; ok {
+			info.NextRun = &next
+		}
+		status.Jobs = append(status.Jobs, info)
+	}
+	jr.mu.Unlock()
+
+	sort.Slice(status.Jobs, func(i, j int) bool { return status.Jobs[i].Name < status.Jobs[j].Name })
+	for i := range status.Jobs {
+		var last models.Job
+		err := database.DB.Where("name = ?", status.Jobs[i].Name).Order("id DESC").First(&last).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		status.Jobs[i].LastRun = &last
+	}
+	return status, nil
+}
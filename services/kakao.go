@@ -2,11 +2,16 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"ongi-back/models"
 	"os"
+	"strconv"
+
+	"gorm.io/gorm"
 )
 
 // KakaoUserInfo 카카오 사용자 정보
@@ -22,6 +27,7 @@ type KakaoAccount struct {
 	Profile           KakaoProfile `json:"profile"`
 	Email             string       `json:"email"`
 	EmailNeedsAgreement bool       `json:"email_needs_agreement"`
+	IsEmailVerified   bool         `json:"is_email_verified"`
 }
 
 // KakaoProfile 카카오 프로필 정보
@@ -128,3 +134,70 @@ func ValidateKakaoToken(accessToken string) (*KakaoUserInfo, error) {
 
 	return &userInfo, nil
 }
+
+// kakaoProvider 카카오 로그인 제공자
+type kakaoProvider struct{}
+
+func (kakaoProvider) Name() string { return "kakao" }
+
+// Authenticate access_token(클라이언트사이드) 또는 code(서버사이드)로 카카오 사용자 확인
+func (kakaoProvider) Authenticate(cred Credential) (*ExternalIdentity, error) {
+	accessToken := cred.AccessToken
+	if accessToken == "" && cred.Code != "" {
+		tokenResp, err := ExchangeCodeForToken(cred.Code)
+		if err != nil {
+			return nil, err
+		}
+		accessToken = tokenResp.AccessToken
+	}
+	if accessToken == "" {
+		return nil, ErrInvalidCredential
+	}
+
+	userInfo, err := ValidateKakaoToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return KakaoIdentity(userInfo), nil
+}
+
+// KakaoIdentity 카카오 사용자 정보를 외부 계정 정보로 변환
+func KakaoIdentity(userInfo *KakaoUserInfo) *ExternalIdentity {
+	name := userInfo.KakaoAccount.Profile.Nickname
+	if name == "" {
+		name = fmt.Sprintf("User_%d", userInfo.ID)
+	}
+
+	return &ExternalIdentity{
+		Provider:      "kakao",
+		Subject:       strconv.FormatInt(userInfo.ID, 10),
+		Email:         normalizeEmail(userInfo.KakaoAccount.Email),
+		EmailVerified: userInfo.KakaoAccount.IsEmailVerified,
+		Name:          name,
+	}
+}
+
+// findLegacyUser 식별자 도입 이전 카카오 가입자 찾기
+// 예전에는 이메일로 사용자를 식별하고 이메일이 없으면 kakao_<id>@kakao.com을 만들어 사용했다.
+// 당시 로그인 수단은 카카오뿐이었으므로, 식별자가 하나도 없는 계정에 한해 연결한다.
+// 카카오 계정 이메일은 미인증일 수 있으므로 카카오가 인증한 이메일만 비교한다.
+func (kakaoProvider) findLegacyUser(tx *gorm.DB, ext *ExternalIdentity) (*models.User, error) {
+	emails := []string{fmt.Sprintf("kakao_%s@kakao.com", ext.Subject)}
+	if ext.Email != "" && ext.EmailVerified {
+		emails = append(emails, ext.Email)
+	}
+
+	var user models.User
+	err := tx.Where("LOWER(email) IN ?", emails).
+		Where("NOT EXISTS (?)", tx.Model(&models.UserIdentity{}).Select("1").Where("user_identities.user_id = users.id")).
+		Order("id ASC").
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"ongi-back/i18n"
+	"ongi-back/models"
+)
+
+// LocalizeQuestions 질문/옵션 텍스트를 locale 언어로 교체 (번역이 없으면 원문 유지)
+func LocalizeQuestions(questions []models.Question, locale string) {
+	for i := range questions {
+		LocalizeQuestion(&questions[i], locale)
+	}
+}
+
+// LocalizeQuestion 질문 하나와 옵션 텍스트를 locale 언어로 교체
+func LocalizeQuestion(question *models.Question, locale string) {
+	if locale == i18n.DefaultLocale {
+		return
+	}
+
+	question.QuestionText = question.Translations.Text(locale, question.QuestionText)
+	for i := range question.Options {
+		option := &question.Options[i]
+		option.OptionText = option.Translations.Text(locale, option.OptionText)
+	}
+}
+
+// validateTranslations 번역문의 언어와 내용 확인 (원문이 기본 언어이므로 기본 언어 번역은 받지 않음)
+func validateTranslations(translations models.Translations) error {
+	for locale, text := range translations {
+		if !i18n.IsSupported(locale) || locale == i18n.DefaultLocale {
+			return fmt.Errorf("translations keys must be one of: %s", strings.Join(translationLocales(), ", "))
+		}
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("translation for %q must not be empty", locale)
+		}
+	}
+	return nil
+}
+
+// translationLocales 번역문을 받는 언어 (기본 언어 제외)
+func translationLocales() []string {
+	locales := make([]string, 0, len(i18n.SupportedLocales))
+	for _, locale := range i18n.SupportedLocales {
+		if locale != i18n.DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// copyTranslations 공백을 정리해 복사
+func copyTranslations(translations models.Translations) models.Translations {
+	copied := make(models.Translations, len(translations))
+	for locale, text := range translations {
+		copied[locale] = strings.TrimSpace(text)
+	}
+	return copied
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"ongi-back/config"
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// ErrUnknownMetric 지원하지 않는 유사도 척도
+var ErrUnknownMetric = errors.New("unknown similarity metric")
+
+// similarityMetrics 설정으로 정해지는 척도 (InitSimilarityMetrics에서 구성)
+var similarityMetrics struct {
+	mu          sync.Mutex
+	weighted    *utils.WeightedEuclideanMetric
+	mahalanobis *utils.MahalanobisMetric // 인덱스 모집단 공분산 기준, 인덱스 재구성 시 초기화
+}
+
+// InitSimilarityMetrics SIMILARITY_METRIC, COMPATIBILITY_METRIC, SIMILARITY_WEIGHTS 설정 검증
+func InitSimilarityMetrics() error {
+	weights, err := ParseMetricWeights(config.AppConfig.SimilarityWeights)
+	if err != nil {
+		return err
+	}
+	weighted, err := utils.NewWeightedEuclideanMetric(weights)
+	if err != nil {
+		return fmt.Errorf("SIMILARITY_WEIGHTS: %w", err)
+	}
+
+	similarityMetrics.mu.Lock()
+	similarityMetrics.weighted = weighted
+	similarityMetrics.mu.Unlock()
+
+	for key, name := range map[string]string{
+		"SIMILARITY_METRIC":    config.AppConfig.SimilarityMetric,
+		"COMPATIBILITY_METRIC": config.AppConfig.CompatibilityMetric,
+	} {
+		if !isMetricName(name) {
+			return fmt.Errorf("%s: %w: %q", key, ErrUnknownMetric, name)
+		}
+	}
+	return nil
+}
+
+// ParseMetricWeights "sociality:1.5,intimacy:2" 형식의 차원별 가중치 (지정하지 않은 차원은 1)
+func ParseMetricWeights(spec string) (*utils.Vector5D, error) {
+	weights := map[string]float64{}
+	for _, dimension := range models.Dimensions {
+		weights[dimension] = 1
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if _, known := weights[name]; !ok || !known {
+			return nil, fmt.Errorf("SIMILARITY_WEIGHTS: invalid entry %q", pair)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("SIMILARITY_WEIGHTS: invalid weight for %s", name)
+		}
+		weights[name] = w
+	}
+
+	return &utils.Vector5D{
+		Sociality:   weights[models.DimensionSociality],
+		Activity:    weights[models.DimensionActivity],
+		Intimacy:    weights[models.DimensionIntimacy],
+		Immersion:   weights[models.DimensionImmersion],
+		Flexibility: weights[models.DimensionFlexibility],
+	}, nil
+}
+
+// DefaultSimilarityMetric 유사 프로필 검색 기본 척도 (SIMILARITY_METRIC)
+func DefaultSimilarityMetric() (utils.SimilarityMetric, error) {
+	return ResolveSimilarityMetric(config.AppConfig.SimilarityMetric)
+}
+
+// DefaultCompatibilityMetric 궁합 계산 기본 척도 (COMPATIBILITY_METRIC)
+func DefaultCompatibilityMetric() (utils.SimilarityMetric, error) {
+	return ResolveSimilarityMetric(config.AppConfig.CompatibilityMetric)
+}
+
+// ResolveSimilarityMetric 이름으로 척도 조회
+// 마할라노비스 척도는 벡터 인덱스에 등록된 응답자들의 공분산으로 만들며, 응답자가 2명 미만이면 사용할 수 없다.
+func ResolveSimilarityMetric(name string) (utils.SimilarityMetric, error) {
+	switch name {
+	case utils.MetricCosine:
+		return utils.CosineMetric{}, nil
+	case utils.MetricEuclidean:
+		return utils.EuclideanMetric{}, nil
+	case utils.MetricWeightedEuclidean:
+		similarityMetrics.mu.Lock()
+		defer similarityMetrics.mu.Unlock()
+		if similarityMetrics.weighted == nil {
+			// InitSimilarityMetrics 전에는 균등 가중치 (예: cmd 도구)
+			similarityMetrics.weighted = &utils.WeightedEuclideanMetric{Weights: &utils.Vector5D{Sociality: 1, Activity: 1, Intimacy: 1, Immersion: 1, Flexibility: 1}}
+		}
+		return similarityMetrics.weighted, nil
+	case utils.MetricMahalanobis:
+		similarityMetrics.mu.Lock()
+		defer similarityMetrics.mu.Unlock()
+		if similarityMetrics.mahalanobis == nil {
+			metric, err := utils.NewMahalanobisMetric(GlobalVectorIndex.Vectors())
+			if err != nil {
+				return nil, err
+			}
+			similarityMetrics.mahalanobis = metric
+		}
+		return similarityMetrics.mahalanobis, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownMetric, name)
+}
+
+// resetPopulationMetrics 모집단에 의존하는 척도 초기화 (다음 조회 시 다시 계산)
+func resetPopulationMetrics() {
+	similarityMetrics.mu.Lock()
+	similarityMetrics.mahalanobis = nil
+	similarityMetrics.mu.Unlock()
+}
+
+func isMetricName(name string) bool {
+	for _, metric := range utils.MetricNames {
+		if metric == name {
+			return true
+		}
+	}
+	return false
+}
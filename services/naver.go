@@ -0,0 +1,84 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// NaverUserInfo 네이버 회원 프로필 조회 응답
+type NaverUserInfo struct {
+	ResultCode string `json:"resultcode"`
+	Message    string `json:"message"`
+	Response   struct {
+		ID       string `json:"id"`
+		Email    string `json:"email"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname"`
+	} `json:"response"`
+}
+
+// ValidateNaverToken 네이버 Access Token 검증 및 사용자 정보 가져오기
+func ValidateNaverToken(accessToken string) (*NaverUserInfo, error) {
+	req, err := http.NewRequest("GET", "https://openapi.naver.com/v1/nid/me", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call naver API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("naver API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var userInfo NaverUserInfo
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		return nil, fmt.Errorf("failed to parse naver response: %w", err)
+	}
+
+	if userInfo.ResultCode != "00" {
+		return nil, fmt.Errorf("naver API error %s: %s", userInfo.ResultCode, userInfo.Message)
+	}
+
+	return &userInfo, nil
+}
+
+// naverProvider 네이버 로그인 제공자
+type naverProvider struct{}
+
+func (naverProvider) Name() string { return "naver" }
+
+func (naverProvider) Authenticate(cred Credential) (*ExternalIdentity, error) {
+	if cred.AccessToken == "" {
+		return nil, ErrInvalidCredential
+	}
+
+	userInfo, err := ValidateNaverToken(cred.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	name := userInfo.Response.Nickname
+	if name == "" {
+		name = userInfo.Response.Name
+	}
+
+	return &ExternalIdentity{
+		Subject: userInfo.Response.ID,
+		Email:   userInfo.Response.Email,
+		// 네이버는 인증된 계정 이메일만 제공한다
+		EmailVerified: userInfo.Response.Email != "",
+		Name:          name,
+	}, nil
+}
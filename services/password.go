@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"net/mail"
+	"ongi-back/database"
+	"ongi-back/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const minPasswordLength = 8
+
+var ErrWeakPassword = fmt.Errorf("password must be at least %d characters", minPasswordLength)
+
+// 존재하지 않는 계정에도 동일한 비교 비용을 쓰기 위한 더미 해시
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("ongi-dummy-password"), bcrypt.DefaultCost)
+
+// emailProvider 이메일/비밀번호 로그인 제공자 (subject는 정규화된 이메일)
+type emailProvider struct{}
+
+func (emailProvider) Name() string { return "email" }
+
+// Authenticate 이메일/비밀번호 확인
+func (emailProvider) Authenticate(cred Credential) (*ExternalIdentity, error) {
+	email := normalizeEmail(cred.Email)
+	if email == "" || cred.Password == "" {
+		return nil, ErrInvalidCredential
+	}
+
+	var identity models.UserIdentity
+	if err := database.DB.Where("provider = ? AND subject = ?", "email", email).First(&identity).Error; err != nil {
+		// 존재 여부를 노출하지 않도록 동일한 비용으로 비교
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(cred.Password))
+		return nil, ErrInvalidCredential
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(identity.PasswordHash), []byte(cred.Password)); err != nil {
+		return nil, ErrInvalidCredential
+	}
+
+	return &ExternalIdentity{
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+	}, nil
+}
+
+// RegisterWithEmail 이메일/비밀번호 회원가입
+func RegisterWithEmail(email, password, name string) (models.User, error) {
+	ext, passwordHash, err := newEmailIdentity(email, password, name)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.UserIdentity{}).
+			Where("provider = ? AND subject = ?", "email", ext.Subject).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return &AccountExistsError{Email: ext.Email, Providers: []string{"email"}}
+		}
+
+		if err := checkEmailConflict(tx, ext.Email); err != nil {
+			return err
+		}
+
+		user, err = createUserWithIdentity(tx, ext, passwordHash)
+		return err
+	})
+
+	return user, err
+}
+
+// LinkEmailPassword 로그인된 사용자에게 이메일/비밀번호 로그인 수단 연결
+func LinkEmailPassword(userID uint, email, password string) (*models.UserIdentity, error) {
+	ext, passwordHash, err := newEmailIdentity(email, password, "")
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := LinkIdentity(userID, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	// 이미 연결된 경우에도 새 비밀번호로 갱신
+	if err := database.DB.Model(identity).Update("password_hash", passwordHash).Error; err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func newEmailIdentity(email, password, name string) (*ExternalIdentity, string, error) {
+	email = normalizeEmail(email)
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, "", ErrInvalidCredential
+	}
+	if len(password) < minPasswordLength {
+		return nil, "", ErrWeakPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return &ExternalIdentity{
+		Provider: "email",
+		Subject:  email,
+		Email:    email,
+		// 이메일 인증 절차가 없으므로 미인증으로 저장
+		EmailVerified: false,
+		Name:          name,
+	}, string(hash), nil
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+
+	"ongi-back/database"
+	"ongi-back/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 프로필 규칙 출처
+const (
+	ProfileRuleSourceFile     = "file"     // PROFILE_RULES_FILE
+	ProfileRuleSourceDatabase = "database" // profile_rules 테이블
+	ProfileRuleSourceDefault  = "default"  // 내장 기본 규칙
+)
+
+//go:embed profile_rules.yaml
+var defaultProfileRulesYAML []byte
+
+// ProfileRuleError 프로필 규칙 정의가 올바르지 않은 경우
+// Index는 같은 종류의 규칙 중 몇 번째인지(0부터), RuleID는 DB 규칙의 ID다.
+type ProfileRuleError struct {
+	Kind   string
+	Index  int
+	RuleID uint
+	Reason string
+}
+
+func (e *ProfileRuleError) Error() string {
+	if e.RuleID != 0 {
+		return fmt.Sprintf("invalid %s rule (id %d): %s", e.Kind, e.RuleID, e.Reason)
+	}
+	return fmt.Sprintf("invalid %s rule #%d: %s", e.Kind, e.Index+1, e.Reason)
+}
+
+// ProfileRuleSet 프로필 타입/설명/수준 판정 규칙 묶음 (우선순위 내림차순 정렬)
+type ProfileRuleSet struct {
+	Source       string               `json:"source"`
+	ProfileTypes []models.ProfileRule `json:"profile_types"`
+	Descriptions []models.ProfileRule `json:"descriptions"`
+	Levels       []models.ProfileRule `json:"levels"`
+	groupOrder   []string
+}
+
+// ProfileEvaluation 점수 벡터에 규칙을 적용한 결과
+type ProfileEvaluation struct {
+	ProfileType  string              `json:"profile_type"`
+	MatchedRule  *models.ProfileRule `json:"matched_rule,omitempty"`
+	Descriptions []string            `json:"descriptions"`
+	Levels       map[string]string   `json:"levels"`
+}
+
+// profileRuleFile YAML 규칙 파일 형식
+type profileRuleFile struct {
+	ProfileTypes []profileRuleEntry `yaml:"profile_types"`
+	Descriptions []profileRuleEntry `yaml:"descriptions"`
+	Levels       []profileRuleEntry `yaml:"levels"`
+}
+
+type profileRuleEntry struct {
+	Group        string                 `yaml:"group"`
+	Label        string                 `yaml:"label"`
+	Priority     int                    `yaml:"priority"`
+	Conditions   models.DimensionRanges `yaml:"conditions"`
+	Translations models.Translations    `yaml:"translations"`
+}
+
+var (
+	profileRulesMu     sync.RWMutex
+	activeProfileRules *ProfileRuleSet
+
+	defaultProfileRulesOnce sync.Once
+	defaultProfileRules     *ProfileRuleSet
+)
+
+// InitProfileRules 프로필 규칙 로드 (PROFILE_RULES_FILE > profile_rules 테이블 > 내장 기본 규칙)
+func InitProfileRules(path string) error {
+	rules, err := LoadProfileRules(path)
+	if err != nil {
+		return err
+	}
+
+	setProfileRules(rules)
+	log.Printf("Profile rules loaded from %s (%d types, %d descriptions, %d levels)",
+		rules.Source, len(rules.ProfileTypes), len(rules.Descriptions), len(rules.Levels))
+	return nil
+}
+
+// LoadProfileRules 규칙을 출처 우선순위에 따라 읽고 검증
+func LoadProfileRules(path string) (*ProfileRuleSet, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read profile rules file: %w", err)
+		}
+		return parseProfileRulesYAML(data, ProfileRuleSourceFile)
+	}
+
+	var stored []models.ProfileRule
+	if err := database.DB.Order("id ASC").Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	if len(stored) > 0 {
+		return newProfileRuleSet(stored, ProfileRuleSourceDatabase)
+	}
+
+	return builtinProfileRules(), nil
+}
+
+// CurrentProfileRules 현재 적용 중인 규칙
+func CurrentProfileRules() *ProfileRuleSet {
+	profileRulesMu.RLock()
+	defer profileRulesMu.RUnlock()
+
+	if activeProfileRules == nil {
+		// InitProfileRules 전에는 내장 기본 규칙 사용 (예: cmd 도구)
+		return builtinProfileRules()
+	}
+	return activeProfileRules
+}
+
+// builtinProfileRules 내장 기본 규칙 (빌드에 포함된 파일이므로 파싱 실패는 프로그래밍 오류)
+func builtinProfileRules() *ProfileRuleSet {
+	defaultProfileRulesOnce.Do(func() {
+		rules, err := parseProfileRulesYAML(defaultProfileRulesYAML, ProfileRuleSourceDefault)
+		if err != nil {
+			panic(err)
+		}
+		defaultProfileRules = rules
+	})
+	return defaultProfileRules
+}
+
+func setProfileRules(rules *ProfileRuleSet) {
+	profileRulesMu.Lock()
+	activeProfileRules = rules
+	profileRulesMu.Unlock()
+}
+
+// EvaluateProfile 점수 벡터에 규칙 적용 (문구는 locale 언어로 반환)
+func (rs *ProfileRuleSet) EvaluateProfile(scores *ScoreResult, locale string) *ProfileEvaluation {
+	byDimension := scores.ByDimension()
+	evaluation := &ProfileEvaluation{
+		Descriptions: []string{},
+		Levels:       make(map[string]string, len(models.Dimensions)),
+	}
+
+	for i := range rs.ProfileTypes {
+		if profileRuleMatches(rs.ProfileTypes[i].Conditions, byDimension) {
+			evaluation.ProfileType = rs.ProfileTypes[i].Translations.Text(locale, rs.ProfileTypes[i].Label)
+			evaluation.MatchedRule = &rs.ProfileTypes[i]
+			break
+		}
+	}
+
+	for _, group := range rs.groupOrder {
+		for _, rule := range rs.Descriptions {
+			if rule.Group == group && profileRuleMatches(rule.Conditions, byDimension) {
+				evaluation.Descriptions = append(evaluation.Descriptions, rule.Translations.Text(locale, rule.Label))
+				break
+			}
+		}
+	}
+
+	for _, dimension := range models.Dimensions {
+		evaluation.Levels[dimension] = rs.Level(byDimension[dimension], locale)
+	}
+
+	return evaluation
+}
+
+// Level 차원 점수 하나의 수준
+func (rs *ProfileRuleSet) Level(score float64, locale string) string {
+	values := map[string]float64{models.ProfileRuleScoreKey: score}
+	for _, rule := range rs.Levels {
+		if profileRuleMatches(rule.Conditions, values) {
+			return rule.Translations.Text(locale, rule.Label)
+		}
+	}
+	return ""
+}
+
+// DimensionLevel 현재 규칙으로 차원 점수 하나의 수준 판정
+func DimensionLevel(score float64, locale string) string {
+	return CurrentProfileRules().Level(score, locale)
+}
+
+// LocalizeProfileType 저장된 프로필 타입(기본 언어 label)을 locale 언어로 변환
+// 현재 규칙에 없는 타입(규칙 변경 전 결과)은 그대로 반환한다.
+func LocalizeProfileType(profileType, locale string) string {
+	for _, rule := range CurrentProfileRules().ProfileTypes {
+		if rule.Label == profileType {
+			return rule.Translations.Text(locale, rule.Label)
+		}
+	}
+	return profileType
+}
+
+// LocalizedDescriptions locale 언어의 설명 문구
+func LocalizedDescriptions(scores *ScoreResult, locale string) []string {
+	return CurrentProfileRules().EvaluateProfile(scores, locale).Descriptions
+}
+
+func profileRuleMatches(conditions models.DimensionRanges, values map[string]float64) bool {
+	for key, scoreRange := range conditions {
+		if !scoreRange.Contains(values[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseProfileRulesYAML(data []byte, source string) (*ProfileRuleSet, error) {
+	var file profileRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse profile rules: %w", err)
+	}
+
+	var rules []models.ProfileRule
+	appendEntries := func(kind string, entries []profileRuleEntry) {
+		for _, entry := range entries {
+			rules = append(rules, models.ProfileRule{
+				Kind:         kind,
+				Group:        entry.Group,
+				Label:        entry.Label,
+				Priority:     entry.Priority,
+				Conditions:   entry.Conditions,
+				Translations: entry.Translations,
+			})
+		}
+	}
+	appendEntries(models.ProfileRuleKindType, file.ProfileTypes)
+	appendEntries(models.ProfileRuleKindDescription, file.Descriptions)
+	appendEntries(models.ProfileRuleKindLevel, file.Levels)
+
+	return newProfileRuleSet(rules, source)
+}
+
+// newProfileRuleSet 규칙을 종류별로 나누고 검증 (같은 우선순위는 정의 순서 유지)
+func newProfileRuleSet(rules []models.ProfileRule, source string) (*ProfileRuleSet, error) {
+	rs := &ProfileRuleSet{
+		Source:       source,
+		ProfileTypes: []models.ProfileRule{},
+		Descriptions: []models.ProfileRule{},
+		Levels:       []models.ProfileRule{},
+	}
+	seenGroups := make(map[string]bool)
+
+	for _, rule := range rules {
+		switch rule.Kind {
+		case models.ProfileRuleKindType:
+			rs.ProfileTypes = append(rs.ProfileTypes, rule)
+		case models.ProfileRuleKindDescription:
+			rs.Descriptions = append(rs.Descriptions, rule)
+			if !seenGroups[rule.Group] {
+				seenGroups[rule.Group] = true
+				rs.groupOrder = append(rs.groupOrder, rule.Group)
+			}
+		case models.ProfileRuleKindLevel:
+			rs.Levels = append(rs.Levels, rule)
+		default:
+			return nil, &ProfileRuleError{Kind: rule.Kind, RuleID: rule.ID, Reason: "unknown rule kind"}
+		}
+	}
+
+	for _, list := range [][]models.ProfileRule{rs.ProfileTypes, rs.Descriptions, rs.Levels} {
+		for i, rule := range list {
+			if err := validateProfileRule(rule); err != nil {
+				return nil, &ProfileRuleError{Kind: rule.Kind, Index: i, RuleID: rule.ID, Reason: err.Error()}
+			}
+		}
+		sort.SliceStable(list, func(a, b int) bool { return list[a].Priority > list[b].Priority })
+	}
+
+	if len(rs.ProfileTypes) == 0 {
+		return nil, &ProfileRuleError{Kind: models.ProfileRuleKindType, Reason: "at least one profile type rule is required"}
+	}
+
+	return rs, nil
+}
+
+func validateProfileRule(rule models.ProfileRule) error {
+	if rule.Label == "" {
+		return fmt.Errorf("label is required")
+	}
+	if rule.Kind == models.ProfileRuleKindDescription && rule.Group == "" {
+		return fmt.Errorf("group is required for description rules")
+	}
+
+	for key, scoreRange := range rule.Conditions {
+		if rule.Kind == models.ProfileRuleKindLevel {
+			if key != models.ProfileRuleScoreKey {
+				return fmt.Errorf("level rules only accept the %q condition", models.ProfileRuleScoreKey)
+			}
+		} else if !models.IsValidDimension(key) {
+			return fmt.Errorf("unknown dimension %q", key)
+		}
+
+		if scoreRange.Min != nil && scoreRange.Max != nil && *scoreRange.Min >= *scoreRange.Max {
+			return fmt.Errorf("condition %q: min must be less than max", key)
+		}
+	}
+
+	return validateTranslations(rule.Translations)
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ongi-back/database"
+	"ongi-back/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrQuestionnaireNotFound    = errors.New("questionnaire not found")
+	ErrNoPublishedQuestionnaire = errors.New("no published questionnaire")
+	ErrQuestionnaireNotDraft    = errors.New("questionnaire is not a draft")
+	ErrQuestionnaireNotOpen     = errors.New("questionnaire is not open for answers")
+	ErrQuestionnaireEmpty       = errors.New("questionnaire has no questions")
+)
+
+// InvalidAnswerError 설문 버전에 속하지 않는 질문/옵션으로 응답한 경우
+type InvalidAnswerError struct {
+	QuestionID uint
+	OptionID   uint
+	Reason     string
+}
+
+func (e *InvalidAnswerError) Error() string {
+	return fmt.Sprintf("invalid answer (question %d, option %d): %s", e.QuestionID, e.OptionID, e.Reason)
+}
+
+// ListQuestionnaires 설문 버전 목록 (최신 버전부터, includeDrafts가 false면 초안 제외)
+func ListQuestionnaires(includeDrafts bool) ([]models.Questionnaire, error) {
+	var questionnaires []models.Questionnaire
+	query := database.DB.Order("version DESC")
+	if !includeDrafts {
+		query = query.Where("status <> ?", models.QuestionnaireStatusDraft)
+	}
+	err := query.Find(&questionnaires).Error
+	return questionnaires, err
+}
+
+// GetActiveQuestionnaire 현재 발행된 설문 버전
+func GetActiveQuestionnaire() (*models.Questionnaire, error) {
+	var questionnaire models.Questionnaire
+	err := database.DB.Where("status = ?", models.QuestionnaireStatusPublished).
+		Order("version DESC").
+		First(&questionnaire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoPublishedQuestionnaire
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &questionnaire, nil
+}
+
+// GetQuestionnaire ID로 설문 버전 조회
+func GetQuestionnaire(id uint) (*models.Questionnaire, error) {
+	var questionnaire models.Questionnaire
+	err := database.DB.First(&questionnaire, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuestionnaireNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &questionnaire, nil
+}
+
+// GetQuestionnaireWithQuestions 질문/옵션을 포함한 설문 버전 조회
+func GetQuestionnaireWithQuestions(id uint) (*models.Questionnaire, error) {
+	var questionnaire models.Questionnaire
+	err := database.DB.
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("\"order\" ASC")
+		}).
+		Preload("Questions.Options").
+		First(&questionnaire, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuestionnaireNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &questionnaire, nil
+}
+
+// ResolveAnswerableQuestionnaire 응답에 사용할 설문 버전 결정 (0이면 현재 발행된 버전)
+// 설문 도중 새 버전이 발행되어도 제출할 수 있도록 보관된 버전도 허용하고, 초안만 거부한다.
+func ResolveAnswerableQuestionnaire(id uint) (*models.Questionnaire, error) {
+	if id == 0 {
+		return GetActiveQuestionnaire()
+	}
+
+	questionnaire, err := GetQuestionnaire(id)
+	if err != nil {
+		return nil, err
+	}
+	if questionnaire.Status == models.QuestionnaireStatusDraft {
+		return nil, ErrQuestionnaireNotOpen
+	}
+	return questionnaire, nil
+}
+
+// ValidateAnswers 모든 응답이 해당 설문 버전의 질문/옵션인지 확인
+func ValidateAnswers(questionnaireID uint, answers []models.AnswerPayload) error {
+	var questions []models.Question
+	if err := database.DB.Preload("Options").
+		Where("questionnaire_id = ?", questionnaireID).
+		Find(&questions).Error; err != nil {
+		return err
+	}
+
+	optionsByQuestion := make(map[uint]map[uint]bool, len(questions))
+	for _, question := range questions {
+		options := make(map[uint]bool, len(question.Options))
+		for _, option := range question.Options {
+			options[option.ID] = true
+		}
+		optionsByQuestion[question.ID] = options
+	}
+
+	answered := make(map[uint]bool, len(answers))
+	for _, ans := range answers {
+		options, ok := optionsByQuestion[ans.QuestionID]
+		if !ok {
+			return &InvalidAnswerError{QuestionID: ans.QuestionID, OptionID: ans.OptionID, Reason: "question does not belong to this questionnaire"}
+		}
+		if !options[ans.OptionID] {
+			return &InvalidAnswerError{QuestionID: ans.QuestionID, OptionID: ans.OptionID, Reason: "option does not belong to this question"}
+		}
+		if answered[ans.QuestionID] {
+			return &InvalidAnswerError{QuestionID: ans.QuestionID, OptionID: ans.OptionID, Reason: "question answered more than once"}
+		}
+		answered[ans.QuestionID] = true
+	}
+
+	return nil
+}
+
+// CreateQuestionnaireDraft 새 설문 버전 초안 생성
+// baseID가 있으면 해당 버전의 질문/옵션을 복사해 시작한다.
+func CreateQuestionnaireDraft(title string, baseID uint) (*models.Questionnaire, error) {
+	var draft models.Questionnaire
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var maxVersion int
+		if err := tx.Model(&models.Questionnaire{}).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return err
+		}
+
+		// 새 버전은 기존 버전을 복사해도 가중치 벡터 정규화로 계산한다 (평균 방식은 변환된 과거 버전 전용)
+		draft = models.Questionnaire{
+			Version:       maxVersion + 1,
+			Title:         title,
+			Status:        models.QuestionnaireStatusDraft,
+			ScoringMethod: models.ScoringMethodWeighted,
+		}
+		if err := tx.Create(&draft).Error; err != nil {
+			return err
+		}
+
+		if baseID == 0 {
+			return nil
+		}
+
+		var baseQuestions []models.Question
+		if err := tx.Preload("Options").
+			Where("questionnaire_id = ?", baseID).
+			Order("\"order\" ASC").
+			Find(&baseQuestions).Error; err != nil {
+			return err
+		}
+		if len(baseQuestions) == 0 {
+			return ErrQuestionnaireNotFound
+		}
+
+		for _, base := range baseQuestions {
+			question := models.Question{
+				QuestionnaireID: draft.ID,
+				QuestionText:    base.QuestionText,
+				Order:           base.Order,
+				Category:        base.Category,
+				Translations:    copyTranslations(base.Translations),
+			}
+			for _, option := range base.Options {
+				question.Options = append(question.Options, models.Option{
+					OptionText:   option.OptionText,
+					Weights:      copyWeights(option.Weights),
+					Translations: copyTranslations(option.Translations),
+				})
+			}
+			if err := tx.Create(&question).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// PublishQuestionnaire 초안을 검증하여 발행하고 이전에 발행된 버전은 보관 처리
+func PublishQuestionnaire(id uint) (*models.Questionnaire, error) {
+	var questionnaire models.Questionnaire
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		draft, err := lockDraftQuestionnaire(tx, id)
+		if err != nil {
+			return err
+		}
+		questionnaire = *draft
+
+		var questionCount int64
+		if err := tx.Model(&models.Question{}).
+			Where("questionnaire_id = ?", id).
+			Count(&questionCount).Error; err != nil {
+			return err
+		}
+		if questionCount == 0 {
+			return ErrQuestionnaireEmpty
+		}
+		if err := validatePublishable(tx, id); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Questionnaire{}).
+			Where("status = ?", models.QuestionnaireStatusPublished).
+			Update("status", models.QuestionnaireStatusArchived).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		questionnaire.Status = models.QuestionnaireStatusPublished
+		questionnaire.PublishedAt = &now
+		return tx.Model(&questionnaire).Updates(map[string]interface{}{
+			"status":       questionnaire.Status,
+			"published_at": now,
+		}).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return &questionnaire, nil
+}
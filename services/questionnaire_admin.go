@@ -0,0 +1,500 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"ongi-back/database"
+	"ongi-back/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxOptionWeight    = 5.0 // 옵션 가중치 절대값 상한
+	minQuestionOptions = 2
+)
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrOptionNotFound   = errors.New("option not found")
+)
+
+// QuestionValidationError 질문/옵션 입력값이 올바르지 않은 경우
+type QuestionValidationError struct {
+	Reason string
+}
+
+func (e *QuestionValidationError) Error() string {
+	return e.Reason
+}
+
+// CoverageError 설문이 일부 성향 차원을 측정하지 못하게 되는 경우
+type CoverageError struct {
+	Missing []string
+}
+
+func (e *CoverageError) Error() string {
+	return fmt.Sprintf("questionnaire must cover every dimension (missing: %s)", strings.Join(e.Missing, ", "))
+}
+
+// QuestionInput 질문 작성 입력
+type QuestionInput struct {
+	QuestionText string              `json:"question_text"`
+	Order        int                 `json:"order"`
+	Category     string              `json:"category"`
+	Translations models.Translations `json:"translations"`
+	Options      []OptionInput       `json:"options"`
+}
+
+// OptionInput 옵션 작성 입력
+type OptionInput struct {
+	OptionText   string                  `json:"option_text"`
+	Weights      models.DimensionWeights `json:"weights"`
+	Translations models.Translations     `json:"translations"`
+}
+
+// QuestionUpdate 질문 수정 입력 (nil 필드는 유지, translations는 전체 교체)
+type QuestionUpdate struct {
+	QuestionText *string             `json:"question_text"`
+	Order        *int                `json:"order"`
+	Category     *string             `json:"category"`
+	Translations models.Translations `json:"translations"`
+}
+
+// OptionUpdate 옵션 수정 입력 (nil 필드는 유지, weights와 translations는 전체 교체)
+type OptionUpdate struct {
+	OptionText   *string                 `json:"option_text"`
+	Weights      models.DimensionWeights `json:"weights"`
+	Translations models.Translations     `json:"translations"`
+}
+
+// QuestionnaireCoverage 설문 버전이 측정하는 성향 차원
+type QuestionnaireCoverage struct {
+	Covered []string `json:"covered"`
+	Missing []string `json:"missing"`
+}
+
+// GetQuestionnaireCoverage 설문 버전의 차원 커버리지 조회
+func GetQuestionnaireCoverage(questionnaireID uint) (*QuestionnaireCoverage, error) {
+	covered, err := dimensionCoverage(database.DB, questionnaireID)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := &QuestionnaireCoverage{Covered: []string{}, Missing: []string{}}
+	for _, dimension := range models.Dimensions {
+		if covered[dimension] {
+			coverage.Covered = append(coverage.Covered, dimension)
+		} else {
+			coverage.Missing = append(coverage.Missing, dimension)
+		}
+	}
+	return coverage, nil
+}
+
+// DeleteQuestionnaireDraft 발행되지 않은 초안 삭제
+func DeleteQuestionnaireDraft(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockDraftQuestionnaire(tx, id); err != nil {
+			return err
+		}
+
+		questionIDs := tx.Model(&models.Question{}).Select("id").Where("questionnaire_id = ?", id)
+		if err := tx.Where("question_id IN (?)", questionIDs).Delete(&models.Option{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("questionnaire_id = ?", id).Delete(&models.Question{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Questionnaire{}, id).Error
+	})
+}
+
+// CreateQuestion 초안 설문에 질문과 옵션 추가
+func CreateQuestion(questionnaireID uint, input QuestionInput) (*models.Question, error) {
+	if err := validateQuestionFields(input.QuestionText, input.Order, input.Category, input.Translations); err != nil {
+		return nil, err
+	}
+	if len(input.Options) < minQuestionOptions {
+		return nil, &QuestionValidationError{Reason: fmt.Sprintf("a question needs at least %d options", minQuestionOptions)}
+	}
+	for _, option := range input.Options {
+		if err := validateOptionFields(option.OptionText, option.Weights, option.Translations); err != nil {
+			return nil, err
+		}
+	}
+
+	question := models.Question{
+		QuestionnaireID: questionnaireID,
+		QuestionText:    strings.TrimSpace(input.QuestionText),
+		Order:           input.Order,
+		Category:        input.Category,
+		Translations:    copyTranslations(input.Translations),
+	}
+	for _, option := range input.Options {
+		question.Options = append(question.Options, models.Option{
+			OptionText:   strings.TrimSpace(option.OptionText),
+			Weights:      copyWeights(option.Weights),
+			Translations: copyTranslations(option.Translations),
+		})
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockDraftQuestionnaire(tx, questionnaireID); err != nil {
+			return err
+		}
+		if err := checkOrderAvailable(tx, questionnaireID, input.Order, 0); err != nil {
+			return err
+		}
+		return tx.Create(&question).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// UpdateQuestion 초안 설문의 질문 수정
+func UpdateQuestion(questionID uint, update QuestionUpdate) (*models.Question, error) {
+	var question models.Question
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&question, questionID).Error; err != nil {
+			return notFoundOr(err, ErrQuestionNotFound)
+		}
+		if _, err := lockDraftQuestionnaire(tx, question.QuestionnaireID); err != nil {
+			return err
+		}
+
+		if update.QuestionText != nil {
+			question.QuestionText = strings.TrimSpace(*update.QuestionText)
+		}
+		if update.Order != nil {
+			question.Order = *update.Order
+		}
+		if update.Category != nil {
+			question.Category = *update.Category
+		}
+		if update.Translations != nil {
+			question.Translations = copyTranslations(update.Translations)
+		}
+		if err := validateQuestionFields(question.QuestionText, question.Order, question.Category, question.Translations); err != nil {
+			return err
+		}
+		if err := checkOrderAvailable(tx, question.QuestionnaireID, question.Order, question.ID); err != nil {
+			return err
+		}
+
+		return tx.Model(&question).Updates(map[string]interface{}{
+			"question_text": question.QuestionText,
+			"order":         question.Order,
+			"category":      question.Category,
+			"translations":  question.Translations,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	database.DB.Preload("Options").First(&question, questionID)
+	return &question, nil
+}
+
+// DeleteQuestion 초안 설문의 질문 삭제 (차원 커버리지가 줄어들면 거부)
+func DeleteQuestion(questionID uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var question models.Question
+		if err := tx.First(&question, questionID).Error; err != nil {
+			return notFoundOr(err, ErrQuestionNotFound)
+		}
+		if _, err := lockDraftQuestionnaire(tx, question.QuestionnaireID); err != nil {
+			return err
+		}
+
+		return withCoverageGuard(tx, question.QuestionnaireID, func() error {
+			if err := tx.Where("question_id = ?", question.ID).Delete(&models.Option{}).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&question).Error
+		})
+	})
+}
+
+// CreateOption 초안 설문의 질문에 옵션 추가
+func CreateOption(questionID uint, input OptionInput) (*models.Option, error) {
+	if err := validateOptionFields(input.OptionText, input.Weights, input.Translations); err != nil {
+		return nil, err
+	}
+
+	option := models.Option{
+		QuestionID:   questionID,
+		OptionText:   strings.TrimSpace(input.OptionText),
+		Weights:      copyWeights(input.Weights),
+		Translations: copyTranslations(input.Translations),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var question models.Question
+		if err := tx.First(&question, questionID).Error; err != nil {
+			return notFoundOr(err, ErrQuestionNotFound)
+		}
+		if _, err := lockDraftQuestionnaire(tx, question.QuestionnaireID); err != nil {
+			return err
+		}
+		return tx.Create(&option).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+// UpdateOption 초안 설문의 옵션 수정 (차원 커버리지가 줄어들면 거부)
+func UpdateOption(optionID uint, update OptionUpdate) (*models.Option, error) {
+	var option models.Option
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		question, err := loadOptionQuestion(tx, optionID, &option)
+		if err != nil {
+			return err
+		}
+		if _, err := lockDraftQuestionnaire(tx, question.QuestionnaireID); err != nil {
+			return err
+		}
+
+		if update.OptionText != nil {
+			option.OptionText = strings.TrimSpace(*update.OptionText)
+		}
+		if update.Weights != nil {
+			option.Weights = copyWeights(update.Weights)
+		}
+		if update.Translations != nil {
+			option.Translations = copyTranslations(update.Translations)
+		}
+		if err := validateOptionFields(option.OptionText, option.Weights, option.Translations); err != nil {
+			return err
+		}
+
+		return withCoverageGuard(tx, question.QuestionnaireID, func() error {
+			return tx.Model(&option).Select("option_text", "weights", "translations").Updates(&option).Error
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+// DeleteOption 초안 설문의 옵션 삭제 (최소 옵션 수 유지, 차원 커버리지가 줄어들면 거부)
+func DeleteOption(optionID uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var option models.Option
+		question, err := loadOptionQuestion(tx, optionID, &option)
+		if err != nil {
+			return err
+		}
+		if _, err := lockDraftQuestionnaire(tx, question.QuestionnaireID); err != nil {
+			return err
+		}
+
+		var optionCount int64
+		if err := tx.Model(&models.Option{}).Where("question_id = ?", question.ID).Count(&optionCount).Error; err != nil {
+			return err
+		}
+		if optionCount <= minQuestionOptions {
+			return &QuestionValidationError{Reason: fmt.Sprintf("a question needs at least %d options", minQuestionOptions)}
+		}
+
+		return withCoverageGuard(tx, question.QuestionnaireID, func() error {
+			return tx.Delete(&option).Error
+		})
+	})
+}
+
+// validatePublishable 발행 전 검증: 모든 차원 커버, 질문별 최소 옵션 수
+func validatePublishable(tx *gorm.DB, questionnaireID uint) error {
+	covered, err := dimensionCoverage(tx, questionnaireID)
+	if err != nil {
+		return err
+	}
+	if missing := missingDimensions(covered); len(missing) > 0 {
+		return &CoverageError{Missing: missing}
+	}
+
+	var thinQuestions []uint
+	if err := tx.Model(&models.Question{}).
+		Select("questions.id").
+		Joins("LEFT JOIN options ON options.question_id = questions.id").
+		Where("questions.questionnaire_id = ?", questionnaireID).
+		Group("questions.id").
+		Having("COUNT(options.id) < ?", minQuestionOptions).
+		Pluck("questions.id", &thinQuestions).Error; err != nil {
+		return err
+	}
+	if len(thinQuestions) > 0 {
+		return &QuestionValidationError{Reason: fmt.Sprintf("question %d needs at least %d options", thinQuestions[0], minQuestionOptions)}
+	}
+
+	return nil
+}
+
+// lockDraftQuestionnaire 설문을 잠그고 초안인지 확인 (발행된 버전은 수정 불가)
+// 같은 설문에 대한 동시 편집을 직렬화하여 커버리지 검사가 경쟁 없이 이루어지도록 한다.
+func lockDraftQuestionnaire(tx *gorm.DB, questionnaireID uint) (*models.Questionnaire, error) {
+	var questionnaire models.Questionnaire
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&questionnaire, questionnaireID).Error; err != nil {
+		return nil, notFoundOr(err, ErrQuestionnaireNotFound)
+	}
+	if questionnaire.Status != models.QuestionnaireStatusDraft {
+		return nil, ErrQuestionnaireNotDraft
+	}
+	return &questionnaire, nil
+}
+
+// withCoverageGuard 변경 전후의 차원 커버리지를 비교하여 줄어들면 에러 반환 (트랜잭션 롤백)
+func withCoverageGuard(tx *gorm.DB, questionnaireID uint, mutate func() error) error {
+	before, err := dimensionCoverage(tx, questionnaireID)
+	if err != nil {
+		return err
+	}
+
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	after, err := dimensionCoverage(tx, questionnaireID)
+	if err != nil {
+		return err
+	}
+
+	var lost []string
+	for _, dimension := range models.Dimensions {
+		if before[dimension] && !after[dimension] {
+			lost = append(lost, dimension)
+		}
+	}
+	if len(lost) > 0 {
+		return &CoverageError{Missing: lost}
+	}
+	return nil
+}
+
+// dimensionCoverage 설문 버전의 옵션이 0이 아닌 가중치를 주는 차원 집합
+func dimensionCoverage(tx *gorm.DB, questionnaireID uint) (map[string]bool, error) {
+	var options []models.Option
+	err := tx.Model(&models.Option{}).
+		Joins("JOIN questions ON questions.id = options.question_id").
+		Where("questions.questionnaire_id = ?", questionnaireID).
+		Find(&options).Error
+	if err != nil {
+		return nil, err
+	}
+
+	covered := make(map[string]bool, len(models.Dimensions))
+	for _, option := range options {
+		for dimension, weight := range option.Weights {
+			if weight != 0 {
+				covered[dimension] = true
+			}
+		}
+	}
+	return covered, nil
+}
+
+func missingDimensions(covered map[string]bool) []string {
+	var missing []string
+	for _, dimension := range models.Dimensions {
+		if !covered[dimension] {
+			missing = append(missing, dimension)
+		}
+	}
+	return missing
+}
+
+func checkOrderAvailable(tx *gorm.DB, questionnaireID uint, order int, excludeQuestionID uint) error {
+	var count int64
+	if err := tx.Model(&models.Question{}).
+		Where("questionnaire_id = ? AND \"order\" = ? AND id <> ?", questionnaireID, order, excludeQuestionID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return &QuestionValidationError{Reason: fmt.Sprintf("order %d is already used in this questionnaire", order)}
+	}
+	return nil
+}
+
+func loadOptionQuestion(tx *gorm.DB, optionID uint, option *models.Option) (*models.Question, error) {
+	if err := tx.First(option, optionID).Error; err != nil {
+		return nil, notFoundOr(err, ErrOptionNotFound)
+	}
+
+	var question models.Question
+	if err := tx.First(&question, option.QuestionID).Error; err != nil {
+		return nil, notFoundOr(err, ErrQuestionNotFound)
+	}
+	return &question, nil
+}
+
+func validateQuestionFields(text string, order int, category string, translations models.Translations) error {
+	if strings.TrimSpace(text) == "" {
+		return &QuestionValidationError{Reason: "question_text is required"}
+	}
+	if order <= 0 {
+		return &QuestionValidationError{Reason: "order must be a positive number"}
+	}
+	if !models.IsValidDimension(category) {
+		return &QuestionValidationError{Reason: fmt.Sprintf("category must be one of: %s", strings.Join(models.Dimensions, ", "))}
+	}
+	if err := validateTranslations(translations); err != nil {
+		return &QuestionValidationError{Reason: err.Error()}
+	}
+	return nil
+}
+
+func validateOptionFields(text string, weights models.DimensionWeights, translations models.Translations) error {
+	if strings.TrimSpace(text) == "" {
+		return &QuestionValidationError{Reason: "option_text is required"}
+	}
+
+	nonZero := false
+	for dimension, weight := range weights {
+		if !models.IsValidDimension(dimension) {
+			return &QuestionValidationError{Reason: fmt.Sprintf("weights keys must be one of: %s", strings.Join(models.Dimensions, ", "))}
+		}
+		if math.Abs(weight) > maxOptionWeight {
+			return &QuestionValidationError{Reason: fmt.Sprintf("weights must be between %g and %g", -maxOptionWeight, maxOptionWeight)}
+		}
+		if weight != 0 {
+			nonZero = true
+		}
+	}
+	if !nonZero {
+		return &QuestionValidationError{Reason: "an option needs at least one non-zero weight"}
+	}
+	if err := validateTranslations(translations); err != nil {
+		return &QuestionValidationError{Reason: err.Error()}
+	}
+	return nil
+}
+
+// copyWeights 0인 가중치를 제외하고 복사
+func copyWeights(weights models.DimensionWeights) models.DimensionWeights {
+	copied := make(models.DimensionWeights, len(weights))
+	for dimension, weight := range weights {
+		if weight != 0 {
+			copied[dimension] = weight
+		}
+	}
+	return copied
+}
+
+func notFoundOr(err error, notFound error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return err
+}
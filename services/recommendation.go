@@ -1,125 +1,89 @@
 package services
 
 import (
-	"math"
+	"fmt"
+
 	"ongi-back/database"
 	"ongi-back/models"
-	"sort"
+	"ongi-back/utils"
 )
 
 type UserSimilarity struct {
-	User       models.User `json:"user"`
-	Similarity float64     `json:"similarity"`
-}
-
-// 유클리드 거리 기반 유사도 계산
-func calculateSimilarity(profile1, profile2 *models.UserProfile) float64 {
-	diff1 := profile1.SocialityScore - profile2.SocialityScore
-	diff2 := profile1.ActivityScore - profile2.ActivityScore
-	diff3 := profile1.IntimacyScore - profile2.IntimacyScore
-	diff4 := profile1.ImmersionScore - profile2.ImmersionScore
-	diff5 := profile1.FlexibilityScore - profile2.FlexibilityScore
+	User        models.User  `json:"user"`
+	Similarity  float64      `json:"similarity"`
+	Explanation *Explanation `json:"explanation,omitempty"` // ExplainSimilarUsers로 추가
 
-	distance := math.Sqrt(
-		diff1*diff1 + diff2*diff2 + diff3*diff3 + diff4*diff4 + diff5*diff5,
-	)
-
-	// 거리를 유사도로 변환 (0-100)
-	maxDistance := math.Sqrt(5 * 100 * 100) // 최대 거리
-	similarity := (1 - (distance / maxDistance)) * 100
-
-	return similarity
+	vector *utils.Vector5D
 }
 
-func GetSimilarUsers(userID uint, limit int) ([]UserSimilarity, error) {
-	var userProfile models.UserProfile
-	err := database.DB.Where("user_id = ?", userID).First(&userProfile).Error
-	if err != nil {
-		return nil, err
-	}
-
-	var allProfiles []models.UserProfile
-	err = database.DB.Preload("User").
-		Where("user_id != ?", userID).
-		Find(&allProfiles).Error
+// GetSimilarUsers 성향이 비슷한 회원 (유사도 70% 이상, metric이 nil이면 SIMILARITY_METRIC)
+func GetSimilarUsers(userID uint, limit int, metric utils.SimilarityMetric) ([]UserSimilarity, error) {
+	profiles, err := FindSimilarRespondents(UserRespondent(userID), limit, SimilarityFilter{MembersOnly: true, Metric: metric})
 	if err != nil {
 		return nil, err
 	}
 
-	// 유사도 계산
 	similarities := []UserSimilarity{}
-	for _, profile := range allProfiles {
-		similarity := calculateSimilarity(&userProfile, &profile)
+	for _, profile := range profiles {
+		if profile.User == nil || profile.Similarity < 70.0 {
+			continue
+		}
 		similarities = append(similarities, UserSimilarity{
-			User:       profile.User,
-			Similarity: similarity,
+			User:       *profile.User,
+			Similarity: profile.Similarity,
+			vector:     profile.Vector,
 		})
 	}
 
-	// 70% 이상 유사도만 필터링
-	var filteredSimilarities []UserSimilarity
-	for _, sim := range similarities {
-		if sim.Similarity >= 70.0 {
-			filteredSimilarities = append(filteredSimilarities, sim)
-		}
-	}
-
-	// 유사도 높은 순으로 정렬
-	sort.Slice(filteredSimilarities, func(i, j int) bool {
-		return filteredSimilarities[i].Similarity > filteredSimilarities[j].Similarity
-	})
-
-	// 상위 N명 반환
-	maxResults := limit
-	if len(filteredSimilarities) < maxResults {
-		maxResults = len(filteredSimilarities)
-	}
-
-	return filteredSimilarities[:maxResults], nil
+	return similarities, nil
 }
 
-func GetRecommendedClubs(userID uint, limit int) ([]models.Club, error) {
-	var userProfile models.UserProfile
-	err := database.DB.Where("user_id = ?", userID).First(&userProfile).Error
+// RecommendClubs - 성향 기반 클럽 추천 (클럽 선호 성향과 가까운 순)
+func RecommendClubs(r Respondent, limit int) ([]models.Club, error) {
+	matches, err := RecommendClubMatches(r, limit, ClubFilter{})
 	if err != nil {
 		return nil, err
 	}
 
-	var clubs []models.Club
-	query := database.DB.Preload("Members")
-
-	// 사교성이 높은 사람에게는 멤버가 많은 클럽 추천
-	if userProfile.SocialityScore >= 70 {
-		query = query.Order("member_count DESC")
-	} else {
-		// 친밀도가 높은 사람에게는 적당한 규모의 클럽 추천
-		query = query.Order("member_count ASC")
+	clubs := make([]models.Club, len(matches))
+	for i, match := range matches {
+		clubs[i] = match.Club
 	}
+	return clubs, nil
+}
 
-	err = query.Limit(limit).Find(&clubs).Error
+// RecommendClubsWithSimilarMembers - 유사한 사람들이 많은 클럽 추천
+func RecommendClubsWithSimilarMembers(r Respondent, limit int) ([]models.Club, error) {
+	clubs, _, err := similarMemberClubs(r, limit)
 	if err != nil {
 		return nil, err
 	}
-
+	if clubs == nil {
+		return RecommendClubs(r, limit)
+	}
 	return clubs, nil
 }
 
-func GetClubsWithSimilarMembers(userID uint, limit int) ([]models.Club, error) {
-	// 유사한 사용자들이 많이 가입한 클럽 찾기
-	similarUsers, err := GetSimilarUsers(userID, 20)
+// similarMemberClubs 유사한 회원이 많이 가입한 클럽과 클럽별 유사 회원 수 (유사 회원이 없으면 nil)
+func similarMemberClubs(r Respondent, limit int) ([]models.Club, map[uint]int64, error) {
+	// 1. 유사한 회원 찾기
+	similarProfiles, err := FindSimilarRespondents(r, 20, SimilarityFilter{MembersOnly: true})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if len(similarUsers) == 0 {
-		return GetRecommendedClubs(userID, limit)
+	var userIDs []uint
+	for _, profile := range similarProfiles {
+		if profile.UserID != nil {
+			userIDs = append(userIDs, *profile.UserID)
+		}
 	}
 
-	var userIDs []uint
-	for _, userSim := range similarUsers {
-		userIDs = append(userIDs, userSim.User.ID)
+	if len(userIDs) == 0 {
+		return nil, nil, nil
 	}
 
+	// 2. 클럽별 유사 사용자 수 계산
 	type ClubCount struct {
 		ClubID uint
 		Count  int64
@@ -135,48 +99,90 @@ func GetClubsWithSimilarMembers(userID uint, limit int) ([]models.Club, error) {
 		Scan(&clubCounts).Error
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	// 3. 클럽 정보 가져오기
 	var clubIDs []uint
+	counts := make(map[uint]int64, len(clubCounts))
 	for _, cc := range clubCounts {
 		clubIDs = append(clubIDs, cc.ClubID)
+		counts[cc.ClubID] = cc.Count
 	}
 
-	var clubs []models.Club
+	clubs := []models.Club{}
 	if len(clubIDs) > 0 {
 		err = database.DB.Where("id IN ?", clubIDs).Find(&clubs).Error
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+
+		// 원래 순서대로 정렬 (count 높은 순)
+		clubMap := make(map[uint]models.Club)
+		for _, club := range clubs {
+			clubMap[club.ID] = club
+		}
+
+		sortedClubs := make([]models.Club, 0, len(clubIDs))
+		for _, id := range clubIDs {
+			if club, ok := clubMap[id]; ok {
+				sortedClubs = append(sortedClubs, club)
+			}
+		}
+		clubs = sortedClubs
 	}
 
-	return clubs, nil
+	return clubs, counts, nil
 }
 
-func GetRecommendedMeetings(userID uint, limit int) ([]models.Meeting, error) {
-	var userProfile models.UserProfile
-	err := database.DB.Where("user_id = ?", userID).First(&userProfile).Error
+// 모임 추천 기준
+const (
+	meetingRuleActivity = "activity" // 활동성이 높음 → 가까운 일정
+	meetingRuleIntimacy = "intimacy" // 친밀도가 높음 → 소규모
+	meetingRuleRecent   = "recent"   // 그 외 → 최근 생성
+)
+
+// RecommendMeetings - 성향 기반 모임 추천
+func RecommendMeetings(r Respondent, limit int) ([]models.Meeting, error) {
+	meetings, _, err := recommendMeetings(r, limit)
+	return meetings, err
+}
+
+// recommendMeetings 성향 기반 모임 추천과 적용한 추천 기준 (기준 순서에 추천 피드백 반영)
+func recommendMeetings(r Respondent, limit int) ([]models.Meeting, string, error) {
+	v, err := RespondentVector(r)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var meetings []models.Meeting
 	query := database.DB.Preload("Club")
 
 	// 활동성이 높은 사람에게는 다양한 모임 추천
-	if userProfile.ActivityScore >= 70 {
+	var rule string
+	if v.Activity >= 70 {
+		rule = meetingRuleActivity
 		query = query.Order("scheduled_at ASC")
+	} else if v.Intimacy >= 60 {
+		// 친밀도가 높은 사람에게는 소규모 모임
+		rule = meetingRuleIntimacy
+		query = query.Where("max_members <= ?", 20).Order("max_members ASC")
 	} else {
-		query = query.Order("max_members ASC")
+		rule = meetingRuleRecent
+		query = query.Order("created_at DESC")
 	}
 
-	err = query.Limit(limit).Find(&meetings).Error
+	// 피드백으로 순서가 바뀌므로 후보를 더 가져옴
+	err = query.Limit(limit * meetingCandidateRatio).Find(&meetings).Error
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return meetings, nil
+	meetings, err = blendMeetingFeedback(r, meetings, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return meetings, rule, nil
 }
 
 type UserGroup struct {
@@ -184,202 +190,79 @@ type UserGroup struct {
 	AvgProfile models.UserProfile
 }
 
-// 비슷한 성향의 사용자들을 그룹화하고 적합한 클럽에 매칭
-func MatchUsersToClubs() error {
+// MatchUsersToClubs 비슷한 성향의 사용자들을 군집화하여 그룹별로 가장 잘 맞는 클럽에 매칭
+// 그룹은 함께 들어갈 자리가 있는 클럽 하나에 배정되며, opts.DryRun이면 저장하지 않고 계획만 반환한다.
+func MatchUsersToClubs(opts MatchOptions) (*MatchReport, error) {
 	// 1. 프로필이 있는 모든 사용자 가져오기
 	var profiles []models.UserProfile
 	err := database.DB.Preload("User").Find(&profiles).Error
 	if err != nil {
-		return err
-	}
-
-	if len(profiles) == 0 {
-		return nil
+		return nil, err
 	}
 
-	// 2. 사용자들을 유사도 기반으로 그룹화
-	groups := groupSimilarUsers(profiles, 70.0) // 70% 이상 유사도
-
-	// 3. 각 그룹에 적합한 클럽 찾기
+	// 2. 클럽 정보 (그룹 최대 인원은 클럽의 남은 정원을 넘지 않음)
 	var clubs []models.Club
 	err = database.DB.Preload("Members").Find(&clubs).Error
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 4. 각 그룹을 클럽에 매칭
-	for _, group := range groups {
-		if len(group.Users) == 0 {
-			continue
-		}
-
-		// 그룹의 평균 성향과 가장 잘 맞는 클럽 찾기
-		bestClub := findBestClubForGroup(group, clubs)
-		if bestClub == nil {
-			continue
-		}
-
-		// 그룹의 모든 사용자를 해당 클럽에 추가
-		addedCount := 0
-		for _, user := range group.Users {
-			// 이미 가입했는지 확인
-			var existingMember models.ClubMember
-			err := database.DB.Where("club_id = ? AND user_id = ?", bestClub.ID, user.ID).
-				First(&existingMember).Error
-
-			if err != nil { // 가입하지 않은 경우만 추가
-				member := models.ClubMember{
-					ClubID: bestClub.ID,
-					UserID: user.ID,
-				}
-				database.DB.Create(&member)
-				addedCount++
-			}
-		}
-
-		// 실제 추가된 멤버 수만큼 증가
-		if addedCount > 0 {
-			database.DB.Model(&models.Club{}).
-				Where("id = ?", bestClub.ID).
-				Update("member_count", database.DB.Raw("member_count + ?", addedCount))
-		}
+	maxGroupSize := opts.MaxGroupSize
+	if maxGroupSize <= 0 {
+		maxGroupSize = DefaultMaxGroupSize
+	}
+	if capacity := remainingGroupCapacity(clubs); capacity >= 0 && capacity < maxGroupSize {
+		maxGroupSize = capacity
 	}
 
-	return nil
-}
-
-// 유사한 사용자들을 그룹화
-func groupSimilarUsers(profiles []models.UserProfile, threshold float64) []UserGroup {
-	var groups []UserGroup
-	used := make(map[uint]bool)
+	// 3. 사용자들을 성향 벡터 기반으로 군집화
+	groups, report, err := clusterProfiles(profiles, opts, maxGroupSize)
+	if err != nil {
+		return nil, err
+	}
 
-	for i, profile1 := range profiles {
-		if used[profile1.UserID] {
+	// 4. 그룹별 후보 클럽 (그룹 평균 성향과 클럽 선호 성향이 가까운 순)
+	units := make([]AssignmentUnit, 0, len(groups))
+	centroids := make([]*utils.Vector5D, 0, len(groups))
+	for i, group := range groups {
+		if len(group.Users) == 0 {
 			continue
 		}
 
-		// 새 그룹 생성
-		group := UserGroup{
-			Users: []models.User{profile1.User},
-		}
-		used[profile1.UserID] = true
-
-		// 유사한 사용자들 찾기
-		for j, profile2 := range profiles {
-			if i == j || used[profile2.UserID] {
-				continue
-			}
-
-			similarity := calculateSimilarity(&profile1, &profile2)
-			if similarity >= threshold {
-				group.Users = append(group.Users, profile2.User)
-				used[profile2.UserID] = true
-			}
+		unit := AssignmentUnit{Key: fmt.Sprintf("group:%d", i), UserIDs: make([]uint, len(group.Users)), MaxClubs: 1}
+		for j, user := range group.Users {
+			unit.UserIDs[j] = user.ID
 		}
-
-		// 그룹의 평균 프로필 계산
-		group.AvgProfile = calculateGroupAverage(profiles, group.Users)
-		groups = append(groups, group)
-	}
-
-	return groups
-}
-
-// 그룹의 평균 성향 계산
-func calculateGroupAverage(allProfiles []models.UserProfile, groupUsers []models.User) models.UserProfile {
-	var sumSociality, sumActivity, sumIntimacy, sumImmersion, sumFlexibility float64
-	count := 0
-
-	userIDMap := make(map[uint]bool)
-	for _, user := range groupUsers {
-		userIDMap[user.ID] = true
-	}
-
-	for _, profile := range allProfiles {
-		if userIDMap[profile.UserID] {
-			sumSociality += profile.SocialityScore
-			sumActivity += profile.ActivityScore
-			sumIntimacy += profile.IntimacyScore
-			sumImmersion += profile.ImmersionScore
-			sumFlexibility += profile.FlexibilityScore
-			count++
+		centroid := profileVector(&group.AvgProfile)
+		for _, match := range RankClubs(centroid, clubs, ClubFilter{}) {
+			unit.Candidates = append(unit.Candidates, AssignmentCandidate{ClubID: match.Club.ID, Score: match.Score})
 		}
+		units = append(units, unit)
+		centroids = append(centroids, centroid)
 	}
 
-	if count == 0 {
-		return models.UserProfile{}
-	}
-
-	return models.UserProfile{
-		SocialityScore:   sumSociality / float64(count),
-		ActivityScore:    sumActivity / float64(count),
-		IntimacyScore:    sumIntimacy / float64(count),
-		ImmersionScore:   sumImmersion / float64(count),
-		FlexibilityScore: sumFlexibility / float64(count),
-	}
-}
-
-// 그룹에 가장 적합한 클럽 찾기
-func findBestClubForGroup(group UserGroup, clubs []models.Club) *models.Club {
-	if len(clubs) == 0 {
-		return nil
+	// 5. 정원과 카테고리 제약을 지키며 한 트랜잭션에서 배정
+	plan, err := AssignClubs(units, opts.DryRun)
+	if err != nil {
+		return nil, err
 	}
-
-	var bestClub *models.Club
-	bestScore := -1.0
-
-	for i := range clubs {
-		club := &clubs[i]
-
-		// 멤버 수 체크 (그룹 전체가 들어갈 수 있는지)
-		if club.MaxMembers > 0 && club.MemberCount+len(group.Users) > club.MaxMembers {
+	report.DryRun = opts.DryRun
+	report.Assignment = plan
+
+	for i, unitPlan := range plan.Units {
+		assignment := GroupAssignment{UserIDs: unitPlan.UserIDs, Centroid: centroids[i], Reason: unitPlan.Reason}
+		if len(unitPlan.Clubs) == 0 {
+			report.UnmatchedGroups++
+			report.Groups = append(report.Groups, assignment)
 			continue
 		}
 
-		// 클럽의 선호 성향과 그룹 평균 성향의 유사도 계산
-		score := calculateClubMatchScore(group.AvgProfile, club)
-
-		if score > bestScore {
-			bestScore = score
-			bestClub = club
-		}
-	}
-
-	return bestClub
-}
-
-// 클럽과 그룹의 매칭 점수 계산
-func calculateClubMatchScore(avgProfile models.UserProfile, club *models.Club) float64 {
-	// Vibe에 따른 성향 매칭
-	score := 0.0
-
-	switch club.Vibe {
-	case "energetic":
-		score += avgProfile.SocialityScore * 0.3
-		score += avgProfile.ActivityScore * 0.4
-		score += avgProfile.FlexibilityScore * 0.3
-	case "cozy":
-		score += avgProfile.IntimacyScore * 0.4
-		score += avgProfile.ImmersionScore * 0.3
-		score += (100 - avgProfile.ActivityScore) * 0.3
-	case "deep":
-		score += avgProfile.ImmersionScore * 0.5
-		score += avgProfile.IntimacyScore * 0.3
-		score += avgProfile.FlexibilityScore * 0.2
-	case "casual":
-		score += avgProfile.FlexibilityScore * 0.4
-		score += avgProfile.SocialityScore * 0.3
-		score += avgProfile.ActivityScore * 0.3
-	case "chill":
-		score += (100 - avgProfile.ActivityScore) * 0.4
-		score += avgProfile.FlexibilityScore * 0.3
-		score += avgProfile.IntimacyScore * 0.3
-	default:
-		// 균형잡힌 점수
-		score = (avgProfile.SocialityScore + avgProfile.ActivityScore +
-			avgProfile.IntimacyScore + avgProfile.ImmersionScore +
-			avgProfile.FlexibilityScore) / 5.0
+		clubID := unitPlan.Clubs[0].ClubID
+		assignment.ClubID = &clubID
+		assignment.Added = len(unitPlan.Clubs[0].JoinedUsers)
+		report.MatchedUsers += len(unitPlan.Clubs[0].JoinedUsers)
+		report.Groups = append(report.Groups, assignment)
 	}
 
-	return score
+	return report, nil
 }
@@ -3,10 +3,14 @@ package services
 import (
 	"math"
 	"ongi-back/database"
+	"ongi-back/i18n"
 	"ongi-back/models"
 	"ongi-back/utils"
-	"runtime"
 	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 // SimilarProfile - 유사한 프로필 정보
@@ -16,256 +20,456 @@ type SimilarProfile struct {
 	User       *models.User  `json:"user,omitempty"`
 	Similarity float64       `json:"similarity"`
 	Vector     *utils.Vector5D `json:"vector,omitempty"`
+	Explanation *Explanation `json:"explanation,omitempty"` // ExplainSimilarProfiles로 추가
 }
 
-// GetSimilarProfilesFast - 고속 유사 프로필 검색 (벡터 연산 최적화)
-func GetSimilarProfilesFast(sessionID string, limit int) ([]SimilarProfile, error) {
-	// 1. 현재 세션의 벡터 가져오기
-	var currentVector models.SessionVector
-	err := database.DB.Where("session_id = ?", sessionID).First(&currentVector).Error
-	if err != nil {
-		return nil, err
-	}
-
-	currentV := utils.FromSlice(currentVector.Vector)
-	if currentV == nil {
-		return nil, err
+// FindSimilarRespondents - 벡터 인덱스 기반 유사 프로필 검색
+// 회원/비회원 모두 같은 벡터 공간에서 비교하며, filter로 회원만 또는 만료되지 않은 세션만 검색할 수 있다.
+// filter.Metric이 nil이면 SIMILARITY_METRIC 척도를 사용한다.
+func FindSimilarRespondents(r Respondent, limit int, filter SimilarityFilter) ([]SimilarProfile, error) {
+	if filter.Metric == nil {
+		metric, err := DefaultSimilarityMetric()
+		if err != nil {
+			return nil, err
+		}
+		filter.Metric = metric
 	}
 
-	// 2. 모든 벡터 가져오기 (자기 자신 제외)
-	var allVectors []models.SessionVector
-	err = database.DB.
-		Preload("User").
-		Where("session_id != ?", sessionID).
-		Find(&allVectors).Error
-
-	if err != nil {
-		return nil, err
+	// 1. 현재 응답자의 벡터 가져오기 (인덱스에 없으면 DB)
+	currentV, indexed := GlobalVectorIndex.Lookup(r)
+	if !indexed {
+		var err error
+		if currentV, err = RespondentVector(r); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(allVectors) == 0 {
-		return []SimilarProfile{}, nil
+	// 2. 자기 자신 제외 (인덱스에 있는 비회원 세션은 연동되지 않은 세션)
+	var selfUserID uint
+	if r.IsUser() || !indexed {
+		selfUserID = linkedUserID(r)
 	}
-
-	// 3. 벡터 변환
-	vectors := make([]*utils.Vector5D, len(allVectors))
-	for i, v := range allVectors {
-		vectors[i] = utils.FromSlice(v.Vector)
+	exclude := func(candidate Respondent) bool {
+		return candidate == r || (selfUserID != 0 && candidate.IsUser() && candidate.UserID == selfUserID)
 	}
 
-	// 4. 병렬 유사도 계산 (CPU 코어 수만큼 워커 사용)
-	workers := runtime.NumCPU()
-	results := utils.BatchSimilarity(currentV, vectors, workers)
+	// 3. 상위 N개 검색
+	matches := GlobalVectorIndex.Search(currentV, limit, filter, exclude)
 
-	// 5. 결과를 SimilarProfile로 변환
-	profiles := make([]SimilarProfile, len(results))
-	for i, result := range results {
+	// 4. 결과를 SimilarProfile로 변환 (회원 정보는 한 번에 조회)
+	profiles := make([]SimilarProfile, len(matches))
+	var userIDs []uint
+	for i, match := range matches {
 		profile := SimilarProfile{
-			SessionID:  allVectors[result.Index].SessionID,
-			UserID:     allVectors[result.Index].UserID,
-			Similarity: result.Similarity,
-			Vector:     vectors[result.Index],
+			Similarity: match.Similarity,
+			Vector:     match.Entry.Vector,
 		}
-
-		// 회원인 경우 User 정보 포함
-		if profile.UserID != nil {
-			var user models.User
-			database.DB.First(&user, *profile.UserID)
-			profile.User = &user
+		if match.Entry.Respondent.IsUser() {
+			userID := match.Entry.Respondent.UserID
+			profile.UserID = &userID
+			userIDs = append(userIDs, userID)
+		} else {
+			profile.SessionID = match.Entry.Respondent.SessionID
 		}
-
 		profiles[i] = profile
 	}
 
-	// 6. 유사도 높은 순으로 정렬
-	sort.Slice(profiles, func(i, j int) bool {
-		return profiles[i].Similarity > profiles[j].Similarity
-	})
-
-	// 7. 상위 N개 반환
-	if len(profiles) > limit {
-		profiles = profiles[:limit]
+	if len(userIDs) > 0 {
+		var users []models.User
+		if err := database.DB.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		userMap := make(map[uint]*models.User, len(users))
+		for i := range users {
+			userMap[users[i].ID] = &users[i]
+		}
+		for i := range profiles {
+			if profiles[i].UserID != nil {
+				profiles[i].User = userMap[*profiles[i].UserID]
+			}
+		}
 	}
 
 	return profiles, nil
 }
 
-// GetRecommendedClubsForSession - 세션 기반 클럽 추천
-func GetRecommendedClubsForSession(sessionID string, limit int) ([]models.Club, error) {
-	var sessionVector models.SessionVector
-	err := database.DB.Where("session_id = ?", sessionID).First(&sessionVector).Error
-	if err != nil {
-		return nil, err
-	}
+// CalculateProfileCompatibility - 두 프로필 간 궁합 점수 계산 (응답에 사용한 척도 포함)
+func CalculateProfileCompatibility(v1, v2 *utils.Vector5D, metric utils.SimilarityMetric, locale string) map[string]interface{} {
+	similarity := metric.Score(v1, v2)
 
-	v := utils.FromSlice(sessionVector.Vector)
-	if v == nil {
-		return []models.Club{}, nil
+	// 차원별 궁합 분석
+	compatibility := map[string]interface{}{
+		"overall_score": similarity,
+		"metric":        metric.Name(),
+		"details": map[string]interface{}{
+			"sociality_match":   100 - math.Abs(v1.Sociality-v2.Sociality),
+			"activity_match":    100 - math.Abs(v1.Activity-v2.Activity),
+			"intimacy_match":    100 - math.Abs(v1.Intimacy-v2.Intimacy),
+			"immersion_match":   100 - math.Abs(v1.Immersion-v2.Immersion),
+			"flexibility_match": 100 - math.Abs(v1.Flexibility-v2.Flexibility),
+		},
 	}
 
-	var clubs []models.Club
-	query := database.DB.Preload("Members")
-
-	// 사교성이 높은 사람에게는 멤버가 많은 클럽 추천
-	if v.Sociality >= 70 {
-		query = query.Order("member_count DESC")
-	} else if v.Intimacy >= 60 {
-		// 친밀도가 높은 사람에게는 적당한 규모의 클럽 추천
-		query = query.Where("member_count <= ?", 50).Order("member_count ASC")
+	// 궁합 평가
+	var grade string
+	if similarity >= 80 {
+		grade = "best"
+	} else if similarity >= 70 {
+		grade = "good"
+	} else if similarity >= 60 {
+		grade = "average"
+	} else if similarity >= 50 {
+		grade = "complementary"
 	} else {
-		// 균형잡힌 사람에게는 중간 규모
-		query = query.Where("member_count BETWEEN ? AND ?", 10, 100)
+		grade = "interesting"
 	}
+	compatibility["rating"] = i18n.T(locale, "compatibility."+grade+".rating")
+	compatibility["description"] = i18n.T(locale, "compatibility."+grade+".description")
 
-	err = query.Limit(limit).Find(&clubs).Error
-	if err != nil {
-		return nil, err
-	}
+	return compatibility
+}
 
-	return clubs, nil
+// 추천 피드백 반영 비율
+const (
+	feedbackNeighbors     = 50   // 협업 신호를 모을 유사 응답자 수
+	collaborativeWeight   = 30.0 // 유사 응답자 반응(-1~1)에 곱해 점수에 더함
+	personalWeight        = 20.0 // 본인의 분위기/카테고리 반응(-1~1)에 곱해 점수에 더함
+	ignoredPenalty        = 0.5  // 노출만 되고 반응하지 않은 항목의 감점 (관심 없음은 2)
+	affinityPrior         = 5.0  // 반응이 적을 때 0 쪽으로 당기는 가상 노출 수
+	meetingCandidateRatio = 3    // 모임은 기준 순서로 limit의 몇 배를 가져와 다시 정렬
+)
+
+// 유사 응답자의 항목별 반응 점수
+var neighborSignal = map[string]float64{
+	models.FeedbackJoin:    1,
+	models.FeedbackClick:   0.3,
+	models.FeedbackDismiss: -0.5,
 }
 
-// GetClubsWithSimilarMembersForSession - 유사한 사람들이 많은 클럽 추천
-func GetClubsWithSimilarMembersForSession(sessionID string, limit int) ([]models.Club, error) {
-	// 1. 유사한 프로필 찾기
-	similarProfiles, err := GetSimilarProfilesFast(sessionID, 20)
-	if err != nil {
-		return nil, err
+// feedbackSignals 추천 순위에 더할 피드백 신호
+type feedbackSignals struct {
+	collaborative map[uint]float64   // 항목별 유사 응답자 반응 (유사도 가중 평균, -1~1)
+	affinity      map[string]float64 // 본인의 속성별 반응 ("vibe:cozy", "category:운동", -1~1)
+	dismissed     map[uint]bool      // 본인이 관심 없음으로 표시한 항목 (이후 가입하지 않은 것)
+}
+
+// feedbackCounts 항목 또는 속성별 이벤트 수
+type feedbackCounts struct {
+	impressions, clicks, dismissals, joins int
+}
+
+func (c *feedbackCounts) add(event string) {
+	switch event {
+	case models.FeedbackImpression:
+		c.impressions++
+	case models.FeedbackClick:
+		c.clicks++
+	case models.FeedbackDismiss:
+		c.dismissals++
+	case models.FeedbackJoin:
+		c.joins++
 	}
+}
+
+// affinity 반응 점수 (클릭 1, 가입 2, 관심 없음 -2, 무반응 노출 -0.5를 노출 수로 나눔)
+func (c *feedbackCounts) affinity() float64 {
+	reacted := c.clicks + c.joins + c.dismissals
+	shown := math.Max(float64(c.impressions), float64(reacted))
+	ignored := math.Max(0, float64(c.impressions-reacted))
+
+	positive := float64(c.clicks) + 2*float64(c.joins)
+	negative := 2*float64(c.dismissals) + ignoredPenalty*ignored
+	return math.Max(-1, math.Min(1, (positive-negative)/(shown+affinityPrior)))
+}
 
-	if len(similarProfiles) == 0 {
-		return GetRecommendedClubsForSession(sessionID, limit)
+// feedbackEventsQuery 응답자의 이벤트 (회원은 연동 전 비회원 세션 이벤트 포함, asOf가 있으면 그 전까지)
+func feedbackEventsQuery(r Respondent, itemType string, asOf time.Time) *gorm.DB {
+	query := feedbackPeriod(database.DB.Model(&models.RecommendationEvent{}).Where("item_type = ?", itemType), asOf)
+	if r.IsUser() {
+		return query.Where("user_id = ?", r.UserID)
 	}
+	return query.Where("session_id = ?", r.SessionID)
+}
 
-	// 2. 유사한 사용자들의 ID 수집 (회원만)
-	var userIDs []uint
-	for _, profile := range similarProfiles {
-		if profile.UserID != nil {
-			userIDs = append(userIDs, *profile.UserID)
-		}
+// feedbackPeriod 추천에 반영하는 피드백 기간 조건 (asOf가 있으면 그 시각 이전 feedbackWindow, 없으면 현재 기준)
+func feedbackPeriod(query *gorm.DB, asOf time.Time) *gorm.DB {
+	if asOf.IsZero() {
+		return query.Where("created_at >= ?", time.Now().Add(-feedbackWindow))
 	}
+	return query.Where("created_at >= ? AND created_at < ?", asOf.Add(-feedbackWindow), asOf)
+}
 
-	if len(userIDs) == 0 {
-		return GetRecommendedClubsForSession(sessionID, limit)
+// joinedBefore asOf 이전의 클럽 가입만 (joined_at이 없는 예전 행은 created_at 기준)
+func joinedBefore(query *gorm.DB, asOf time.Time) *gorm.DB {
+	if asOf.IsZero() {
+		return query
 	}
+	return query.Where("(CASE WHEN joined_at > '1970-01-01' THEN joined_at ELSE created_at END) < ?", asOf)
+}
 
-	// 3. 클럽별 유사 사용자 수 계산
-	type ClubCount struct {
-		ClubID uint
-		Count  int64
+// loadFeedbackSignals 본인 반응과 유사 응답자 반응 집계
+// attributes는 항목 ID별 속성 키 (본인 반응을 속성 단위로 일반화하는 데 사용)
+// asOf가 있으면 그 전에 기록된 이벤트와 가입만 쓴다 (오프라인 평가에서 정답을 미리 보지 않도록).
+func loadFeedbackSignals(r Respondent, itemType string, asOf time.Time, attributes func(ids []uint) (map[uint][]string, error)) (*feedbackSignals, error) {
+	signals := &feedbackSignals{
+		collaborative: map[uint]float64{},
+		affinity:      map[string]float64{},
+		dismissed:     map[uint]bool{},
 	}
 
-	var clubCounts []ClubCount
-	err = database.DB.Model(&models.ClubMember{}).
-		Select("club_id, COUNT(*) as count").
-		Where("user_id IN ?", userIDs).
-		Group("club_id").
-		Order("count DESC").
-		Limit(limit).
-		Scan(&clubCounts).Error
+	// 1. 본인 반응: 항목별로 모은 뒤 속성별로 합산
+	var own []models.RecommendationEvent
+	if err := feedbackEventsQuery(r, itemType, asOf).Find(&own).Error; err != nil {
+		return nil, err
+	}
+	items := map[uint]*feedbackCounts{}
+	for _, event := range own {
+		if items[event.ItemID] == nil {
+			items[event.ItemID] = &feedbackCounts{}
+		}
+		items[event.ItemID].add(event.Event)
+	}
+	if len(items) > 0 {
+		ids := make([]uint, 0, len(items))
+		for id, counts := range items {
+			ids = append(ids, id)
+			if counts.dismissals > 0 && counts.joins == 0 {
+				signals.dismissed[id] = true
+			}
+		}
+		keysByItem, err := attributes(ids)
+		if err != nil {
+			return nil, err
+		}
+		byKey := map[string]*feedbackCounts{}
+		for id, counts := range items {
+			for _, key := range keysByItem[id] {
+				if byKey[key] == nil {
+					byKey[key] = &feedbackCounts{}
+				}
+				byKey[key].impressions += counts.impressions
+				byKey[key].clicks += counts.clicks
+				byKey[key].dismissals += counts.dismissals
+				byKey[key].joins += counts.joins
+			}
+		}
+		for key, counts := range byKey {
+			signals.affinity[key] = counts.affinity()
+		}
+	}
 
+	// 2. 유사 응답자 반응: 응답자별로 항목에 가장 강한 반응 하나만 (가입 > 관심 없음 > 클릭)
+	neighbors, err := FindSimilarRespondents(r, feedbackNeighbors, SimilarityFilter{ExcludeExpired: true})
 	if err != nil {
 		return nil, err
 	}
+	weights := map[string]float64{}
+	var userIDs []uint
+	var sessionIDs []string
+	totalWeight := 0.0
+	for _, neighbor := range neighbors {
+		weight := neighbor.Similarity / 100
+		if weight <= 0 {
+			continue
+		}
+		totalWeight += weight
+		if neighbor.UserID != nil {
+			weights[UserRespondent(*neighbor.UserID).String()] = weight
+			userIDs = append(userIDs, *neighbor.UserID)
+		} else {
+			weights[GuestRespondent(neighbor.SessionID).String()] = weight
+			sessionIDs = append(sessionIDs, neighbor.SessionID)
+		}
+	}
+	if totalWeight == 0 {
+		return signals, nil
+	}
 
-	// 4. 클럽 정보 가져오기
-	var clubIDs []uint
-	for _, cc := range clubCounts {
-		clubIDs = append(clubIDs, cc.ClubID)
+	type reaction struct {
+		UserID    *uint
+		SessionID string
+		ItemID    uint
+		Event     string
+	}
+	var reactions []reaction
+	query := feedbackPeriod(database.DB.Model(&models.RecommendationEvent{}).
+		Select("user_id, session_id, item_id, event").
+		Where("item_type = ? AND event <> ?", itemType, models.FeedbackImpression), asOf)
+	switch {
+	case len(userIDs) > 0 && len(sessionIDs) > 0:
+		query = query.Where("user_id IN ? OR session_id IN ?", userIDs, sessionIDs)
+	case len(userIDs) > 0:
+		query = query.Where("user_id IN ?", userIDs)
+	default:
+		query = query.Where("session_id IN ?", sessionIDs)
+	}
+	if err := query.Scan(&reactions).Error; err != nil {
+		return nil, err
 	}
 
-	var clubs []models.Club
-	if len(clubIDs) > 0 {
-		err = database.DB.Where("id IN ?", clubIDs).Find(&clubs).Error
-		if err != nil {
+	// 클럽은 실제 가입도 가입 반응으로 본다
+	if itemType == models.RecommendationItemClub && len(userIDs) > 0 {
+		var members []models.ClubMember
+		if err := joinedBefore(database.DB.Select("user_id, club_id").Where("user_id IN ?", userIDs), asOf).Find(&members).Error; err != nil {
 			return nil, err
 		}
+		for _, member := range members {
+			userID := member.UserID
+			reactions = append(reactions, reaction{UserID: &userID, ItemID: member.ClubID, Event: models.FeedbackJoin})
+		}
+	}
 
-		// 원래 순서대로 정렬 (count 높은 순)
-		clubMap := make(map[uint]models.Club)
-		for _, club := range clubs {
-			clubMap[club.ID] = club
+	strongest := map[string]map[uint]float64{}
+	for _, reaction := range reactions {
+		key := GuestRespondent(reaction.SessionID).String()
+		if reaction.UserID != nil {
+			key = UserRespondent(*reaction.UserID).String()
+		}
+		if _, ok := weights[key]; !ok {
+			continue
+		}
+		signal := neighborSignal[reaction.Event]
+		if strongest[key] == nil {
+			strongest[key] = map[uint]float64{}
+		}
+		if current, ok := strongest[key][reaction.ItemID]; !ok || math.Abs(signal) > math.Abs(current) {
+			strongest[key][reaction.ItemID] = signal
 		}
+	}
+	for key, byItem := range strongest {
+		for itemID, signal := range byItem {
+			signals.collaborative[itemID] += weights[key] * signal / totalWeight
+		}
+	}
+	return signals, nil
+}
 
-		sortedClubs := make([]models.Club, 0, len(clubIDs))
-		for _, id := range clubIDs {
-			if club, ok := clubMap[id]; ok {
-				sortedClubs = append(sortedClubs, club)
-			}
+// adjust 기본 점수에 피드백 반영 (반환: 반영한 점수, 협업 신호, 본인 반응)
+func (s *feedbackSignals) adjust(itemID uint, base float64, keys []string) (score, collaborative, personal float64) {
+	collaborative = s.collaborative[itemID]
+
+	known := 0
+	for _, key := range keys {
+		if affinity, ok := s.affinity[key]; ok {
+			personal += affinity
+			known++
 		}
-		clubs = sortedClubs
+	}
+	if known > 0 {
+		personal /= float64(known)
 	}
 
-	return clubs, nil
+	score = base + collaborativeWeight*collaborative + personalWeight*personal
+	score = math.Max(0, math.Min(100, score))
+	return math.Round(score*10) / 10, math.Round(collaborative*1000) / 1000, math.Round(personal*1000) / 1000
 }
 
-// GetRecommendedMeetingsForSession - 세션 기반 모임 추천
-func GetRecommendedMeetingsForSession(sessionID string, limit int) ([]models.Meeting, error) {
-	var sessionVector models.SessionVector
-	err := database.DB.Where("session_id = ?", sessionID).First(&sessionVector).Error
-	if err != nil {
-		return nil, err
+// clubFeedbackKeys 클럽 속성 키 (분위기, 카테고리)
+func clubFeedbackKeys(club *models.Club) []string {
+	var keys []string
+	if club.Vibe != "" {
+		keys = append(keys, "vibe:"+strings.ToLower(club.Vibe))
 	}
-
-	v := utils.FromSlice(sessionVector.Vector)
-	if v == nil {
-		return []models.Meeting{}, nil
+	if club.Category != "" {
+		keys = append(keys, "category:"+club.Category)
 	}
+	return keys
+}
 
-	var meetings []models.Meeting
-	query := database.DB.Preload("Club")
-
-	// 활동성이 높은 사람에게는 다양한 모임 추천
-	if v.Activity >= 70 {
-		query = query.Order("scheduled_at ASC")
-	} else if v.Intimacy >= 60 {
-		// 친밀도가 높은 사람에게는 소규모 모임
-		query = query.Where("max_members <= ?", 20).Order("max_members ASC")
-	} else {
-		query = query.Order("created_at DESC")
+// meetingFeedbackKeys 모임 속성 키 (카테고리, 여는 클럽의 분위기)
+func meetingFeedbackKeys(meeting *models.Meeting) []string {
+	var keys []string
+	if meeting.Category != "" {
+		keys = append(keys, "category:"+meeting.Category)
+	}
+	if meeting.Club.Vibe != "" {
+		keys = append(keys, "vibe:"+strings.ToLower(meeting.Club.Vibe))
 	}
+	return keys
+}
 
-	err = query.Limit(limit).Find(&meetings).Error
+// blendClubFeedback 클럽 추천 순위에 피드백 반영
+// 관심 없음으로 표시한 클럽은 빼고, 성향 적합도에 유사 응답자 반응과 본인의 분위기/카테고리 반응을 더해 다시 정렬한다.
+func blendClubFeedback(r Respondent, matches []ClubMatch, asOf time.Time) ([]ClubMatch, error) {
+	signals, err := loadFeedbackSignals(r, models.RecommendationItemClub, asOf, func(ids []uint) (map[uint][]string, error) {
+		var clubs []models.Club
+		if err := database.DB.Select("id, vibe, category").Where("id IN ?", ids).Find(&clubs).Error; err != nil {
+			return nil, err
+		}
+		keys := make(map[uint][]string, len(clubs))
+		for i := range clubs {
+			keys[clubs[i].ID] = clubFeedbackKeys(&clubs[i])
+		}
+		return keys, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return meetings, nil
-}
+	blended := make([]ClubMatch, 0, len(matches))
+	for _, match := range matches {
+		if signals.dismissed[match.Club.ID] {
+			continue
+		}
+		match.ContentScore = match.Score
+		match.Score, match.CollaborativeScore, match.FeedbackScore = signals.adjust(match.Club.ID, match.Score, clubFeedbackKeys(&match.Club))
+		blended = append(blended, match)
+	}
 
-// CalculateProfileCompatibility - 두 프로필 간 궁합 점수 계산
-func CalculateProfileCompatibility(v1, v2 *utils.Vector5D) map[string]interface{} {
-	similarity := utils.SimilarityScore(v1, v2)
+	sort.SliceStable(blended, func(i, j int) bool {
+		a, b := blended[i], blended[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.HasTarget != b.HasTarget {
+			return a.HasTarget
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Club.ID < b.Club.ID
+	})
+	return blended, nil
+}
 
-	// 차원별 궁합 분석
-	compatibility := map[string]interface{}{
-		"overall_score": similarity,
-		"details": map[string]interface{}{
-			"sociality_match":   100 - math.Abs(v1.Sociality-v2.Sociality),
-			"activity_match":    100 - math.Abs(v1.Activity-v2.Activity),
-			"intimacy_match":    100 - math.Abs(v1.Intimacy-v2.Intimacy),
-			"immersion_match":   100 - math.Abs(v1.Immersion-v2.Immersion),
-			"flexibility_match": 100 - math.Abs(v1.Flexibility-v2.Flexibility),
-		},
+// blendMeetingFeedback 모임 추천 순위에 피드백 반영 (기준 순서를 0-100점으로 바꾼 뒤 반영)
+func blendMeetingFeedback(r Respondent, meetings []models.Meeting, limit int) ([]models.Meeting, error) {
+	signals, err := loadFeedbackSignals(r, models.RecommendationItemMeeting, time.Time{}, func(ids []uint) (map[uint][]string, error) {
+		var found []models.Meeting
+		if err := database.DB.Preload("Club").Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return nil, err
+		}
+		keys := make(map[uint][]string, len(found))
+		for i := range found {
+			keys[found[i].ID] = meetingFeedbackKeys(&found[i])
+		}
+		return keys, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 궁합 평가
-	if similarity >= 80 {
-		compatibility["rating"] = "최고의 궁합"
-		compatibility["description"] = "매우 비슷한 성향으로 서로 잘 맞을 것입니다"
-	} else if similarity >= 70 {
-		compatibility["rating"] = "좋은 궁합"
-		compatibility["description"] = "비슷한 성향으로 편안한 관계를 형성할 수 있습니다"
-	} else if similarity >= 60 {
-		compatibility["rating"] = "보통 궁합"
-		compatibility["description"] = "서로 다른 점이 있지만 조화롭게 지낼 수 있습니다"
-	} else if similarity >= 50 {
-		compatibility["rating"] = "상호보완적"
-		compatibility["description"] = "다른 성향으로 서로에게 새로운 자극이 될 수 있습니다"
-	} else {
-		compatibility["rating"] = "흥미로운 조합"
-		compatibility["description"] = "매우 다른 성향이지만 배울 점이 많을 것입니다"
+	type scored struct {
+		meeting models.Meeting
+		score   float64
 	}
+	candidates := make([]scored, 0, len(meetings))
+	for i := range meetings {
+		if signals.dismissed[meetings[i].ID] {
+			continue
+		}
+		base := 100 * (1 - float64(i)/float64(len(meetings)))
+		score, _, _ := signals.adjust(meetings[i].ID, base, meetingFeedbackKeys(&meetings[i]))
+		candidates = append(candidates, scored{meeting: meetings[i], score: score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
 
-	return compatibility
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	blended := make([]models.Meeting, len(candidates))
+	for i := range candidates {
+		blended[i] = candidates[i].meeting
+	}
+	return blended, nil
 }
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// 응답자 종류
+const (
+	RespondentUser  = "user"  // 회원
+	RespondentGuest = "guest" // 비회원 세션
+)
+
+// Respondent 설문 응답자 (회원 또는 비회원 세션)
+// 점수 계산과 추천은 응답자 종류와 무관하게 같은 엔진을 사용한다.
+type Respondent struct {
+	Kind      string
+	UserID    uint
+	SessionID string
+}
+
+// UserRespondent 회원 응답자
+func UserRespondent(userID uint) Respondent {
+	return Respondent{Kind: RespondentUser, UserID: userID}
+}
+
+// GuestRespondent 비회원 세션 응답자
+func GuestRespondent(sessionID string) Respondent {
+	return Respondent{Kind: RespondentGuest, SessionID: sessionID}
+}
+
+// IsUser 회원 응답자인지 여부
+func (r Respondent) IsUser() bool {
+	return r.Kind == RespondentUser
+}
+
+func (r Respondent) String() string {
+	if r.IsUser() {
+		return fmt.Sprintf("user:%d", r.UserID)
+	}
+	return "guest:" + r.SessionID
+}
+
+// Vector 점수를 벡터로 변환
+func (s *ScoreResult) Vector() *utils.Vector5D {
+	return &utils.Vector5D{
+		Sociality:   s.SocialityScore,
+		Activity:    s.ActivityScore,
+		Intimacy:    s.IntimacyScore,
+		Immersion:   s.ImmersionScore,
+		Flexibility: s.FlexibilityScore,
+	}
+}
+
+// ByDimension 차원 이름별 점수
+func (s *ScoreResult) ByDimension() map[string]float64 {
+	return map[string]float64{
+		models.DimensionSociality:   s.SocialityScore,
+		models.DimensionActivity:    s.ActivityScore,
+		models.DimensionIntimacy:    s.IntimacyScore,
+		models.DimensionImmersion:   s.ImmersionScore,
+		models.DimensionFlexibility: s.FlexibilityScore,
+	}
+}
+
+// profileVector 회원 프로필 점수를 벡터로 변환
+func profileVector(profile *models.UserProfile) *utils.Vector5D {
+	return &utils.Vector5D{
+		Sociality:   profile.SocialityScore,
+		Activity:    profile.ActivityScore,
+		Intimacy:    profile.IntimacyScore,
+		Immersion:   profile.ImmersionScore,
+		Flexibility: profile.FlexibilityScore,
+	}
+}
+
+// vectorByDimension 벡터를 차원 이름별 점수로 변환
+func vectorByDimension(v *utils.Vector5D) map[string]float64 {
+	return map[string]float64{
+		models.DimensionSociality:   v.Sociality,
+		models.DimensionActivity:    v.Activity,
+		models.DimensionIntimacy:    v.Intimacy,
+		models.DimensionImmersion:   v.Immersion,
+		models.DimensionFlexibility: v.Flexibility,
+	}
+}
+
+// RespondentQuestionnaireID 응답자가 응답한 설문 버전 (회원은 가장 최근에 응답한 버전)
+func RespondentQuestionnaireID(r Respondent) (uint, error) {
+	if r.IsUser() {
+		var answer models.UserAnswer
+		err := database.DB.Where("user_id = ?", r.UserID).
+			Order("created_at DESC, id DESC").
+			First(&answer).Error
+		if err != nil {
+			return 0, fmt.Errorf("no answers found for user")
+		}
+		return answer.QuestionnaireID, nil
+	}
+
+	var answer models.GuestAnswer
+	if err := database.DB.Where("session_id = ?", r.SessionID).First(&answer).Error; err != nil {
+		return 0, fmt.Errorf("no answers found for session")
+	}
+	return answer.QuestionnaireID, nil
+}
+
+// CalculateRespondentScores 응답자의 답변으로 차원별 점수 계산
+func CalculateRespondentScores(r Respondent) (*ScoreResult, error) {
+	questionnaireID, answered, err := loadAnsweredOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(answered) == 0 {
+		return nil, fmt.Errorf("no answers found for %s", r.Kind)
+	}
+
+	var questionnaire models.Questionnaire
+	if err := database.DB.Select("id", "scoring_method").First(&questionnaire, questionnaireID).Error; err != nil {
+		return nil, err
+	}
+
+	return scoreAnswers(questionnaire.ScoringMethod, answered), nil
+}
+
+// RespondentVector 응답자의 저장된 성향 벡터 (회원은 프로필, 비회원은 세션 벡터)
+func RespondentVector(r Respondent) (*utils.Vector5D, error) {
+	if r.IsUser() {
+		var profile models.UserProfile
+		if err := database.DB.Where("user_id = ?", r.UserID).First(&profile).Error; err != nil {
+			return nil, err
+		}
+		return profileVector(&profile), nil
+	}
+
+	var sessionVector models.SessionVector
+	if err := database.DB.Where("session_id = ?", r.SessionID).First(&sessionVector).Error; err != nil {
+		return nil, err
+	}
+	v := utils.FromSlice(sessionVector.Vector)
+	if v == nil {
+		return nil, fmt.Errorf("invalid vector for session %s", r.SessionID)
+	}
+	return v, nil
+}
+
+// loadAnsweredOptions 응답한 설문 버전과 답변을 (선택 옵션, 질문의 전체 옵션)으로 조회
+// 회원은 다른 버전의 응답이 섞이지 않도록 가장 최근에 응답한 버전만 사용한다.
+func loadAnsweredOptions(r Respondent) (uint, []answeredOption, error) {
+	questionnaireID, err := RespondentQuestionnaireID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if r.IsUser() {
+		var answers []models.UserAnswer
+		if err := database.DB.Preload("Option").Preload("Question.Options").
+			Where("user_id = ? AND questionnaire_id = ?", r.UserID, questionnaireID).
+			Find(&answers).Error; err != nil {
+			return 0, nil, err
+		}
+
+		answered := make([]answeredOption, len(answers))
+		for i, answer := range answers {
+			answered[i] = answeredOption{Chosen: answer.Option, Options: answer.Question.Options}
+		}
+		return questionnaireID, answered, nil
+	}
+
+	var answers []models.GuestAnswer
+	if err := database.DB.Preload("Option").Preload("Question.Options").
+		Where("session_id = ? AND questionnaire_id = ?", r.SessionID, questionnaireID).
+		Find(&answers).Error; err != nil {
+		return 0, nil, err
+	}
+
+	answered := make([]answeredOption, len(answers))
+	for i, answer := range answers {
+		answered[i] = answeredOption{Chosen: answer.Option, Options: answer.Question.Options}
+	}
+	return questionnaireID, answered, nil
+}
+
+// linkedUserID 비회원 세션이 연동된 회원 ID (자기 자신을 유사 프로필에서 제외하기 위해 사용)
+func linkedUserID(r Respondent) uint {
+	if r.IsUser() {
+		return r.UserID
+	}
+
+	var session models.GuestSession
+	if err := database.DB.Select("linked_user_id").Where("id = ?", r.SessionID).First(&session).Error; err != nil || session.LinkedUserID == nil {
+		return 0
+	}
+	return *session.LinkedUserID
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"math"
+	"ongi-back/models"
+)
+
+// answeredOption 응답 하나 (선택한 옵션과 해당 질문의 전체 옵션)
+type answeredOption struct {
+	Chosen  models.Option
+	Options []models.Option
+}
+
+// scoreAnswers 설문 버전의 점수 계산 방식으로 차원별 점수 계산
+func scoreAnswers(method string, answers []answeredOption) *ScoreResult {
+	if method == models.ScoringMethodAverage {
+		return calculateAverageScores(answers)
+	}
+	return calculateWeightedScores(answers)
+}
+
+// calculateAverageScores 차원별로 선택한 옵션 가중치(1-5)의 평균을 0-100으로 변환
+// 단일 카테고리 점수에서 변환된 설문 버전용으로, 변환 전과 같은 점수를 낸다 (해당 차원 옵션을 고르지 않았으면 0점).
+func calculateAverageScores(answers []answeredOption) *ScoreResult {
+	sums := make([]float64, len(models.Dimensions))
+	counts := make([]int, len(models.Dimensions))
+
+	for _, answer := range answers {
+		for i, dimension := range models.Dimensions {
+			if weight, ok := answer.Chosen.Weights[dimension]; ok && weight != 0 {
+				sums[i] += weight
+				counts[i]++
+			}
+		}
+	}
+
+	averaged := make([]float64, len(models.Dimensions))
+	for i := range models.Dimensions {
+		if counts[i] == 0 {
+			continue
+		}
+		averaged[i] = math.Round(sums[i]/float64(counts[i])/5*100*10) / 10
+	}
+
+	return &ScoreResult{
+		SocialityScore:   averaged[0],
+		ActivityScore:    averaged[1],
+		IntimacyScore:    averaged[2],
+		ImmersionScore:   averaged[3],
+		FlexibilityScore: averaged[4],
+	}
+}
+
+// calculateWeightedScores 선택한 옵션의 가중치 벡터로 차원별 점수(0-100) 계산
+// 차원마다 응답한 질문들에서 얻을 수 있는 최소/최대 가중치 합을 기준으로 정규화한다.
+// 응답한 질문 중 해당 차원에 영향을 주는 옵션이 없으면 0점이다.
+func calculateWeightedScores(answers []answeredOption) *ScoreResult {
+	earned := make([]float64, len(models.Dimensions))
+	lowest := make([]float64, len(models.Dimensions))
+	highest := make([]float64, len(models.Dimensions))
+
+	for _, answer := range answers {
+		for i, dimension := range models.Dimensions {
+			minWeight, maxWeight := optionWeightRange(answer.Options, dimension)
+			earned[i] += answer.Chosen.Weights[dimension]
+			lowest[i] += minWeight
+			highest[i] += maxWeight
+		}
+	}
+
+	normalized := make([]float64, len(models.Dimensions))
+	for i := range models.Dimensions {
+		span := highest[i] - lowest[i]
+		if span == 0 {
+			continue
+		}
+		normalized[i] = math.Round((earned[i]-lowest[i])/span*100*10) / 10
+	}
+
+	return &ScoreResult{
+		SocialityScore:   normalized[0],
+		ActivityScore:    normalized[1],
+		IntimacyScore:    normalized[2],
+		ImmersionScore:   normalized[3],
+		FlexibilityScore: normalized[4],
+	}
+}
+
+// optionWeightRange 질문의 옵션들이 해당 차원에 주는 최소/최대 가중치
+func optionWeightRange(options []models.Option, dimension string) (float64, float64) {
+	if len(options) == 0 {
+		return 0, 0
+	}
+
+	minWeight := math.Inf(1)
+	maxWeight := math.Inf(-1)
+	for _, option := range options {
+		weight := option.Weights[dimension]
+		minWeight = math.Min(minWeight, weight)
+		maxWeight = math.Max(maxWeight, weight)
+	}
+	return minWeight, maxWeight
+}
@@ -58,64 +58,50 @@ func GetGuestSession(sessionID string) (*models.GuestSession, error) {
 	return &session, nil
 }
 
-// SubmitGuestAnswers - 비회원 답변 제출
-func SubmitGuestAnswers(sessionID string, answers []models.AnswerPayload) error {
-	// 기존 답변 삭제
-	database.DB.Where("session_id = ?", sessionID).Delete(&models.GuestAnswer{})
-
-	// 새 답변 저장
-	for _, ans := range answers {
-		answer := models.GuestAnswer{
-			SessionID:  sessionID,
-			QuestionID: ans.QuestionID,
-			OptionID:   ans.OptionID,
-		}
-
-		if err := database.DB.Create(&answer).Error; err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// CalculateGuestScores - 비회원 세션 점수 계산
-func CalculateGuestScores(sessionID string) (*ScoreResult, error) {
-	var answers []models.GuestAnswer
-
-	err := database.DB.Preload("Option").
-		Where("session_id = ?", sessionID).
-		Find(&answers).Error
-
+// SubmitGuestAnswers - 비회원 답변 제출 (questionnaireID가 0이면 현재 발행된 버전)
+func SubmitGuestAnswers(sessionID string, questionnaireID uint, answers []models.AnswerPayload) (*models.Questionnaire, error) {
+	questionnaire, err := ResolveAnswerableQuestionnaire(questionnaireID)
 	if err != nil {
 		return nil, err
 	}
-
-	if len(answers) == 0 {
-		return nil, fmt.Errorf("no answers found for session")
+	if err := ValidateAnswers(questionnaire.ID, answers); err != nil {
+		return nil, err
 	}
 
-	scores := &ScoreResult{}
-	categoryScores := make(map[string][]int)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 기존 답변 삭제
+		if err := tx.Where("session_id = ?", sessionID).Delete(&models.GuestAnswer{}).Error; err != nil {
+			return err
+		}
 
-	// 각 답변의 점수를 카테고리별로 분류
-	for _, answer := range answers {
-		weight := answer.Option.Weight
-		score := answer.Option.Score
+		// 새 답변 저장
+		for _, ans := range answers {
+			answer := models.GuestAnswer{
+				SessionID:       sessionID,
+				QuestionnaireID: questionnaire.ID,
+				QuestionID:      ans.QuestionID,
+				OptionID:        ans.OptionID,
+			}
 
-		if weight != "" {
-			categoryScores[weight] = append(categoryScores[weight], score)
+			if err := tx.Create(&answer).Error; err != nil {
+				return err
+			}
 		}
-	}
 
-	// 카테고리별 평균 계산
-	scores.SocialityScore = calculateAverage(categoryScores["sociality"])
-	scores.ActivityScore = calculateAverage(categoryScores["activity"])
-	scores.IntimacyScore = calculateAverage(categoryScores["intimacy"])
-	scores.ImmersionScore = calculateAverage(categoryScores["immersion"])
-	scores.FlexibilityScore = calculateAverage(categoryScores["flexibility"])
+		// 응답한 버전을 기록하고, 이전 결과는 다시 계산하도록 비움
+		return tx.Model(&models.GuestSession{}).
+			Where("id = ?", sessionID).
+			Updates(map[string]interface{}{
+				"questionnaire_id": questionnaire.ID,
+				"profile_type":     "",
+				"result_summary":   "",
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return scores, nil
+	return questionnaire, nil
 }
 
 // SaveGuestResult - 비회원 세션 결과 저장
@@ -157,19 +143,37 @@ func CreateSessionVector(sessionID string, userID *uint, scores *ScoreResult) er
 	var existing models.SessionVector
 	result := database.DB.Where("session_id = ?", sessionID).First(&existing)
 
+	var err error
 	if result.Error == nil {
 		// 업데이트
-		return database.DB.Model(&existing).Updates(sessionVector).Error
+		err = database.DB.Model(&existing).Updates(sessionVector).Error
 	} else {
 		// 생성
-		return database.DB.Create(&sessionVector).Error
+		err = database.DB.Create(&sessionVector).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	// 벡터 인덱스 동기화 (회원과 연결된 세션은 회원 프로필로 검색됨)
+	if userID != nil {
+		GlobalVectorIndex.Remove(GuestRespondent(sessionID))
+		return nil
+	}
+	var session models.GuestSession
+	if err := database.DB.Select("expires_at").Where("id = ?", sessionID).First(&session).Error; err != nil {
+		return err
 	}
+	GlobalVectorIndex.Upsert(GuestRespondent(sessionID), v, &session.ExpiresAt)
+	return nil
 }
 
 // LinkSessionToUser - 세션을 사용자 계정과 연동
 func LinkSessionToUser(sessionID string, userID uint) error {
+	var profile models.UserProfile
+
 	// 트랜잭션으로 처리
-	return database.DB.Transaction(func(tx *gorm.DB) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
 		// 1. 세션을 사용자와 연결
 		err := tx.Model(&models.GuestSession{}).
 			Where("id = ?", sessionID).
@@ -194,9 +198,10 @@ func LinkSessionToUser(sessionID string, userID uint) error {
 		// 새 답변 생성
 		for _, ga := range guestAnswers {
 			userAnswer := models.UserAnswer{
-				UserID:     userID,
-				QuestionID: ga.QuestionID,
-				OptionID:   ga.OptionID,
+				UserID:          userID,
+				QuestionnaireID: ga.QuestionnaireID,
+				QuestionID:      ga.QuestionID,
+				OptionID:        ga.OptionID,
 			}
 			if err := tx.Create(&userAnswer).Error; err != nil {
 				return err
@@ -210,7 +215,7 @@ func LinkSessionToUser(sessionID string, userID uint) error {
 			return err
 		}
 
-		profile := models.UserProfile{
+		profile = models.UserProfile{
 			UserID:           userID,
 			SocialityScore:   session.SocialityScore,
 			ActivityScore:    session.ActivityScore,
@@ -219,6 +224,7 @@ func LinkSessionToUser(sessionID string, userID uint) error {
 			FlexibilityScore: session.FlexibilityScore,
 			ProfileType:      session.ProfileType,
 			ResultSummary:    session.ResultSummary,
+			QuestionnaireID:  session.QuestionnaireID,
 		}
 
 		// UserProfile upsert
@@ -236,10 +242,37 @@ func LinkSessionToUser(sessionID string, userID uint) error {
 		}
 
 		// 4. SessionVector 업데이트
-		return tx.Model(&models.SessionVector{}).
+		err = tx.Model(&models.SessionVector{}).
+			Where("session_id = ?", sessionID).
+			Update("user_id", userID).Error
+		if err != nil {
+			return err
+		}
+
+		// 5. 비회원일 때 남긴 추천 피드백과 실험 노출을 회원 기록으로
+		err = tx.Model(&models.RecommendationEvent{}).
+			Where("session_id = ?", sessionID).
+			Update("user_id", userID).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.ExperimentExposure{}).
 			Where("session_id = ?", sessionID).
 			Update("user_id", userID).Error
 	})
+	if err != nil {
+		return err
+	}
+
+	// 6. 벡터 인덱스 동기화 (비회원 세션 -> 회원 프로필)
+	GlobalVectorIndex.Remove(GuestRespondent(sessionID))
+	IndexUserProfile(&profile)
+	return nil
+}
+
+// IndexUserProfile 회원 프로필 저장 후 벡터 인덱스 동기화
+func IndexUserProfile(profile *models.UserProfile) {
+	GlobalVectorIndex.Upsert(UserRespondent(profile.UserID), profileVector(profile), nil)
 }
 
 // CleanExpiredSessions - 만료된 세션 정리
@@ -273,7 +306,13 @@ func CleanExpiredSessions() error {
 	}
 
 	// 만료된 세션 삭제 (연동되지 않은 것만)
-	return database.DB.
+	err = database.DB.
 		Where("expires_at < ? AND is_linked = false", now).
 		Delete(&models.GuestSession{}).Error
+	if err != nil {
+		return err
+	}
+
+	GlobalVectorIndex.RemoveExpiredGuests(now)
+	return nil
 }
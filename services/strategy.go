@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"ongi-back/config"
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// 클럽 추천 전략 이름
+const (
+	StrategyContent        = "content"         // 성향 적합도만
+	StrategyFeedback       = "feedback"        // 성향 적합도 + 추천 피드백 (기본)
+	StrategySimilarMembers = "similar_members" // 유사한 회원이 많이 가입한 클럽 우선
+)
+
+// ErrUnknownStrategy 등록되지 않은 추천 전략
+var ErrUnknownStrategy = errors.New("unknown recommendation strategy")
+
+// ClubStrategy 클럽 추천 전략
+// 후보 클럽(조건으로 거른 목록)을 응답자에게 맞는 순서로 정렬한다. 제외할 클럽은 결과에서 빼도 된다.
+type ClubStrategy interface {
+	Name() string
+	Description() string
+	Rank(r Respondent, v *utils.Vector5D, clubs []models.Club, filter ClubFilter) ([]ClubMatch, error)
+}
+
+// clubStrategies 등록된 클럽 추천 전략
+var clubStrategies = map[string]ClubStrategy{
+	StrategyContent:        contentStrategy{},
+	StrategyFeedback:       feedbackStrategy{},
+	StrategySimilarMembers: similarMembersStrategy{},
+}
+
+// ClubStrategies 등록된 클럽 추천 전략 (이름순)
+func ClubStrategies() []ClubStrategy {
+	strategies := make([]ClubStrategy, 0, len(clubStrategies))
+	for _, strategy := range clubStrategies {
+		strategies = append(strategies, strategy)
+	}
+	sort.Slice(strategies, func(i, j int) bool { return strategies[i].Name() < strategies[j].Name() })
+	return strategies
+}
+
+// ResolveClubStrategy 이름으로 클럽 추천 전략 조회
+func ResolveClubStrategy(name string) (ClubStrategy, error) {
+	strategy, ok := clubStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, name)
+	}
+	return strategy, nil
+}
+
+// DefaultClubStrategy 실험에 배정되지 않은 응답자의 클럽 추천 전략 (RECOMMENDATION_STRATEGY)
+func DefaultClubStrategy() (ClubStrategy, error) {
+	return ResolveClubStrategy(config.AppConfig.RecommendationStrategy)
+}
+
+// contentStrategy 클럽 선호 성향과의 거리만으로 정렬
+type contentStrategy struct{}
+
+func (contentStrategy) Name() string { return StrategyContent }
+
+func (contentStrategy) Description() string {
+	return "Distance between the respondent and each club's preferred scores"
+}
+
+func (contentStrategy) Rank(r Respondent, v *utils.Vector5D, clubs []models.Club, filter ClubFilter) ([]ClubMatch, error) {
+	matches := RankClubs(v, clubs, filter)
+	for i := range matches {
+		matches[i].ContentScore = matches[i].Score
+	}
+	return matches, nil
+}
+
+// feedbackStrategy 성향 적합도에 유사 응답자 반응과 본인 피드백을 반영
+type feedbackStrategy struct{}
+
+func (feedbackStrategy) Name() string { return StrategyFeedback }
+
+func (feedbackStrategy) Description() string {
+	return "Preferred-score fit blended with similar respondents' and the respondent's own feedback; dismissed clubs are removed"
+}
+
+func (feedbackStrategy) Rank(r Respondent, v *utils.Vector5D, clubs []models.Club, filter ClubFilter) ([]ClubMatch, error) {
+	return blendClubFeedback(r, RankClubs(v, clubs, filter), filter.AsOf)
+}
+
+// similarMembersStrategy 유사한 회원이 많이 가입한 클럽을 앞에 두고, 같으면 성향 적합도 순
+type similarMembersStrategy struct{}
+
+func (similarMembersStrategy) Name() string { return StrategySimilarMembers }
+
+func (similarMembersStrategy) Description() string {
+	return "Clubs joined by the most similar members first, then preferred-score fit"
+}
+
+func (similarMembersStrategy) Rank(r Respondent, v *utils.Vector5D, clubs []models.Club, filter ClubFilter) ([]ClubMatch, error) {
+	matches := RankClubs(v, clubs, filter)
+	for i := range matches {
+		matches[i].ContentScore = matches[i].Score
+	}
+	if len(matches) == 0 {
+		return matches, nil
+	}
+
+	neighbors, err := FindSimilarRespondents(r, 20, SimilarityFilter{MembersOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []uint
+	for _, profile := range neighbors {
+		if profile.UserID != nil {
+			userIDs = append(userIDs, *profile.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return matches, nil
+	}
+
+	clubIDs := make([]uint, len(matches))
+	for i, match := range matches {
+		clubIDs[i] = match.Club.ID
+	}
+	var counts []struct {
+		ClubID uint
+		Count  int64
+	}
+	if err := joinedBefore(database.DB.Model(&models.ClubMember{}).
+		Select("club_id, COUNT(*) as count").
+		Where("user_id IN ? AND club_id IN ?", userIDs, clubIDs), filter.AsOf).
+		Group("club_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	members := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		members[count.ClubID] = count.Count
+	}
+	for i := range matches {
+		matches[i].CollaborativeScore = float64(members[matches[i].Club.ID]) / float64(len(userIDs))
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return members[matches[i].Club.ID] > members[matches[j].Club.ID]
+	})
+	return matches, nil
+}
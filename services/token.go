@@ -0,0 +1,280 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrUserBanned          = errors.New("user is banned")
+	ErrUserNotFound        = errors.New("user not found")
+)
+
+// TokenPair 액세스 토큰 + 리프레시 토큰
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"` // 액세스 토큰 유효 기간 (초)
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// IssueTokenPair 로그인 시 새 토큰 패밀리로 토큰 쌍 발급
+func IssueTokenPair(user *models.User, userAgent string) (*TokenPair, error) {
+	if user.IsBanned {
+		return nil, ErrUserBanned
+	}
+
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	return issueTokenPair(database.DB, user, familyID, userAgent)
+}
+
+// RotateRefreshToken 리프레시 토큰을 사용해 새 토큰 쌍 발급 (기존 토큰은 사용 처리)
+// 이미 사용된 토큰이 다시 제출되면 탈취로 간주하고 해당 패밀리 전체를 폐기한다.
+func RotateRefreshToken(rawToken string, userAgent string) (*TokenPair, error) {
+	var pair *TokenPair
+	var reused *models.RefreshToken
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashToken(rawToken)).
+			First(&stored).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		if stored.UsedAt != nil {
+			reused = &stored
+			return ErrRefreshTokenReused
+		}
+
+		var user models.User
+		if err := tx.First(&user, stored.UserID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+		if user.IsBanned {
+			return ErrUserBanned
+		}
+
+		now := time.Now()
+		if err := tx.Model(&stored).Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		newPair, err := issueTokenPair(tx, &user, stored.FamilyID, userAgent)
+		if err != nil {
+			return err
+		}
+		pair = newPair
+		return nil
+	})
+
+	// 재사용 감지 시 트랜잭션 밖에서 패밀리 폐기 (롤백되지 않도록)
+	if reused != nil {
+		log.Printf("Refresh token reuse detected: userID=%d, familyID=%s", reused.UserID, reused.FamilyID)
+		if revokeErr := revokeRefreshFamily(reused.FamilyID); revokeErr != nil {
+			log.Printf("Failed to revoke refresh token family: %v", revokeErr)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// RevokeRefreshToken 리프레시 토큰이 속한 패밀리 전체 폐기 (로그아웃)
+func RevokeRefreshToken(rawToken string, userID uint) error {
+	var stored models.RefreshToken
+	if err := database.DB.Where("token_hash = ? AND user_id = ?", hashToken(rawToken), userID).
+		First(&stored).Error; err != nil {
+		return ErrInvalidRefreshToken
+	}
+
+	return revokeRefreshFamily(stored.FamilyID)
+}
+
+// RevokeAccessToken 단일 액세스 토큰 폐기 (만료 시간까지 유지)
+func RevokeAccessToken(claims *utils.Claims, reason string) error {
+	if claims == nil || claims.ID == "" {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(utils.AccessTokenTTL())
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	return database.DB.Create(&models.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+	}).Error
+}
+
+// RevokeAllUserTokens 사용자의 모든 액세스/리프레시 토큰 폐기
+func RevokeAllUserTokens(userID uint, reason string) error {
+	// JWT iat는 초 단위이므로 기준 시간도 초 단위로 맞춘다 (같은 초에 발급된 토큰도 폐기, 재로그인은 다음 초부터 유효)
+	now := time.Now().Truncate(time.Second)
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+
+		// 현재 시점 이전에 발급된 액세스 토큰은 최대 유효 기간 동안 거부
+		return tx.Create(&models.RevokedToken{
+			UserID:       userID,
+			RevokeBefore: &now,
+			Reason:       reason,
+			ExpiresAt:    now.Add(utils.AccessTokenTTL()),
+		}).Error
+	})
+}
+
+// BanUser 계정 정지 및 모든 토큰 즉시 폐기
+// 정지된 사용자의 토큰은 폐기 목록과 관계없이 IsRevoked에서 거부된다.
+func BanUser(userID uint) error {
+	if err := setUserBanned(userID, true); err != nil {
+		return err
+	}
+
+	return RevokeAllUserTokens(userID, "banned")
+}
+
+// UnbanUser 계정 정지 해제 (정지 때 폐기한 토큰은 되살리지 않으므로 다시 로그인해야 한다)
+func UnbanUser(userID uint) error {
+	return setUserBanned(userID, false)
+}
+
+func setUserBanned(userID uint, banned bool) error {
+	result := database.DB.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("is_banned", banned)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// CleanExpiredTokens 만료된 리프레시 토큰 및 폐기 목록 정리
+func CleanExpiredTokens() error {
+	now := time.Now()
+
+	if err := database.DB.
+		Where("expires_at < ?", now).
+		Delete(&models.RefreshToken{}).Error; err != nil {
+		return err
+	}
+
+	return database.DB.
+		Where("expires_at < ?", now).
+		Delete(&models.RevokedToken{}).Error
+}
+
+// dbRevocationChecker revoked_tokens 테이블과 계정 정지 여부 기반 폐기 확인
+// users.is_banned를 직접 읽으므로 관리 도구나 SQL로 정지해도 즉시 적용된다.
+type dbRevocationChecker struct{}
+
+func (dbRevocationChecker) IsRevoked(claims *utils.Claims) (bool, error) {
+	issuedAt := time.Time{}
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	var count int64
+	err := database.DB.Model(&models.RevokedToken{}).
+		Where("(jti = ? AND jti <> '') OR (user_id = ? AND revoke_before >= ?)", claims.ID, claims.UserID, issuedAt).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	err = database.DB.Model(&models.User{}).
+		Where("id = ? AND is_banned = ?", claims.UserID, true).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// InitTokenRevocation utils.ValidateJWT에 DB 기반 폐기 목록 등록
+func InitTokenRevocation() {
+	utils.SetRevocationChecker(dbRevocationChecker{})
+	log.Println("Token revocation list initialized")
+}
+
+func issueTokenPair(tx *gorm.DB, user *models.User, familyID string, userAgent string) (*TokenPair, error) {
+	accessToken, _, err := utils.GenerateAccessToken(user.ID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	rawRefresh, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	refreshExpiresAt := time.Now().Add(utils.RefreshTokenTTL())
+	refreshToken := models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(rawRefresh),
+		FamilyID:  familyID,
+		ExpiresAt: refreshExpiresAt,
+		UserAgent: userAgent,
+	}
+
+	if err := tx.Create(&refreshToken).Error; err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     rawRefresh,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(utils.AccessTokenTTL().Seconds()),
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func revokeRefreshFamily(familyID string) error {
+	return database.DB.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func hashToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
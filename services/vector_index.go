@@ -0,0 +1,331 @@
+package services
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"ongi-back/database"
+	"ongi-back/models"
+	"ongi-back/utils"
+)
+
+// SimilarityFilter 유사 프로필 검색 조건
+type SimilarityFilter struct {
+	MembersOnly    bool                   // 회원만 (비회원 세션 제외)
+	ExcludeExpired bool                   // 만료된 비회원 세션 제외
+	Metric         utils.SimilarityMetric // 유사도 척도 (nil이면 SIMILARITY_METRIC)
+}
+
+// vectorIndexEntry 인덱스에 등록된 응답자 (회원 프로필 또는 계정에 연동되지 않은 비회원 세션)
+type vectorIndexEntry struct {
+	Respondent Respondent
+	Vector     *utils.Vector5D
+	ExpiresAt  *time.Time // 비회원 세션 만료 시간
+}
+
+// vectorMatch 인덱스 검색 결과
+type vectorMatch struct {
+	Entry      *vectorIndexEntry
+	Similarity float64
+}
+
+// metricTree 척도별 k-d 트리
+// 척도의 Embed 공간에서는 유클리드 거리 순위가 유사도 순위와 같으므로, 변환한 벡터로 트리를 만든다.
+// 변환할 수 없는 벡터(코사인의 영벡터)는 트리에 넣지 않고 항상 가장 낮은 유사도로 취급한다.
+type metricTree struct {
+	metric       utils.SimilarityMetric
+	tree         *utils.KDTree
+	incomparable map[string]bool
+}
+
+func newMetricTree(metric utils.SimilarityMetric) *metricTree {
+	return &metricTree{
+		metric:       metric,
+		tree:         utils.NewKDTree(),
+		incomparable: make(map[string]bool),
+	}
+}
+
+func (mt *metricTree) insert(key string, v *utils.Vector5D) {
+	if embedded, ok := mt.metric.Embed(v); ok {
+		mt.tree.Insert(key, embedded)
+	} else {
+		mt.incomparable[key] = true
+	}
+}
+
+func (mt *metricTree) remove(key string) {
+	delete(mt.incomparable, key)
+	mt.tree.Remove(key)
+}
+
+// VectorIndex - 유사 프로필 검색용 프로세스 내 벡터 인덱스
+// 코사인 트리는 항상 유지하고, 다른 척도의 트리는 처음 검색할 때 만든다.
+// 인스턴스마다 따로 유지되므로 다른 인스턴스의 변경은 RebuildVectorIndex 전까지 반영되지 않는다.
+type VectorIndex struct {
+	mu      sync.RWMutex
+	entries map[string]*vectorIndexEntry
+	trees   map[string]*metricTree // 척도 이름별
+}
+
+// NewVectorIndex 빈 벡터 인덱스 생성
+func NewVectorIndex() *VectorIndex {
+	return &VectorIndex{
+		entries: make(map[string]*vectorIndexEntry),
+		trees:   map[string]*metricTree{utils.MetricCosine: newMetricTree(utils.CosineMetric{})},
+	}
+}
+
+// GlobalVectorIndex 서버 전역 벡터 인덱스
+var GlobalVectorIndex = NewVectorIndex()
+
+// InitVectorIndex DB의 회원 프로필과 비회원 세션 벡터로 인덱스 구성
+func InitVectorIndex() error {
+	if err := RebuildVectorIndex(); err != nil {
+		return err
+	}
+	log.Printf("Vector index initialized (%d respondents)", GlobalVectorIndex.Len())
+	return nil
+}
+
+// RebuildVectorIndex DB에서 인덱스를 다시 만들어 교체
+func RebuildVectorIndex() error {
+	entries, err := loadVectorIndexEntries()
+	if err != nil {
+		return err
+	}
+
+	rebuilt := NewVectorIndex()
+	for _, entry := range entries {
+		rebuilt.upsertLocked(entry)
+	}
+	for _, mt := range rebuilt.trees {
+		mt.tree.Rebuild()
+	}
+
+	GlobalVectorIndex.mu.Lock()
+	GlobalVectorIndex.entries = rebuilt.entries
+	GlobalVectorIndex.trees = rebuilt.trees
+	GlobalVectorIndex.mu.Unlock()
+
+	// 모집단이 바뀌었으므로 공분산 기반 척도는 다시 계산
+	resetPopulationMetrics()
+	return nil
+}
+
+// loadVectorIndexEntries 회원 프로필과 계정에 연동되지 않은 비회원 세션 벡터 조회 (연동된 세션은 회원 프로필과 중복)
+func loadVectorIndexEntries() ([]*vectorIndexEntry, error) {
+	var profiles []models.UserProfile
+	if err := database.DB.Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]*vectorIndexEntry, 0, len(profiles))
+	for i := range profiles {
+		entries = append(entries, &vectorIndexEntry{
+			Respondent: UserRespondent(profiles[i].UserID),
+			Vector:     profileVector(&profiles[i]),
+		})
+	}
+
+	var rows []struct {
+		SessionID string
+		Vector    []float64 `gorm:"serializer:json"`
+		ExpiresAt *time.Time
+	}
+	err := database.DB.Model(&models.SessionVector{}).
+		Select("session_vectors.session_id, session_vectors.vector, guest_sessions.expires_at").
+		Joins("LEFT JOIN guest_sessions ON guest_sessions.id = session_vectors.session_id").
+		Where("session_vectors.user_id IS NULL").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if v := utils.FromSlice(row.Vector); v != nil {
+			entries = append(entries, &vectorIndexEntry{
+				Respondent: GuestRespondent(row.SessionID),
+				Vector:     v,
+				ExpiresAt:  row.ExpiresAt,
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// Len 인덱스에 등록된 응답자 수
+func (idx *VectorIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Upsert 응답자 벡터 등록/교체
+func (idx *VectorIndex) Upsert(r Respondent, v *utils.Vector5D, expiresAt *time.Time) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.upsertLocked(&vectorIndexEntry{Respondent: r, Vector: v, ExpiresAt: expiresAt})
+}
+
+func (idx *VectorIndex) upsertLocked(entry *vectorIndexEntry) {
+	key := entry.Respondent.String()
+	idx.removeLocked(key)
+
+	idx.entries[key] = entry
+	for _, mt := range idx.trees {
+		mt.insert(key, entry.Vector)
+	}
+}
+
+// Remove 응답자 삭제
+func (idx *VectorIndex) Remove(r Respondent) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(r.String())
+}
+
+func (idx *VectorIndex) removeLocked(key string) {
+	if _, ok := idx.entries[key]; !ok {
+		return
+	}
+	delete(idx.entries, key)
+	for _, mt := range idx.trees {
+		mt.remove(key)
+	}
+}
+
+// RemoveExpiredGuests 만료 시간이 지난 비회원 세션 삭제
+func (idx *VectorIndex) RemoveExpiredGuests(now time.Time) int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	removed := 0
+	for key, entry := range idx.entries {
+		if entry.ExpiresAt != nil && entry.ExpiresAt.Before(now) {
+			idx.removeLocked(key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Lookup 인덱스에 등록된 응답자 벡터
+func (idx *VectorIndex) Lookup(r Respondent) (*utils.Vector5D, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	entry, ok := idx.entries[r.String()]
+	if !ok {
+		return nil, false
+	}
+	return entry.Vector, true
+}
+
+// Vectors 인덱스에 등록된 모든 응답자 벡터 (모집단 통계용)
+func (idx *VectorIndex) Vectors() []*utils.Vector5D {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	vectors := make([]*utils.Vector5D, 0, len(idx.entries))
+	for _, entry := range idx.entries {
+		vectors = append(vectors, entry.Vector)
+	}
+	return vectors
+}
+
+// treeFor 척도의 k-d 트리 (없거나 척도가 바뀌었으면 새로 만든다)
+func (idx *VectorIndex) treeFor(metric utils.SimilarityMetric) *metricTree {
+	idx.mu.RLock()
+	mt := idx.trees[metric.Name()]
+	idx.mu.RUnlock()
+	if mt != nil && mt.metric == metric {
+		return mt
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if mt = idx.trees[metric.Name()]; mt != nil && mt.metric == metric {
+		return mt
+	}
+	mt = newMetricTree(metric)
+	for key, entry := range idx.entries {
+		mt.insert(key, entry.Vector)
+	}
+	mt.tree.Rebuild()
+	idx.trees[metric.Name()] = mt
+	return mt
+}
+
+// Search query와 유사도가 높은 응답자 limit명 (유사도 내림차순, filter.Metric이 nil이면 코사인)
+// exclude가 true를 반환하는 응답자는 제외한다.
+func (idx *VectorIndex) Search(query *utils.Vector5D, limit int, filter SimilarityFilter, exclude func(Respondent) bool) []vectorMatch {
+	if limit <= 0 {
+		return nil
+	}
+
+	metric := filter.Metric
+	if metric == nil {
+		metric = utils.CosineMetric{}
+	}
+	mt := idx.treeFor(metric)
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	now := time.Now()
+	accept := func(key string) bool {
+		entry := idx.entries[key]
+		if entry == nil {
+			return false
+		}
+		if filter.MembersOnly && !entry.Respondent.IsUser() {
+			return false
+		}
+		if filter.ExcludeExpired && entry.ExpiresAt != nil && entry.ExpiresAt.Before(now) {
+			return false
+		}
+		return exclude == nil || !exclude(entry.Respondent)
+	}
+
+	var keys []string
+	if embedded, ok := metric.Embed(query); !ok {
+		// 비교할 수 없는 벡터는 모든 응답자와 유사도가 같음
+		for key := range idx.entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	} else {
+		for _, neighbor := range mt.tree.Nearest(embedded, limit, accept) {
+			keys = append(keys, neighbor.ID)
+		}
+		if len(keys) < limit {
+			incomparableKeys := make([]string, 0, len(mt.incomparable))
+			for key := range mt.incomparable {
+				incomparableKeys = append(incomparableKeys, key)
+			}
+			sort.Strings(incomparableKeys)
+			keys = append(keys, incomparableKeys...)
+		}
+	}
+
+	matches := make([]vectorMatch, 0, limit)
+	for _, key := range keys {
+		if len(matches) == limit {
+			break
+		}
+		if !accept(key) {
+			continue
+		}
+		entry := idx.entries[key]
+		matches = append(matches, vectorMatch{
+			Entry:      entry,
+			Similarity: metric.Score(query, entry.Vector),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Similarity > matches[j].Similarity })
+	return matches
+}
@@ -1,27 +1,117 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"ongi-back/config"
+	"ongi-back/database"
+
+	fasthttpws "github.com/fasthttp/websocket"
 	"github.com/gofiber/websocket/v2"
 )
 
+// WebSocket 연결 유지와 크기 제한
+const (
+	writeWait      = 10 * time.Second    // 프레임 하나를 쓰는 제한 시간
+	pongWait       = 60 * time.Second    // 이 시간 동안 아무 프레임(pong 포함)도 받지 못하면 연결 종료
+	pingPeriod     = (pongWait * 9) / 10 // ping 간격 (pongWait보다 짧아야 함)
+	maxFrameSize   = 32 * 1024           // 클라이언트 프레임 최대 크기 (바이트, 넘으면 연결 종료)
+	sendBufferSize = 256                 // 연결별 전송 대기 프레임 수 (가득 차면 느린 연결로 보고 종료)
+)
+
+// inboundBufferSize 브로커에서 받아 처리를 기다리는 이벤트 수 (가득 차면 버림, 클라이언트는 seq로 재전송 요청)
+const inboundBufferSize = 1024
+
 // Client WebSocket 클라이언트
+// /ws/chat/:roomId 연결은 RoomID 채팅방 하나만, /ws 연결(RoomID 0)은 사용자의 모든 채팅방을 구독한다.
 type Client struct {
 	Hub      *Hub
 	Conn     *websocket.Conn
 	Send     chan []byte
 	UserID   uint
 	RoomID   uint
+
+	rooms  map[uint]bool // 구독 중인 채팅방 (Hub.mu로 보호)
+	replay *replayState  // 재연결 재전송 (ReplayFrom으로 설정)
+}
+
+// replayState 재연결 재전송 상태
+// 재전송이 끝날 때까지 실시간 프레임은 Send 대신 held에 보류하고, 재전송 뒤에 순서대로 쓴다.
+type replayState struct {
+	mu      sync.Mutex
+	cursors map[uint]int64 // 채팅방별로 클라이언트가 받은 마지막 순번
+	active  bool
+	held    []heldFrame
+}
+
+type heldFrame struct {
+	roomID uint
+	seq    int64
+	frame  []byte
+}
+
+// NewClient 구독할 채팅방을 지정해 클라이언트 생성 (roomID가 0이면 다중 채팅방 연결)
+func NewClient(hub *Hub, conn *websocket.Conn, userID, roomID uint, roomIDs []uint) *Client {
+	client := &Client{
+		Hub:    hub,
+		Conn:   conn,
+		Send:   make(chan []byte, sendBufferSize),
+		UserID: userID,
+		RoomID: roomID,
+		rooms:  make(map[uint]bool, len(roomIDs)),
+	}
+	for _, id := range roomIDs {
+		client.rooms[id] = true
+	}
+	return client
+}
+
+// ReplayFrom 채팅방별로 받은 마지막 순번 이후의 메시지와 멤버 이벤트를 먼저 보내도록 설정 (Hub에 등록하기 전에 호출)
+func (c *Client) ReplayFrom(cursors map[uint]int64) {
+	c.replay = &replayState{cursors: cursors, active: true}
+}
+
+// hold 재전송 중이면 실시간 프레임을 보류 (반환: 보류했는지, 보류 한도 안인지)
+func (c *Client) hold(message *Message, frame []byte) (bool, bool) {
+	if c.replay == nil {
+		return false, false
+	}
+	c.replay.mu.Lock()
+	defer c.replay.mu.Unlock()
+	if !c.replay.active {
+		return false, false
+	}
+	if len(c.replay.held) >= sendBufferSize {
+		return true, false
+	}
+	c.replay.held = append(c.replay.held, heldFrame{roomID: message.RoomID, seq: message.Seq, frame: frame})
+	return true, true
+}
+
+// MultiRoom /ws 다중 채팅방 연결인지
+func (c *Client) MultiRoom() bool {
+	return c.RoomID == 0
 }
 
 // Hub WebSocket 연결 관리
+// 이 인스턴스의 연결에 전달하고, 같은 이벤트를 브로커로 다른 인스턴스에 보낸다.
 type Hub struct {
-	// 채팅방별 클라이언트 관리
+	// 채팅방별 구독 클라이언트
 	Rooms map[uint]map[*Client]bool
 
+	// 사용자별 연결 (채팅방 목록 갱신과 구독 변경용)
+	Users map[uint]map[*Client]bool
+
 	// 브로드캐스트 채널
 	Broadcast chan *Message
 
@@ -29,24 +119,177 @@ type Hub struct {
 	Register   chan *Client
 	Unregister chan *Client
 
+	broker  HubBroker   // 인스턴스 간 이벤트 전달
+	origin  string      // 이 Hub 식별자 (브로커로 돌아온 자기 이벤트를 거름)
+	inbound chan []byte // 브로커에서 받은 이벤트 (브로커 수신 고루틴을 막지 않도록 따로 처리)
+	stats   hubCounters
+
 	mu sync.RWMutex
 }
 
+// hubCounters 누적 전송 통계
+type hubCounters struct {
+	framesSent      atomic.Uint64
+	framesDropped   atomic.Uint64
+	slowEvictions   atomic.Uint64
+	pongTimeouts    atomic.Uint64
+	oversizedFrames atomic.Uint64
+	publishErrors   atomic.Uint64
+	eventsReceived  atomic.Uint64
+	eventsDropped   atomic.Uint64
+
+	mu            sync.Mutex
+	droppedByType map[string]uint64
+}
+
+// HubStats WebSocket 연결과 누적 전송 통계 (이 인스턴스 기준, 서버 시작 이후)
+type HubStats struct {
+	Instance        string            `json:"instance"`
+	Broker          string            `json:"broker"`
+	Connections     int               `json:"connections"`      // 현재 연결 수
+	Users           int               `json:"users"`            // 연결된 사용자 수
+	Rooms           int               `json:"rooms"`            // 구독자가 있는 채팅방 수
+	FramesSent      uint64            `json:"frames_sent"`      // 연결에 쓴 프레임
+	FramesDropped   uint64            `json:"frames_dropped"`   // 전송 버퍼가 가득 차 버린 프레임
+	DroppedByType   map[string]uint64 `json:"dropped_by_type"`  // 버린 프레임의 메시지 타입별 수
+	SlowEvictions   uint64            `json:"slow_evictions"`   // 버퍼가 가득 차 끊은 연결
+	PongTimeouts    uint64            `json:"pong_timeouts"`    // 제한 시간 안에 응답이 없어 끊은 연결
+	OversizedFrames uint64            `json:"oversized_frames"` // 크기 제한을 넘는 프레임을 보내 끊은 연결
+	PublishErrors   uint64            `json:"publish_errors"`   // 브로커 발행 실패
+	EventsReceived  uint64            `json:"events_received"`  // 다른 인스턴스에서 받은 이벤트
+	EventsDropped   uint64            `json:"events_dropped"`   // 처리 대기열이 가득 차 버린 이벤트
+}
+
+// hubEvent 브로커로 주고받는 Hub 이벤트
+type hubEvent struct {
+	Origin   string            `json:"origin"`
+	Kind     string            `json:"kind"`
+	RoomID   uint              `json:"room_id,omitempty"`
+	UserIDs  []uint            `json:"user_ids,omitempty"`
+	Message  *Message          `json:"message,omitempty"`
+	Messages map[uint]*Message `json:"messages,omitempty"`
+}
+
+// Hub 이벤트 종류
+const (
+	hubEventRoom        = "room"        // 채팅방 브로드캐스트 (Message)
+	hubEventUsers       = "users"       // 사용자별 전송 (Messages)
+	hubEventSubscribe   = "subscribe"   // 사용자들의 /ws 연결에 채팅방 구독 추가
+	hubEventUnsubscribe = "unsubscribe" // 사용자들의 연결에서 채팅방 구독 해제
+)
+
 // Message WebSocket 메시지 구조
 type Message struct {
-	Type       string      `json:"type"` // message, read, member_join, member_leave
+	Type       string      `json:"type"` // message, read, typing, delivered, member_join, member_leave, room_update, room_added, room_removed, replayed, ack, error
 	RoomID     uint        `json:"room_id"`
 	UserID     uint        `json:"user_id"`
+	Seq        int64       `json:"seq,omitempty"`       // message, member_join, member_leave: 채팅방 순번
+	ClientID   string      `json:"client_id,omitempty"` // ack/error: 요청 프레임의 client_id
 	Data       interface{} `json:"data"`
 }
 
-// NewHub Hub 생성
-func NewHub() *Hub {
+// ClientFrame 클라이언트가 보내는 프레임
+type ClientFrame struct {
+	Type     string          `json:"type"`      // message, typing, read, ack, subscribe, unsubscribe, replay
+	ClientID string          `json:"client_id"` // message는 멱등 키, ack/error 응답에 그대로 돌려줌
+	RoomID   uint            `json:"room_id"`   // /ws 연결은 필수, /ws/chat/:roomId 연결은 생략 가능
+	Data     json.RawMessage `json:"data"`
+}
+
+// 클라이언트 프레임 처리 오류 코드
+const (
+	FrameErrorInvalid     = "invalid_frame"   // JSON 또는 data 형식 오류
+	FrameErrorUnknownType = "unknown_type"    // 지원하지 않는 프레임
+	FrameErrorRoom        = "room_mismatch"   // 구독하지 않은 채팅방 (room_id 누락 포함)
+	FrameErrorMessage     = "invalid_message" // 메시지 입력 오류
+	FrameErrorNotMember   = "not_member"      // 채팅방 멤버가 아님
+	FrameErrorInternal    = "internal_error"  // 저장 실패
+)
+
+// NewHub Hub 생성 (broker로 다른 인스턴스와 이벤트를 주고받음)
+func NewHub(broker HubBroker) *Hub {
 	return &Hub{
 		Rooms:      make(map[uint]map[*Client]bool),
+		Users:      make(map[uint]map[*Client]bool),
 		Broadcast:  make(chan *Message, 256),
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
+		broker:     broker,
+		origin:     fmt.Sprintf("%s-%d-%d", jobInstanceName(), os.Getpid(), time.Now().UnixNano()),
+		inbound:    make(chan []byte, inboundBufferSize),
+	}
+}
+
+// Start 브로커 구독 후 Hub 실행
+func (h *Hub) Start(ctx context.Context) error {
+	go h.dispatch(ctx)
+	if err := h.broker.Start(ctx, h.deliver); err != nil {
+		return fmt.Errorf("start %s hub broker: %w", h.broker.Name(), err)
+	}
+	go h.Run()
+	return nil
+}
+
+// deliver 브로커 수신 고루틴에서 호출 (기다리지 않고 대기열에 넣음, 가득 차면 버림)
+// Hub가 밀려도 LISTEN/SUBSCRIBE 연결의 수신과 알림 대기열이 멈추지 않게 한다.
+func (h *Hub) deliver(payload []byte) {
+	select {
+	case h.inbound <- payload:
+	default:
+		h.stats.eventsDropped.Add(1)
+		log.Printf("Hub event queue full, dropping event (%d bytes)", len(payload))
+	}
+}
+
+// dispatch 받은 이벤트를 순서대로 처리 (구독 변경과 브로드캐스트 순서 유지)
+func (h *Hub) dispatch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case payload := <-h.inbound:
+			h.receive(payload)
+		}
+	}
+}
+
+// publish 다른 인스턴스에 이벤트 전달 (실패해도 이 인스턴스의 연결에는 이미 전달됨)
+func (h *Hub) publish(event *hubEvent) {
+	event.Origin = h.origin
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling hub event: %v", err)
+		return
+	}
+	if err := h.broker.Publish(payload); err != nil {
+		h.stats.publishErrors.Add(1)
+		log.Printf("Failed to publish %s hub event: %v", event.Kind, err)
+	}
+}
+
+// receive 다른 인스턴스에서 온 이벤트를 이 인스턴스의 연결에 전달
+func (h *Hub) receive(payload []byte) {
+	var event hubEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Printf("Invalid hub event: %v", err)
+		return
+	}
+	if event.Origin == h.origin {
+		return
+	}
+	h.stats.eventsReceived.Add(1)
+
+	switch event.Kind {
+	case hubEventRoom:
+		if event.Message != nil {
+			h.Broadcast <- event.Message
+		}
+	case hubEventUsers:
+		h.sendToUsers(event.Messages)
+	case hubEventSubscribe:
+		h.subscribeUsers(event.RoomID, event.UserIDs)
+	case hubEventUnsubscribe:
+		h.unsubscribeUsers(event.RoomID, event.UserIDs)
 	}
 }
 
@@ -56,134 +299,674 @@ func (h *Hub) Run() {
 		select {
 		case client := <-h.Register:
 			h.mu.Lock()
-			if _, ok := h.Rooms[client.RoomID]; !ok {
-				h.Rooms[client.RoomID] = make(map[*Client]bool)
+			if _, ok := h.Users[client.UserID]; !ok {
+				h.Users[client.UserID] = make(map[*Client]bool)
+			}
+			h.Users[client.UserID][client] = true
+			for roomID := range client.rooms {
+				h.addToRoom(client, roomID)
 			}
-			h.Rooms[client.RoomID][client] = true
 			h.mu.Unlock()
-			log.Printf("Client registered: UserID=%d, RoomID=%d", client.UserID, client.RoomID)
+			log.Printf("Client registered: UserID=%d, RoomID=%d, Rooms=%d", client.UserID, client.RoomID, len(client.rooms))
 
 		case client := <-h.Unregister:
 			h.mu.Lock()
-			if clients, ok := h.Rooms[client.RoomID]; ok {
-				if _, ok := clients[client]; ok {
-					delete(clients, client)
-					close(client.Send)
-					if len(clients) == 0 {
-						delete(h.Rooms, client.RoomID)
-					}
-				}
-			}
+			h.removeClient(client)
 			h.mu.Unlock()
 			log.Printf("Client unregistered: UserID=%d, RoomID=%d", client.UserID, client.RoomID)
 
 		case message := <-h.Broadcast:
-			h.mu.RLock()
-			if clients, ok := h.Rooms[message.RoomID]; ok {
-				messageBytes, err := json.Marshal(message)
-				if err != nil {
-					log.Printf("Error marshaling message: %v", err)
-					h.mu.RUnlock()
-					continue
-				}
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				log.Printf("Error marshaling message: %v", err)
+				continue
+			}
 
-				for client := range clients {
-					select {
-					case client.Send <- messageBytes:
-					default:
-						close(client.Send)
-						delete(clients, client)
-					}
+			var slow []*Client
+			h.mu.RLock()
+			for client := range h.Rooms[message.RoomID] {
+				if !h.enqueue(client, message, messageBytes) {
+					slow = append(slow, client)
 				}
 			}
 			h.mu.RUnlock()
+			h.evictSlow(slow)
+		}
+	}
+}
+
+// enqueue 전송 버퍼에 프레임 추가 (h.mu 읽기 잠금 필요, 버퍼가 가득 차면 버리고 false)
+// 재전송 중인 연결은 재전송이 끝날 때까지 보류한다.
+func (h *Hub) enqueue(client *Client, message *Message, frame []byte) bool {
+	if held, ok := client.hold(message, frame); held {
+		if !ok {
+			h.recordDrop(message.Type)
+		}
+		return ok
+	}
+
+	select {
+	case client.Send <- frame:
+		return true
+	default:
+		h.recordDrop(message.Type)
+		return false
+	}
+}
+
+// recordDrop 버린 프레임 집계
+func (h *Hub) recordDrop(msgType string) {
+	h.stats.framesDropped.Add(1)
+	h.stats.mu.Lock()
+	defer h.stats.mu.Unlock()
+	if h.stats.droppedByType == nil {
+		h.stats.droppedByType = make(map[string]uint64)
+	}
+	h.stats.droppedByType[msgType]++
+}
+
+// evictSlow 전송 버퍼가 가득 찬 연결 종료
+// 쓰기 잠금에서 제거하므로 다른 전송과 동시에 채널이 닫히지 않고, 이미 제거된 연결은 건너뛴다.
+// Send가 닫히면 WritePump가 close 프레임을 보내고 연결을 닫는다.
+func (h *Hub) evictSlow(clients []*Client) {
+	if len(clients) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, client := range clients {
+		if !h.Users[client.UserID][client] {
+			continue
+		}
+		h.removeClient(client)
+		h.stats.slowEvictions.Add(1)
+		log.Printf("Evicted slow client: UserID=%d, RoomID=%d", client.UserID, client.RoomID)
+	}
+}
+
+// Stats 현재 연결 수와 누적 전송 통계
+func (h *Hub) Stats() HubStats {
+	stats := HubStats{
+		Instance:        jobInstanceName(),
+		Broker:          h.broker.Name(),
+		FramesSent:      h.stats.framesSent.Load(),
+		FramesDropped:   h.stats.framesDropped.Load(),
+		DroppedByType:   map[string]uint64{},
+		SlowEvictions:   h.stats.slowEvictions.Load(),
+		PongTimeouts:    h.stats.pongTimeouts.Load(),
+		OversizedFrames: h.stats.oversizedFrames.Load(),
+		PublishErrors:   h.stats.publishErrors.Load(),
+		EventsReceived:  h.stats.eventsReceived.Load(),
+		EventsDropped:   h.stats.eventsDropped.Load(),
+	}
+
+	h.stats.mu.Lock()
+	for msgType, count := range h.stats.droppedByType {
+		stats.DroppedByType[msgType] = count
+	}
+	h.stats.mu.Unlock()
+
+	h.mu.RLock()
+	for _, clients := range h.Users {
+		stats.Connections += len(clients)
+	}
+	stats.Users = len(h.Users)
+	stats.Rooms = len(h.Rooms)
+	h.mu.RUnlock()
+	return stats
+}
+
+// addToRoom 채팅방 구독 추가 (h.mu 쓰기 잠금 필요)
+func (h *Hub) addToRoom(client *Client, roomID uint) {
+	if _, ok := h.Rooms[roomID]; !ok {
+		h.Rooms[roomID] = make(map[*Client]bool)
+	}
+	h.Rooms[roomID][client] = true
+	client.rooms[roomID] = true
+}
+
+// removeFromRoom 채팅방 구독 해제 (h.mu 쓰기 잠금 필요)
+func (h *Hub) removeFromRoom(client *Client, roomID uint) {
+	if clients, ok := h.Rooms[roomID]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.Rooms, roomID)
+		}
+	}
+	delete(client.rooms, roomID)
+}
+
+// removeClient 모든 구독을 해제하고 전송 채널을 닫음 (h.mu 쓰기 잠금 필요, 이미 제거됐으면 무시)
+func (h *Hub) removeClient(client *Client) {
+	clients, ok := h.Users[client.UserID]
+	if !ok || !clients[client] {
+		return
+	}
+	for roomID := range client.rooms {
+		h.removeFromRoom(client, roomID)
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(h.Users, client.UserID)
+	}
+	close(client.Send)
+}
+
+// Subscribe 클라이언트의 채팅방 구독 추가 (등록되지 않은 클라이언트면 false)
+func (h *Hub) Subscribe(client *Client, roomID uint) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.Users[client.UserID][client] {
+		return false
+	}
+	h.addToRoom(client, roomID)
+	return true
+}
+
+// Unsubscribe 클라이언트의 채팅방 구독 해제
+func (h *Hub) Unsubscribe(client *Client, roomID uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Users[client.UserID][client] {
+		h.removeFromRoom(client, roomID)
+	}
+}
+
+// SubscribeUsers 사용자들의 다중 채팅방 연결 모두에 채팅방 구독 추가 (채팅방에 새로 들어왔을 때, 모든 인스턴스)
+func (h *Hub) SubscribeUsers(roomID uint, userIDs []uint) {
+	h.subscribeUsers(roomID, userIDs)
+	h.publish(&hubEvent{Kind: hubEventSubscribe, RoomID: roomID, UserIDs: userIDs})
+}
+
+// UnsubscribeUsers 사용자들의 모든 연결에서 채팅방 구독 해제 (채팅방에서 나갔을 때, 모든 인스턴스)
+func (h *Hub) UnsubscribeUsers(roomID uint, userIDs []uint) {
+	h.unsubscribeUsers(roomID, userIDs)
+	h.publish(&hubEvent{Kind: hubEventUnsubscribe, RoomID: roomID, UserIDs: userIDs})
+}
+
+// SendToUsers 사용자별 메시지를 각자의 다중 채팅방 연결에 전송 (채팅방 목록 갱신용, 모든 인스턴스)
+func (h *Hub) SendToUsers(messages map[uint]*Message) {
+	if len(messages) == 0 {
+		return
+	}
+	h.sendToUsers(messages)
+	h.publish(&hubEvent{Kind: hubEventUsers, Messages: messages})
+}
+
+func (h *Hub) subscribeUsers(roomID uint, userIDs []uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, userID := range userIDs {
+		for client := range h.Users[userID] {
+			if client.MultiRoom() {
+				h.addToRoom(client, roomID)
+			}
+		}
+	}
+}
+
+func (h *Hub) unsubscribeUsers(roomID uint, userIDs []uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, userID := range userIDs {
+		for client := range h.Users[userID] {
+			h.removeFromRoom(client, roomID)
+		}
+	}
+}
+
+// sendToUsers 이 인스턴스의 연결에 전송 (버퍼가 가득 찬 연결은 종료)
+func (h *Hub) sendToUsers(messages map[uint]*Message) {
+	var slow []*Client
+	h.mu.RLock()
+	for userID, message := range messages {
+		if len(h.Users[userID]) == 0 {
+			continue
+		}
+		messageBytes, err := json.Marshal(message)
+		if err != nil {
+			log.Printf("Error marshaling message: %v", err)
+			continue
+		}
+		for client := range h.Users[userID] {
+			if client.MultiRoom() && !h.enqueue(client, message, messageBytes) {
+				slow = append(slow, client)
+			}
 		}
 	}
+	h.mu.RUnlock()
+	h.evictSlow(slow)
+}
+
+// Serve 연결이 끝날 때까지 읽기/쓰기 실행 (Hub에 등록한 뒤 호출)
+// 핸들러가 반환되면 연결이 재사용되므로 WritePump가 끝날 때까지 기다린다.
+func (c *Client) Serve() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.WritePump()
+	}()
+	c.ReadPump()
+	<-done
 }
 
 // ReadPump 클라이언트로부터 메시지 읽기
+// pongWait 동안 아무 프레임도 받지 못하거나 maxFrameSize를 넘는 프레임을 받으면 연결을 끊는다.
 func (c *Client) ReadPump() {
 	defer func() {
 		c.Hub.Unregister <- c
 		c.Conn.Close()
 	}()
 
+	c.Conn.SetReadLimit(maxFrameSize)
+	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.Conn.SetPongHandler(func(string) error {
+		return c.Conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
 	for {
 		_, message, err := c.Conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			var netErr net.Error
+			switch {
+			case errors.Is(err, fasthttpws.ErrReadLimit):
+				c.Hub.stats.oversizedFrames.Add(1)
+				log.Printf("WebSocket frame too large: UserID=%d, RoomID=%d", c.UserID, c.RoomID)
+			case errors.As(err, &netErr) && netErr.Timeout():
+				c.Hub.stats.pongTimeouts.Add(1)
+				log.Printf("WebSocket pong timeout: UserID=%d, RoomID=%d", c.UserID, c.RoomID)
+			case websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure):
 				log.Printf("WebSocket error: %v", err)
 			}
-			break
+			return
 		}
+		c.Conn.SetReadDeadline(time.Now().Add(pongWait))
 
-		// 클라이언트로부터 받은 메시지 처리
-		var msg Message
-		if err := json.Unmarshal(message, &msg); err != nil {
-			log.Printf("Error unmarshaling message: %v", err)
+		// 클라이언트로부터 받은 프레임 처리
+		var frame ClientFrame
+		if err := json.Unmarshal(message, &frame); err != nil {
+			c.replyError(c.RoomID, "", FrameErrorInvalid, "invalid JSON frame")
 			continue
 		}
 
-		// 메시지 유효성 검증
-		if msg.RoomID != c.RoomID {
-			log.Printf("Invalid room ID: expected=%d, got=%d", c.RoomID, msg.RoomID)
-			continue
+		c.handleFrame(&frame)
+	}
+}
+
+// handleFrame 클라이언트 프레임 처리 (room_id를 생략하면 /ws/chat/:roomId 연결의 채팅방)
+// - subscribe/unsubscribe: /ws 연결의 채팅방 구독 추가/해제 (멤버인 채팅방만)
+// - message: 메시지 저장 후 채팅방에 브로드캐스트하고 보낸 클라이언트에 ack (같은 client_id는 다시 저장하지 않음)
+// - typing: 입력 중 상태 브로드캐스트
+// - read: 읽음 처리 후 브로드캐스트
+// - ack: 메시지 수신 확인을 delivered로 브로드캐스트
+// - replay: after_seq 이후 메시지와 멤버 이벤트를 이 연결에 다시 보냄 (순번 빈틈을 발견했을 때)
+func (c *Client) handleFrame(frame *ClientFrame) {
+	roomID := frame.RoomID
+	if roomID == 0 {
+		roomID = c.RoomID
+	}
+	if roomID == 0 {
+		c.replyError(0, frame.ClientID, FrameErrorRoom, "room_id is required")
+		return
+	}
+
+	switch frame.Type {
+	case "subscribe", "unsubscribe":
+		if !c.MultiRoom() {
+			c.replyError(roomID, frame.ClientID, FrameErrorRoom, fmt.Sprintf("connected to room %d only", c.RoomID))
+			return
+		}
+		status := "unsubscribed"
+		if frame.Type == "subscribe" {
+			if err := checkChatMember(database.DB, roomID, c.UserID); err != nil {
+				c.replyChatError(roomID, frame.ClientID, err)
+				return
+			}
+			c.Hub.Subscribe(c, roomID)
+			status = "subscribed"
+		} else {
+			c.Hub.Unsubscribe(c, roomID)
 		}
+		c.Reply(&Message{
+			Type:     "ack",
+			RoomID:   roomID,
+			UserID:   c.UserID,
+			ClientID: frame.ClientID,
+			Data:     map[string]interface{}{"status": status},
+		})
+		return
+	}
 
-		// 브로드캐스트 (서버에서 처리 후 다시 보내는 방식이므로 여기서는 무시)
-		// 실제 메시지는 HTTP API를 통해 저장되고, 저장 후 Hub를 통해 브로드캐스트됨
+	if !c.Subscribed(roomID) {
+		c.replyError(roomID, frame.ClientID, FrameErrorRoom, fmt.Sprintf("not subscribed to room %d", roomID))
+		return
 	}
+
+	switch frame.Type {
+	case "message":
+		var input ChatMessageInput
+		if err := decodeFrameData(frame, &input); err != nil {
+			c.replyError(roomID, frame.ClientID, FrameErrorInvalid, err.Error())
+			return
+		}
+		if input.ClientID == "" {
+			input.ClientID = frame.ClientID
+		}
+
+		message, duplicate, err := SendChatMessage(roomID, c.UserID, input)
+		if err != nil {
+			c.replyChatError(roomID, frame.ClientID, err)
+			return
+		}
+		status := "sent"
+		if duplicate {
+			status = "duplicate"
+		}
+		c.Reply(&Message{
+			Type:     "ack",
+			RoomID:   roomID,
+			UserID:   c.UserID,
+			ClientID: input.ClientID,
+			Data: map[string]interface{}{
+				"message_id": message.ID,
+				"status":     status,
+				"created_at": message.CreatedAt,
+			},
+		})
+
+	case "typing":
+		typing := struct {
+			Typing *bool `json:"typing"`
+		}{}
+		if err := decodeFrameData(frame, &typing); err != nil {
+			c.replyError(roomID, frame.ClientID, FrameErrorInvalid, err.Error())
+			return
+		}
+		c.Hub.BroadcastMessage(roomID, "typing", c.UserID, map[string]interface{}{
+			"user_id": c.UserID,
+			"typing":  typing.Typing == nil || *typing.Typing,
+		})
+
+	case "read":
+		lastReadAt, err := MarkChatRoomRead(roomID, c.UserID)
+		if err != nil {
+			c.replyChatError(roomID, frame.ClientID, err)
+			return
+		}
+		if frame.ClientID != "" {
+			c.Reply(&Message{
+				Type:     "ack",
+				RoomID:   roomID,
+				UserID:   c.UserID,
+				ClientID: frame.ClientID,
+				Data: map[string]interface{}{
+					"status":       "read",
+					"last_read_at": lastReadAt,
+				},
+			})
+		}
+
+	case "ack":
+		var delivered struct {
+			MessageID uint `json:"message_id"`
+		}
+		if err := decodeFrameData(frame, &delivered); err != nil || delivered.MessageID == 0 {
+			c.replyError(roomID, frame.ClientID, FrameErrorInvalid, "data.message_id is required")
+			return
+		}
+		c.Hub.BroadcastMessage(roomID, "delivered", c.UserID, map[string]interface{}{
+			"user_id":    c.UserID,
+			"message_id": delivered.MessageID,
+		})
+
+	case "replay":
+		var replay struct {
+			AfterSeq *int64 `json:"after_seq"`
+		}
+		if err := decodeFrameData(frame, &replay); err != nil || replay.AfterSeq == nil || *replay.AfterSeq < 0 {
+			c.replyError(roomID, frame.ClientID, FrameErrorInvalid, "data.after_seq is required")
+			return
+		}
+		frames, truncated, err := LoadRoomReplay(roomID, *replay.AfterSeq, maxReplayPerRoom)
+		if err != nil {
+			c.replyChatError(roomID, frame.ClientID, err)
+			return
+		}
+		lastSeq := *replay.AfterSeq
+		for _, message := range frames {
+			c.Reply(message)
+			lastSeq = message.Seq
+		}
+		c.Reply(&Message{
+			Type:     "replayed",
+			RoomID:   roomID,
+			UserID:   c.UserID,
+			ClientID: frame.ClientID,
+			Data: map[string]interface{}{
+				"count":     len(frames),
+				"last_seq":  lastSeq,
+				"truncated": truncated,
+			},
+		})
+
+	default:
+		c.replyError(roomID, frame.ClientID, FrameErrorUnknownType, fmt.Sprintf("unknown frame type %q", frame.Type))
+	}
+}
+
+// decodeFrameData 프레임 data 파싱 (data가 없으면 빈 값)
+func decodeFrameData(frame *ClientFrame, v interface{}) error {
+	if len(frame.Data) == 0 || string(frame.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(frame.Data, v); err != nil {
+		return fmt.Errorf("invalid data for %s frame", frame.Type)
+	}
+	return nil
+}
+
+// replyChatError 채팅 서비스 오류를 error 프레임으로
+func (c *Client) replyChatError(roomID uint, clientID string, err error) {
+	var messageErr *ChatMessageError
+	switch {
+	case errors.As(err, &messageErr):
+		c.replyError(roomID, clientID, FrameErrorMessage, messageErr.Error())
+	case errors.Is(err, ErrNotChatMember), errors.Is(err, ErrChatRoomNotFound):
+		c.replyError(roomID, clientID, FrameErrorNotMember, err.Error())
+	default:
+		log.Printf("WebSocket frame failed: UserID=%d, RoomID=%d: %v", c.UserID, roomID, err)
+		c.replyError(roomID, clientID, FrameErrorInternal, "failed to process frame")
+	}
+}
+
+func (c *Client) replyError(roomID uint, clientID, code, message string) {
+	c.Reply(&Message{
+		Type:     "error",
+		RoomID:   roomID,
+		UserID:   c.UserID,
+		ClientID: clientID,
+		Data: map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// Reply 이 클라이언트에게만 프레임 전송 (등록이 해제됐으면 버리고, 버퍼가 가득 차면 연결 종료)
+func (c *Client) Reply(message *Message) {
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+
+	c.Hub.mu.RLock()
+	queued := !c.Hub.Users[c.UserID][c] || c.Hub.enqueue(c, message, messageBytes)
+	c.Hub.mu.RUnlock()
+	if !queued {
+		c.Hub.evictSlow([]*Client{c})
+	}
+}
+
+// Subscribed 채팅방을 구독 중인지
+func (c *Client) Subscribed(roomID uint) bool {
+	c.Hub.mu.RLock()
+	defer c.Hub.mu.RUnlock()
+	return c.rooms[roomID]
+}
+
+// SubscribedRooms 구독 중인 채팅방 ID
+func (c *Client) SubscribedRooms() []uint {
+	c.Hub.mu.RLock()
+	defer c.Hub.mu.RUnlock()
+	return sortedIDs(c.rooms)
 }
 
-// WritePump 클라이언트로 메시지 쓰기
+// WritePump 클라이언트로 메시지 쓰기 (프레임마다 JSON 메시지 하나, pingPeriod마다 ping)
 func (c *Client) WritePump() {
+	ticker := time.NewTicker(pingPeriod)
 	defer func() {
+		ticker.Stop()
 		c.Conn.Close()
 	}()
 
+	if c.replay != nil && !c.writeReplay() {
+		return
+	}
+
 	for {
-		message, ok := <-c.Send
-		if !ok {
-			c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
-			return
+		select {
+		case message, ok := <-c.Send:
+			if !ok {
+				// Hub에서 제거됨 (연결 종료 또는 느린 연결)
+				c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if !c.writeFrame(message) {
+				return
+			}
+
+		case <-ticker.C:
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
 		}
+	}
+}
 
-		w, err := c.Conn.NextWriter(websocket.TextMessage)
-		if err != nil {
-			return
+// writeReplay 놓친 메시지와 멤버 이벤트를 DB에서 읽어 먼저 쓰고, 그동안 보류한 실시간 프레임을 이어서 씀 (쓰기 실패 시 false)
+// 채팅방마다 재전송 끝에 replayed 프레임을 보낸다. truncated이면 나머지는 HTTP로 조회해야 한다.
+// Hub에 등록된 뒤에 읽으므로 그 사이에 저장된 메시지는 재전송과 보류 프레임 중 적어도 한쪽에 있다 (겹치면 보류 프레임을 건너뜀).
+func (c *Client) writeReplay() bool {
+	// 등록 전에 넣어 둔 프레임(subscribed)을 먼저 씀 (재전송 중의 실시간 프레임은 Send가 아니라 보류 목록으로 감)
+	for n := len(c.Send); n > 0; n-- {
+		frame, ok := <-c.Send
+		if !ok || !c.writeFrame(frame) {
+			return false
 		}
-		w.Write(message)
+	}
 
-		// 대기 중인 메시지들도 한번에 전송
-		n := len(c.Send)
-		for i := 0; i < n; i++ {
-			w.Write([]byte{'\n'})
-			w.Write(<-c.Send)
+	roomIDs := make([]uint, 0, len(c.replay.cursors))
+	for roomID := range c.replay.cursors {
+		roomIDs = append(roomIDs, roomID)
+	}
+	sort.Slice(roomIDs, func(i, j int) bool { return roomIDs[i] < roomIDs[j] })
+
+	lastSeq := make(map[uint]int64, len(roomIDs))
+	for _, roomID := range roomIDs {
+		lastSeq[roomID] = c.replay.cursors[roomID]
+		frames, truncated, err := LoadRoomReplay(roomID, lastSeq[roomID], maxReplayPerRoom)
+		if err != nil {
+			log.Printf("WebSocket replay failed: UserID=%d, RoomID=%d: %v", c.UserID, roomID, err)
+			truncated = true
+		}
+		for _, frame := range frames {
+			if !c.writeJSON(frame) {
+				return false
+			}
+			lastSeq[roomID] = frame.Seq
 		}
+		if !c.writeJSON(&Message{
+			Type:   "replayed",
+			RoomID: roomID,
+			UserID: c.UserID,
+			Data: map[string]interface{}{
+				"count":     len(frames),
+				"last_seq":  lastSeq[roomID],
+				"truncated": truncated,
+			},
+		}) {
+			return false
+		}
+	}
 
-		if err := w.Close(); err != nil {
-			return
+	c.replay.mu.Lock()
+	held := c.replay.held
+	c.replay.held = nil
+	c.replay.active = false
+	c.replay.mu.Unlock()
+
+	for _, frame := range held {
+		if frame.seq > 0 && frame.seq <= lastSeq[frame.roomID] {
+			continue
 		}
+		if !c.writeFrame(frame.frame) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) writeJSON(message *Message) bool {
+	frame, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return true
+	}
+	return c.writeFrame(frame)
+}
+
+func (c *Client) writeFrame(frame []byte) bool {
+	c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := c.Conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+		return false
 	}
+	c.Hub.stats.framesSent.Add(1)
+	return true
 }
 
-// BroadcastMessage 메시지 브로드캐스트
+// BroadcastMessage 메시지 브로드캐스트 (모든 인스턴스의 채팅방 구독자)
 func (h *Hub) BroadcastMessage(roomID uint, msgType string, userID uint, data interface{}) {
-	message := &Message{
+	h.BroadcastEvent(&Message{
 		Type:   msgType,
 		RoomID: roomID,
 		UserID: userID,
 		Data:   data,
-	}
+	})
+}
+
+// BroadcastEvent 채팅방 순번 등을 채운 메시지를 그대로 브로드캐스트 (모든 인스턴스의 채팅방 구독자)
+func (h *Hub) BroadcastEvent(message *Message) {
+	h.publish(&hubEvent{Kind: hubEventRoom, Message: message})
 	h.Broadcast <- message
 }
 
 // 전역 Hub 인스턴스
 var GlobalHub *Hub
 
-// InitHub Hub 초기화
-func InitHub() {
-	GlobalHub = NewHub()
-	go GlobalHub.Run()
-	log.Println("WebSocket Hub initialized")
+// InitHub Hub 초기화 (HUB_BROKER로 인스턴스 간 전달 방식 선택)
+func InitHub(ctx context.Context) error {
+	broker, err := NewHubBroker(config.AppConfig.HubBroker)
+	if err != nil {
+		return err
+	}
+	hub := NewHub(broker)
+	if err := hub.Start(ctx); err != nil {
+		return err
+	}
+	GlobalHub = hub
+	log.Printf("WebSocket Hub initialized (broker: %s)", broker.Name())
+	return nil
 }
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHubDeliverDoesNotBlock(t *testing.T) {
+	// dispatch를 돌리지 않아 대기열이 비지 않는 Hub
+	hub := NewHub(NewMemoryBroker())
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < inboundBufferSize+10; i++ {
+			hub.deliver([]byte(`{"origin":"other","kind":"room"}`))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deliver blocked on a full event queue")
+	}
+	if got := hub.Stats().EventsDropped; got != 10 {
+		t.Errorf("EventsDropped = %d, want 10", got)
+	}
+}
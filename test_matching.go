@@ -18,13 +18,13 @@ func main() {
 	fmt.Println("Deleted all club members")
 
 	// 그룹 매칭 실행
-	err := services.MatchUsersToClubs()
+	report, err := services.MatchUsersToClubs(services.MatchOptions{})
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
-	fmt.Println("Matching completed!")
+	fmt.Printf("Matching completed! (%d groups, silhouette %.3f)\n", len(report.Groups), report.Quality.Silhouette)
 
 	// 결과 확인: 사용자 76과 같은 클럽에 있는 유사 사용자 확인
 	var user76Members []models.ClubMember
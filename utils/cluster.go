@@ -0,0 +1,315 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ClusterOptions - k-평균 군집화 옵션
+type ClusterOptions struct {
+	K       int   // 군집 수 (1 이상, 점 개수보다 크면 점 개수로 줄인다)
+	MaxSize int   // 군집 최대 크기 (0이면 제한 없음)
+	Seed    int64 // 초기 중심 선택용 시드 (입력과 시드가 같으면 결과도 같다)
+	MaxIter int   // 최대 반복 횟수 (0이면 100)
+}
+
+// ClusterResult - 군집화 결과
+type ClusterResult struct {
+	Assignments []int       // 점별 군집 번호 (0 ~ K-1)
+	Centroids   []*Vector5D // 군집 중심
+	Iterations  int         // 실제 반복 횟수
+	Converged   bool        // 배정이 더 이상 바뀌지 않아 멈췄는지
+}
+
+// ClusterQuality - 군집 품질 지표
+type ClusterQuality struct {
+	Inertia    float64 `json:"inertia"`     // 중심까지 거리 제곱의 합 (작을수록 응집)
+	Silhouette float64 `json:"silhouette"`  // 평균 실루엣 계수 (-1~1, 클수록 군집이 잘 분리됨)
+	MinSize    int     `json:"min_size"`    // 가장 작은 군집 크기
+	MaxSize    int     `json:"max_size"`    // 가장 큰 군집 크기
+	MeanSize   float64 `json:"mean_size"`   // 평균 군집 크기
+	SizeStdDev float64 `json:"size_stddev"` // 군집 크기 표준편차 (작을수록 균형)
+}
+
+// ErrClusterCapacity - 군집 수 x 최대 크기가 점 개수보다 작음
+var ErrClusterCapacity = errors.New("cluster capacity is smaller than the number of points")
+
+const defaultClusterMaxIter = 100
+
+// clusterPatience - 크기 제한이 있을 때 배정이 진동하면, 이 횟수만큼 개선이 없을 때 가장 좋았던 결과로 멈춘다
+const clusterPatience = 10
+
+// silhouetteSampleSize - 실루엣 계수를 계산할 최대 점 개수 (점이 더 많으면 등간격으로 표본 추출)
+const silhouetteSampleSize = 1000
+
+// KMeans - k-means++ 초기화를 사용한 k-평균 군집화
+// MaxSize가 있으면 가장 가까운 중심과 다음 중심의 거리 차이가 큰 점부터 자리가 남은 가장 가까운 군집에 배정한다.
+func KMeans(points []*Vector5D, opts ClusterOptions) (*ClusterResult, error) {
+	n := len(points)
+	if n == 0 {
+		return &ClusterResult{Converged: true}, nil
+	}
+
+	k := opts.K
+	if k < 1 {
+		return nil, errors.New("cluster count must be at least 1")
+	}
+	if k > n {
+		k = n
+	}
+	if opts.MaxSize > 0 && k*opts.MaxSize < n {
+		return nil, ErrClusterCapacity
+	}
+	maxIter := opts.MaxIter
+	if maxIter <= 0 {
+		maxIter = defaultClusterMaxIter
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	result := &ClusterResult{
+		Assignments: make([]int, n),
+		Centroids:   initCentroids(points, k, rng),
+	}
+	for i := range result.Assignments {
+		result.Assignments[i] = -1
+	}
+
+	var best *ClusterResult
+	bestInertia, stale := math.Inf(1), 0
+	for result.Iterations < maxIter {
+		result.Iterations++
+
+		assignments := assignClusters(points, result.Centroids, opts.MaxSize)
+		changed := false
+		for i := range assignments {
+			if assignments[i] != result.Assignments[i] {
+				changed = true
+				break
+			}
+		}
+		result.Assignments = assignments
+		if !changed {
+			result.Converged = true
+			return result, nil
+		}
+
+		result.Centroids = updateCentroids(points, assignments, result.Centroids)
+
+		inertia := 0.0
+		for i, p := range points {
+			inertia += squaredDistance(p, result.Centroids[assignments[i]])
+		}
+		if inertia < bestInertia {
+			bestInertia, stale = inertia, 0
+			best = &ClusterResult{Assignments: assignments, Centroids: result.Centroids}
+		} else if stale++; stale >= clusterPatience {
+			break
+		}
+	}
+
+	best.Iterations = result.Iterations
+	return best, nil
+}
+
+// initCentroids - k-means++ 초기 중심 (이미 고른 중심과 멀수록 뽑힐 확률이 높다)
+func initCentroids(points []*Vector5D, k int, rng *rand.Rand) []*Vector5D {
+	centroids := []*Vector5D{copyVector(points[rng.Intn(len(points))])}
+
+	distSq := make([]float64, len(points))
+	for len(centroids) < k {
+		total := 0.0
+		for i, p := range points {
+			d := squaredDistance(p, centroids[len(centroids)-1])
+			if len(centroids) == 1 || d < distSq[i] {
+				distSq[i] = d
+			}
+			total += distSq[i]
+		}
+
+		// 모든 점이 이미 중심과 겹치면 앞에서부터 채운다
+		next := len(centroids) % len(points)
+		if total > 0 {
+			r := rng.Float64() * total
+			for i, d := range distSq {
+				r -= d
+				if r < 0 {
+					next = i
+					break
+				}
+			}
+		}
+		centroids = append(centroids, copyVector(points[next]))
+	}
+	return centroids
+}
+
+// assignClusters - 각 점을 가장 가까운 중심에 배정 (maxSize가 있으면 자리가 남은 군집 중 가장 가까운 곳)
+func assignClusters(points []*Vector5D, centroids []*Vector5D, maxSize int) []int {
+	assignments := make([]int, len(points))
+
+	type candidate struct {
+		index  int
+		order  []int     // 가까운 중심 순서
+		dist   []float64 // 중심별 거리 제곱
+		regret float64   // 두 번째로 가까운 중심과의 거리 차이
+	}
+
+	candidates := make([]candidate, len(points))
+	for i, p := range points {
+		c := candidate{index: i, order: make([]int, len(centroids)), dist: make([]float64, len(centroids))}
+		for j, centroid := range centroids {
+			c.order[j] = j
+			c.dist[j] = squaredDistance(p, centroid)
+		}
+		sort.SliceStable(c.order, func(a, b int) bool { return c.dist[c.order[a]] < c.dist[c.order[b]] })
+		if len(c.order) > 1 {
+			c.regret = c.dist[c.order[1]] - c.dist[c.order[0]]
+		}
+		candidates[i] = c
+	}
+
+	if maxSize <= 0 {
+		for _, c := range candidates {
+			assignments[c.index] = c.order[0]
+		}
+		return assignments
+	}
+
+	// 다른 군집으로 밀려났을 때 손해가 큰 점부터 배정
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].regret > candidates[b].regret })
+	sizes := make([]int, len(centroids))
+	for _, c := range candidates {
+		for _, cluster := range c.order {
+			if sizes[cluster] < maxSize {
+				assignments[c.index] = cluster
+				sizes[cluster]++
+				break
+			}
+		}
+	}
+	return assignments
+}
+
+// updateCentroids - 군집 평균으로 중심 이동 (빈 군집은 이전 중심 유지)
+func updateCentroids(points []*Vector5D, assignments []int, previous []*Vector5D) []*Vector5D {
+	sums := make([][kdDimensions]float64, len(previous))
+	counts := make([]int, len(previous))
+	for i, p := range points {
+		cluster := assignments[i]
+		for d, x := range p.ToSlice() {
+			sums[cluster][d] += x
+		}
+		counts[cluster]++
+	}
+
+	centroids := make([]*Vector5D, len(previous))
+	for cluster := range previous {
+		if counts[cluster] == 0 {
+			centroids[cluster] = previous[cluster]
+			continue
+		}
+		mean := make([]float64, kdDimensions)
+		for d := range mean {
+			mean[d] = sums[cluster][d] / float64(counts[cluster])
+		}
+		centroids[cluster] = FromSlice(mean)
+	}
+	return centroids
+}
+
+// EvaluateClusters - 군집 품질 지표 계산 (빈 군집은 크기 통계에서 제외)
+func EvaluateClusters(points []*Vector5D, result *ClusterResult) ClusterQuality {
+	quality := ClusterQuality{}
+	if len(points) == 0 || result == nil || len(result.Centroids) == 0 {
+		return quality
+	}
+
+	sizes := make([]int, len(result.Centroids))
+	for i, p := range points {
+		cluster := result.Assignments[i]
+		sizes[cluster]++
+		quality.Inertia += squaredDistance(p, result.Centroids[cluster])
+	}
+	quality.Inertia = math.Round(quality.Inertia*10) / 10
+
+	nonEmpty := 0
+	for _, size := range sizes {
+		if size == 0 {
+			continue
+		}
+		if nonEmpty == 0 || size < quality.MinSize {
+			quality.MinSize = size
+		}
+		if size > quality.MaxSize {
+			quality.MaxSize = size
+		}
+		nonEmpty++
+	}
+	quality.MeanSize = float64(len(points)) / float64(nonEmpty)
+	variance := 0.0
+	for _, size := range sizes {
+		if size > 0 {
+			variance += (float64(size) - quality.MeanSize) * (float64(size) - quality.MeanSize)
+		}
+	}
+	quality.SizeStdDev = math.Round(math.Sqrt(variance/float64(nonEmpty))*100) / 100
+	quality.MeanSize = math.Round(quality.MeanSize*100) / 100
+
+	quality.Silhouette = silhouette(points, result.Assignments, sizes)
+	return quality
+}
+
+// silhouette - 평균 실루엣 계수 (점이 많으면 등간격 표본으로 계산, 군집이 하나면 0)
+func silhouette(points []*Vector5D, assignments []int, sizes []int) float64 {
+	step := 1
+	if len(points) > silhouetteSampleSize {
+		step = (len(points) + silhouetteSampleSize - 1) / silhouetteSampleSize
+	}
+
+	total, count := 0.0, 0
+	sums := make([]float64, len(sizes))
+	for i := 0; i < len(points); i += step {
+		for c := range sums {
+			sums[c] = 0
+		}
+		for j, q := range points {
+			if i != j {
+				sums[assignments[j]] += EuclideanDistance(points[i], q)
+			}
+		}
+
+		own := assignments[i]
+		count++
+		if sizes[own] <= 1 {
+			continue // 혼자인 군집의 점은 0
+		}
+		a := sums[own] / float64(sizes[own]-1)
+		b := math.Inf(1)
+		for c, size := range sizes {
+			if c != own && size > 0 {
+				b = math.Min(b, sums[c]/float64(size))
+			}
+		}
+		if math.IsInf(b, 1) || math.Max(a, b) == 0 {
+			continue
+		}
+		total += (b - a) / math.Max(a, b)
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return math.Round(total/float64(count)*1000) / 1000
+}
+
+func squaredDistance(v1, v2 *Vector5D) float64 {
+	d := EuclideanDistance(v1, v2)
+	return d * d
+}
+
+func copyVector(v *Vector5D) *Vector5D {
+	c := *v
+	return &c
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// blobPoints - 중심 주변에 모인 점들 (중심마다 perCenter개)
+func blobPoints(rng *rand.Rand, centers []*Vector5D, perCenter int) []*Vector5D {
+	var points []*Vector5D
+	for _, c := range centers {
+		for i := 0; i < perCenter; i++ {
+			points = append(points, &Vector5D{
+				Sociality:   c.Sociality + rng.Float64()*10 - 5,
+				Activity:    c.Activity + rng.Float64()*10 - 5,
+				Intimacy:    c.Intimacy + rng.Float64()*10 - 5,
+				Immersion:   c.Immersion + rng.Float64()*10 - 5,
+				Flexibility: c.Flexibility + rng.Float64()*10 - 5,
+			})
+		}
+	}
+	return points
+}
+
+func clusterSizes(result *ClusterResult) []int {
+	sizes := make([]int, len(result.Centroids))
+	for _, c := range result.Assignments {
+		sizes[c]++
+	}
+	return sizes
+}
+
+func TestKMeansMaxSize(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	// 한 덩어리에 점이 몰려 있어 제한이 없으면 군집 크기가 크게 달라진다
+	points := append(
+		blobPoints(rng, []*Vector5D{{Sociality: 20, Activity: 20, Intimacy: 20, Immersion: 20, Flexibility: 20}}, 60),
+		blobPoints(rng, []*Vector5D{{Sociality: 80, Activity: 80, Intimacy: 80, Immersion: 80, Flexibility: 80}}, 15)...,
+	)
+	points = append(points, randomPoints(rng, 25)...)
+
+	tests := []struct {
+		name    string
+		k       int
+		maxSize int
+	}{
+		{"tight capacity", 4, 25},
+		{"loose capacity", 4, 40},
+		{"many small clusters", 20, 5},
+		{"k above point count", 200, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := KMeans(points, ClusterOptions{K: tt.k, MaxSize: tt.maxSize, Seed: 3})
+			if err != nil {
+				t.Fatalf("KMeans: %v", err)
+			}
+			if len(result.Assignments) != len(points) {
+				t.Fatalf("got %d assignments, want %d", len(result.Assignments), len(points))
+			}
+			for cluster, size := range clusterSizes(result) {
+				if size > tt.maxSize {
+					t.Errorf("cluster %d has %d points, max %d", cluster, size, tt.maxSize)
+				}
+			}
+		})
+	}
+}
+
+func TestKMeansSeparatesBlobs(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	centers := []*Vector5D{
+		{Sociality: 10, Activity: 10, Intimacy: 10, Immersion: 10, Flexibility: 10},
+		{Sociality: 90, Activity: 10, Intimacy: 90, Immersion: 10, Flexibility: 90},
+		{Sociality: 50, Activity: 90, Intimacy: 50, Immersion: 90, Flexibility: 50},
+	}
+	points := blobPoints(rng, centers, 20)
+
+	result, err := KMeans(points, ClusterOptions{K: 3, Seed: 1})
+	if err != nil {
+		t.Fatalf("KMeans: %v", err)
+	}
+	if !result.Converged {
+		t.Error("expected well separated blobs to converge")
+	}
+	for blob := range centers {
+		first := result.Assignments[blob*20]
+		for i := blob * 20; i < (blob+1)*20; i++ {
+			if result.Assignments[i] != first {
+				t.Fatalf("blob %d split across clusters %d and %d", blob, first, result.Assignments[i])
+			}
+		}
+	}
+	if got := EvaluateClusters(points, result); got.MinSize != 20 || got.MaxSize != 20 {
+		t.Errorf("cluster sizes = %d..%d, want 20", got.MinSize, got.MaxSize)
+	}
+}
+
+func TestKMeansDeterministic(t *testing.T) {
+	points := randomPoints(rand.New(rand.NewSource(5)), 80)
+	opts := ClusterOptions{K: 5, MaxSize: 20, Seed: 42}
+
+	first, err := KMeans(points, opts)
+	if err != nil {
+		t.Fatalf("KMeans: %v", err)
+	}
+	second, err := KMeans(points, opts)
+	if err != nil {
+		t.Fatalf("KMeans: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("same points and seed produced different clusters")
+	}
+}
+
+func TestKMeansErrors(t *testing.T) {
+	points := randomPoints(rand.New(rand.NewSource(1)), 10)
+
+	if _, err := KMeans(points, ClusterOptions{K: 3, MaxSize: 3}); !errors.Is(err, ErrClusterCapacity) {
+		t.Errorf("K*MaxSize below point count: err = %v, want ErrClusterCapacity", err)
+	}
+	if _, err := KMeans(points, ClusterOptions{K: 0}); err == nil {
+		t.Error("K=0: expected error")
+	}
+	result, err := KMeans(nil, ClusterOptions{K: 3})
+	if err != nil || !result.Converged || len(result.Assignments) != 0 {
+		t.Errorf("no points: result = %+v, err = %v", result, err)
+	}
+}
+
+func randomPoints(rng *rand.Rand, n int) []*Vector5D {
+	points := make([]*Vector5D, n)
+	for i := range points {
+		points[i] = randomVector(rng)
+	}
+	return points
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule - 다음 실행 시각 계산
+type Schedule interface {
+	Next(after time.Time) time.Time // after 이후(같은 시각 제외)의 첫 실행 시각
+}
+
+// cronSchedule - 5필드 cron 표현식 (분 시 일 월 요일)
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool // 일/요일이 '*'로 시작하는지 (둘 다 제한되면 둘 중 하나만 맞아도 실행)
+	spec                          string
+}
+
+// everySchedule - 고정 간격 (@every 10m)
+type everySchedule struct {
+	interval time.Duration
+}
+
+// cronField - 필드별 허용 범위
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0과 7 모두 일요일
+}
+
+// cronDescriptors - 자주 쓰는 표현식 별칭
+var cronDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// cronSearchLimit - 다음 실행 시각을 찾을 최대 기간 (2월 30일처럼 오지 않는 날짜 방지)
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// ParseSchedule - cron 표현식 파싱
+// "분 시 일 월 요일" 5필드(*, */n, a-b, a-b/n, 쉼표 목록), @hourly 같은 별칭, "@every 10m"을 지원한다.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be at least 1s", spec)
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	if expr, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected %d fields, got %d", spec, len(cronFields), len(parts))
+	}
+
+	bits := make([]uint64, len(cronFields))
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		bits[i] = b
+	}
+
+	// 요일 7은 0(일요일)과 같다
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &cronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: strings.HasPrefix(parts[2], "*"), // "*/2"도 제한 없는 필드로 본다 (cron 규칙)
+		dowAny: strings.HasPrefix(parts[4], "*"),
+		spec:   spec,
+	}, nil
+}
+
+// parseCronField - 필드 하나를 허용 값 비트 집합으로 변환
+func parseCronField(expr string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangeExpr = item[:i]
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step in %q", field.name, item)
+			}
+			step = n
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil || a > b {
+				return 0, fmt.Errorf("%s: invalid range %q", field.name, item)
+			}
+			start, end = a, b
+		default:
+			n, err := strconv.Atoi(rangeExpr)
+			if err != nil {
+				return 0, fmt.Errorf("%s: invalid value %q", field.name, item)
+			}
+			start, end = n, n
+			if step > 1 {
+				end = field.max // "5/15"는 5부터 15 간격
+			}
+		}
+		if start < field.min || end > field.max {
+			return 0, fmt.Errorf("%s: %q out of range %d-%d", field.name, item, field.min, field.max)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next - after 이후 표현식과 맞는 첫 분 (after의 시간대 기준)
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches - 일과 요일이 모두 제한되면 둘 중 하나, 아니면 둘 다 맞아야 한다 (cron 규칙)
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if !s.domAny && !s.dowAny {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+func (s *cronSchedule) String() string {
+	return s.spec
+}
+
+// Next - after 이후 interval만큼 지난 시각
+func (s everySchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+func (s everySchedule) String() string {
+	return "@every " + s.interval.String()
+}
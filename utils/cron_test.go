@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 500ms",
+		"@every soon",
+	}
+	for _, spec := range tests {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q): expected error", spec)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2024-01-01은 월요일
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"every minute", "* * * * *", at(1, 1, 10, 0), at(1, 1, 10, 1)},
+		{"seconds truncated", "* * * * *", at(1, 1, 10, 0).Add(30 * time.Second), at(1, 1, 10, 1)},
+		{"hourly alias", "@hourly", at(1, 1, 10, 0), at(1, 1, 11, 0)},
+		{"daily alias next day", "@daily", at(1, 1, 10, 0), at(1, 2, 0, 0)},
+		{"minute step", "*/15 * * * *", at(1, 1, 10, 1), at(1, 1, 10, 15)},
+		{"value with step", "5/20 * * * *", at(1, 1, 10, 26), at(1, 1, 10, 45)},
+		{"range", "0 9-17 * * *", at(1, 1, 18, 0), at(1, 2, 9, 0)},
+		{"range with step", "0 9-17/4 * * *", at(1, 1, 13, 0), at(1, 1, 17, 0)},
+		{"list", "0 8,20 * * *", at(1, 1, 8, 0), at(1, 1, 20, 0)},
+		{"month rollover", "0 0 1 * *", at(1, 15, 0, 0), at(2, 1, 0, 0)},
+		{"leap day", "0 0 29 2 *", at(1, 1, 0, 0), at(2, 29, 0, 0)},
+		{"weekday", "0 9 * * 1-5", at(1, 5, 9, 0), at(1, 8, 9, 0)},   // 금 -> 월
+		{"sunday as 0", "0 0 * * 0", at(1, 1, 0, 0), at(1, 7, 0, 0)}, // 월 -> 일
+		{"sunday as 7", "0 0 * * 7", at(1, 1, 0, 0), at(1, 7, 0, 0)}, // 7도 일요일
+		{"dom or dow", "0 0 15 * 5", at(1, 1, 0, 0), at(1, 5, 0, 0)}, // 금요일이 15일보다 먼저
+		{"dom or dow dom first", "0 0 2 * 5", at(1, 1, 0, 0), at(1, 2, 0, 0)},
+		{"dom with dow star", "0 0 15 * *", at(1, 1, 0, 0), at(1, 15, 0, 0)},
+		{"dow with dom star", "0 0 * * 5", at(1, 1, 0, 0), at(1, 5, 0, 0)},
+		{"dom step is unrestricted", "0 0 */1 * 5", at(1, 1, 0, 0), at(1, 5, 0, 0)}, // 둘 다 맞아야 함
+		{"dow step is unrestricted", "0 0 15 * */1", at(1, 1, 0, 0), at(1, 15, 0, 0)},
+		{"dom step and dow", "0 0 */2 * 1", at(1, 1, 0, 0), at(1, 15, 0, 0)}, // 홀수 일이면서 월요일
+		{"every interval", "@every 90m", at(1, 1, 10, 0), at(1, 1, 11, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q): %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextNever(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedule.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next = %s, want zero time for February 30", got)
+	}
+}
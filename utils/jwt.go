@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"time"
@@ -8,6 +10,12 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// 기본 토큰 유효 기간
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
 // Claims JWT 클레임 구조체
 type Claims struct {
 	UserID uint   `json:"user_id"`
@@ -15,49 +23,71 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT JWT 토큰 생성
+// RevocationChecker 토큰 폐기 여부 확인 인터페이스
+type RevocationChecker interface {
+	IsRevoked(claims *Claims) (bool, error)
+}
+
+var revocationChecker RevocationChecker
+
+// SetRevocationChecker ValidateJWT에서 사용할 폐기 목록 등록
+func SetRevocationChecker(checker RevocationChecker) {
+	revocationChecker = checker
+}
+
+// AccessTokenTTL 액세스 토큰 유효 기간 (JWT_ACCESS_TTL, 예: 15m)
+func AccessTokenTTL() time.Duration {
+	return durationFromEnv("JWT_ACCESS_TTL", DefaultAccessTokenTTL)
+}
+
+// RefreshTokenTTL 리프레시 토큰 유효 기간 (JWT_REFRESH_TTL, 예: 720h)
+func RefreshTokenTTL() time.Duration {
+	return durationFromEnv("JWT_REFRESH_TTL", DefaultRefreshTokenTTL)
+}
+
+// GenerateJWT JWT 액세스 토큰 생성
 func GenerateJWT(userID uint, email string) (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key-change-in-production" // fallback
-	}
+	tokenString, _, err := GenerateAccessToken(userID, email)
+	return tokenString, err
+}
 
-	// 토큰 만료 시간: 7일
-	expirationTime := time.Now().Add(7 * 24 * time.Hour)
+// GenerateAccessToken JWT 액세스 토큰 생성 (클레임 포함 반환)
+func GenerateAccessToken(userID uint, email string) (string, *Claims, error) {
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate token id: %w", err)
+	}
 
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "ongi-back",
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	tokenString, err := token.SignedString([]byte(jwtSecret()))
 	if err != nil {
-		return "", fmt.Errorf("failed to sign token: %w", err)
+		return "", nil, fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	return tokenString, nil
+	return tokenString, claims, nil
 }
 
-// ValidateJWT JWT 토큰 검증
+// ValidateJWT JWT 토큰 검증 (서명, 만료, 폐기 여부)
 func ValidateJWT(tokenString string) (*Claims, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key-change-in-production" // fallback
-	}
-
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(jwtSecret), nil
+		return []byte(jwtSecret()), nil
 	})
 
 	if err != nil {
@@ -68,5 +98,45 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 		return nil, fmt.Errorf("invalid token")
 	}
 
+	if revocationChecker != nil {
+		revoked, err := revocationChecker.IsRevoked(claims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("token has been revoked")
+		}
+	}
+
 	return claims, nil
 }
+
+// GenerateRandomToken 랜덤 토큰 생성 (hex 인코딩)
+func GenerateRandomToken(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func jwtSecret() string {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "your-secret-key-change-in-production" // fallback
+	}
+	return secret
+}
+
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return defaultValue
+	}
+	return duration
+}
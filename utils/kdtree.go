@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"container/heap"
+	"sort"
+)
+
+const kdDimensions = 5
+
+// KDTree - 5차원 k-d 트리 (최근접 이웃 검색)
+// 동시성 보호는 호출하는 쪽에서 한다. 삭제는 표시만 하고, 삭제된 노드가 살아있는 노드보다 많아지면 균형 트리로 다시 만든다.
+type KDTree struct {
+	root    *kdNode
+	nodes   map[string]*kdNode
+	removed int
+}
+
+type kdNode struct {
+	id          string
+	point       [kdDimensions]float64
+	axis        int
+	left, right *kdNode
+	deleted     bool
+}
+
+// Neighbor - 최근접 이웃 검색 결과 (DistanceSq는 유클리드 거리의 제곱)
+type Neighbor struct {
+	ID         string
+	DistanceSq float64
+}
+
+// NewKDTree - 빈 k-d 트리 생성
+func NewKDTree() *KDTree {
+	return &KDTree{nodes: make(map[string]*kdNode)}
+}
+
+// Len - 살아있는 점의 개수
+func (t *KDTree) Len() int {
+	return len(t.nodes)
+}
+
+// Insert - 점 추가 (같은 ID가 있으면 교체)
+func (t *KDTree) Insert(id string, v *Vector5D) {
+	t.Remove(id)
+
+	node := &kdNode{id: id, point: toPoint(v)}
+	t.nodes[id] = node
+
+	if t.root == nil {
+		t.root = node
+		return
+	}
+
+	current := t.root
+	for {
+		if node.point[current.axis] < current.point[current.axis] {
+			if current.left == nil {
+				node.axis = (current.axis + 1) % kdDimensions
+				current.left = node
+				return
+			}
+			current = current.left
+		} else {
+			if current.right == nil {
+				node.axis = (current.axis + 1) % kdDimensions
+				current.right = node
+				return
+			}
+			current = current.right
+		}
+	}
+}
+
+// Remove - 점 삭제 (없으면 false)
+func (t *KDTree) Remove(id string) bool {
+	node, ok := t.nodes[id]
+	if !ok {
+		return false
+	}
+
+	node.deleted = true
+	delete(t.nodes, id)
+	t.removed++
+
+	if t.removed > len(t.nodes) {
+		t.Rebuild()
+	}
+	return true
+}
+
+// Rebuild - 삭제된 노드를 제거하고 중앙값 기준으로 균형 트리 재구성
+func (t *KDTree) Rebuild() {
+	nodes := make([]*kdNode, 0, len(t.nodes))
+	for _, node := range t.nodes {
+		nodes = append(nodes, node)
+	}
+	// 같은 입력이면 같은 트리가 되도록 ID로 정렬 후 분할
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+
+	t.root = buildKDTree(nodes, 0)
+	t.removed = 0
+}
+
+func buildKDTree(nodes []*kdNode, depth int) *kdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	axis := depth % kdDimensions
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].point[axis] < nodes[j].point[axis] })
+
+	// 같은 값은 오른쪽으로 보내므로 중앙값과 같은 값 중 가장 왼쪽을 루트로 사용
+	median := len(nodes) / 2
+	for median > 0 && nodes[median-1].point[axis] == nodes[median].point[axis] {
+		median--
+	}
+
+	root := nodes[median]
+	root.axis = axis
+	root.left = buildKDTree(nodes[:median], depth+1)
+	root.right = buildKDTree(nodes[median+1:], depth+1)
+	return root
+}
+
+// Nearest - query와 가장 가까운 점 k개 (가까운 순)
+// accept가 nil이 아니면 accept(id)가 true인 점만 결과에 포함한다.
+func (t *KDTree) Nearest(query *Vector5D, k int, accept func(id string) bool) []Neighbor {
+	if k <= 0 || t.root == nil {
+		return nil
+	}
+
+	q := toPoint(query)
+	best := &neighborHeap{}
+	t.search(t.root, q, k, accept, best)
+
+	result := make([]Neighbor, best.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(best).(Neighbor)
+	}
+	return result
+}
+
+func (t *KDTree) search(node *kdNode, q [kdDimensions]float64, k int, accept func(id string) bool, best *neighborHeap) {
+	if node == nil {
+		return
+	}
+
+	if !node.deleted && (accept == nil || accept(node.id)) {
+		d := distanceSq(node.point, q)
+		if best.Len() < k {
+			heap.Push(best, Neighbor{ID: node.id, DistanceSq: d})
+		} else if d < (*best)[0].DistanceSq {
+			(*best)[0] = Neighbor{ID: node.id, DistanceSq: d}
+			heap.Fix(best, 0)
+		}
+	}
+
+	diff := q[node.axis] - node.point[node.axis]
+	near, far := node.left, node.right
+	if diff >= 0 {
+		near, far = node.right, node.left
+	}
+
+	t.search(near, q, k, accept, best)
+	// 분할 평면까지의 거리가 현재 k번째보다 가까울 때만 반대쪽 탐색
+	if best.Len() < k || diff*diff < (*best)[0].DistanceSq {
+		t.search(far, q, k, accept, best)
+	}
+}
+
+func toPoint(v *Vector5D) [kdDimensions]float64 {
+	return [kdDimensions]float64{v.Sociality, v.Activity, v.Intimacy, v.Immersion, v.Flexibility}
+}
+
+func distanceSq(a, b [kdDimensions]float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// neighborHeap - 거리 기준 최대 힙 (k번째로 가까운 이웃이 맨 위)
+type neighborHeap []Neighbor
+
+func (h neighborHeap) Len() int            { return len(h) }
+func (h neighborHeap) Less(i, j int) bool  { return h[i].DistanceSq > h[j].DistanceSq }
+func (h neighborHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(Neighbor)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
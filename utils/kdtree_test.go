@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomVector(rng *rand.Rand) *Vector5D {
+	return &Vector5D{
+		Sociality:   float64(rng.Intn(101)),
+		Activity:    float64(rng.Intn(101)),
+		Intimacy:    float64(rng.Intn(101)),
+		Immersion:   float64(rng.Intn(101)),
+		Flexibility: float64(rng.Intn(101)),
+	}
+}
+
+// bruteForceNearest - 모든 점을 비교해 가까운 k개의 거리 제곱 (가까운 순)
+func bruteForceNearest(points map[string]*Vector5D, query *Vector5D, k int, accept func(id string) bool) []float64 {
+	var dists []float64
+	for id, v := range points {
+		if accept == nil || accept(id) {
+			dists = append(dists, distanceSq(toPoint(v), toPoint(query)))
+		}
+	}
+	sort.Float64s(dists)
+	if len(dists) > k {
+		dists = dists[:k]
+	}
+	return dists
+}
+
+func checkNearest(t *testing.T, tree *KDTree, points map[string]*Vector5D, rng *rand.Rand, accept func(id string) bool) {
+	t.Helper()
+	for q := 0; q < 50; q++ {
+		query := randomVector(rng)
+		for _, k := range []int{1, 5, 20} {
+			got := tree.Nearest(query, k, accept)
+			want := bruteForceNearest(points, query, k, accept)
+			if len(got) != len(want) {
+				t.Fatalf("Nearest(k=%d) returned %d neighbors, want %d", k, len(got), len(want))
+			}
+			for i, n := range got {
+				// 거리가 같은 점은 순서가 다를 수 있으므로 거리로 비교
+				if n.DistanceSq != want[i] {
+					t.Fatalf("Nearest(k=%d)[%d].DistanceSq = %v, want %v", k, i, n.DistanceSq, want[i])
+				}
+				if _, ok := points[n.ID]; !ok {
+					t.Fatalf("Nearest returned removed point %q", n.ID)
+				}
+				if accept != nil && !accept(n.ID) {
+					t.Fatalf("Nearest returned rejected point %q", n.ID)
+				}
+			}
+		}
+	}
+}
+
+func TestKDTreeNearestMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := NewKDTree()
+	points := map[string]*Vector5D{}
+	for i := 0; i < 300; i++ {
+		id := fmt.Sprintf("p%d", i)
+		points[id] = randomVector(rng)
+		tree.Insert(id, points[id])
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		checkNearest(t, tree, points, rng, nil)
+	})
+
+	t.Run("accept", func(t *testing.T) {
+		even := func(id string) bool { return len(id)%2 == 0 }
+		checkNearest(t, tree, points, rng, even)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		for i := 0; i < 50; i++ {
+			id := fmt.Sprintf("p%d", i)
+			points[id] = randomVector(rng)
+			tree.Insert(id, points[id])
+		}
+		if tree.Len() != len(points) {
+			t.Fatalf("Len() = %d, want %d", tree.Len(), len(points))
+		}
+		checkNearest(t, tree, points, rng, nil)
+	})
+
+	t.Run("lazy remove", func(t *testing.T) {
+		// 살아있는 노드보다 적게 삭제해 재구성 없이 표시만 된 상태
+		for i := 50; i < 100; i++ {
+			id := fmt.Sprintf("p%d", i)
+			if !tree.Remove(id) {
+				t.Fatalf("Remove(%q) = false", id)
+			}
+			delete(points, id)
+		}
+		if tree.removed == 0 {
+			t.Fatal("expected removed nodes to remain until rebuild")
+		}
+		if tree.Remove("p50") {
+			t.Error("Remove of a removed point returned true")
+		}
+		checkNearest(t, tree, points, rng, nil)
+	})
+
+	t.Run("rebuild on remove", func(t *testing.T) {
+		for i := 100; i < 250; i++ {
+			id := fmt.Sprintf("p%d", i)
+			tree.Remove(id)
+			delete(points, id)
+		}
+		if tree.removed > tree.Len() {
+			t.Fatalf("removed = %d exceeds live nodes %d", tree.removed, tree.Len())
+		}
+		checkNearest(t, tree, points, rng, nil)
+	})
+
+	t.Run("rebuild", func(t *testing.T) {
+		tree.Rebuild()
+		if tree.removed != 0 {
+			t.Fatalf("removed = %d after Rebuild, want 0", tree.removed)
+		}
+		checkNearest(t, tree, points, rng, nil)
+	})
+}
+
+func TestKDTreeDuplicatePoints(t *testing.T) {
+	// 같은 좌표가 많으면 분할 값과 같은 점이 양쪽에 갈 수 있어 재구성 후에도 모두 찾아야 한다
+	tree := NewKDTree()
+	points := map[string]*Vector5D{}
+	for i := 0; i < 40; i++ {
+		id := fmt.Sprintf("d%02d", i)
+		points[id] = &Vector5D{Sociality: float64(i % 3 * 50), Activity: 50, Intimacy: 50, Immersion: 50, Flexibility: 50}
+		tree.Insert(id, points[id])
+	}
+	tree.Rebuild()
+
+	got := tree.Nearest(&Vector5D{Activity: 50, Intimacy: 50, Immersion: 50, Flexibility: 50}, 40, nil)
+	if len(got) != 40 {
+		t.Fatalf("Nearest returned %d neighbors, want 40", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].DistanceSq < got[i-1].DistanceSq {
+			t.Fatalf("neighbors not sorted at %d: %v < %v", i, got[i].DistanceSq, got[i-1].DistanceSq)
+		}
+	}
+}
+
+func TestKDTreeEmpty(t *testing.T) {
+	tree := NewKDTree()
+	if got := tree.Nearest(&Vector5D{}, 3, nil); got != nil {
+		t.Errorf("Nearest on empty tree = %v, want nil", got)
+	}
+	tree.Insert("a", &Vector5D{})
+	if got := tree.Nearest(&Vector5D{}, 0, nil); got != nil {
+		t.Errorf("Nearest with k=0 = %v, want nil", got)
+	}
+}
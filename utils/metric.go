@@ -0,0 +1,253 @@
+package utils
+
+import (
+	"errors"
+	"math"
+)
+
+// 유사도 척도 이름
+const (
+	MetricCosine            = "cosine"      // 방향만 비교 (크기 무시)
+	MetricEuclidean         = "euclidean"   // 점수 차이의 직선 거리
+	MetricMahalanobis       = "mahalanobis" // 모집단 공분산으로 보정한 거리
+	MetricWeightedEuclidean = "weighted"    // 차원별 가중치를 적용한 유클리드 거리
+)
+
+// MetricNames 지원하는 유사도 척도
+var MetricNames = []string{MetricCosine, MetricEuclidean, MetricMahalanobis, MetricWeightedEuclidean}
+
+// maxScore 각 차원 점수의 최대값
+const maxScore = 100.0
+
+// SimilarityMetric - 두 성향 벡터의 유사도(0-100) 척도
+type SimilarityMetric interface {
+	// Name 척도 이름 (MetricCosine 등)
+	Name() string
+	// Score 두 벡터의 유사도 (0-100, 소수점 첫째 자리)
+	Score(v1, v2 *Vector5D) float64
+	// Embed 유클리드 거리가 가까울수록 Score가 높아지는 공간으로 변환 (최근접 이웃 검색용)
+	// false면 비교할 수 없는 벡터로, 어떤 벡터와도 가장 낮은 유사도로 취급한다.
+	Embed(v *Vector5D) (*Vector5D, bool)
+}
+
+// CosineMetric - 코사인 유사도 ((cos+1)/2*100)
+// 크기를 무시하므로 (20,20,20,20,20)과 (100,100,100,100,100)이 100점이 된다.
+type CosineMetric struct{}
+
+func (CosineMetric) Name() string { return MetricCosine }
+
+func (CosineMetric) Score(v1, v2 *Vector5D) float64 { return SimilarityScore(v1, v2) }
+
+// Embed 단위 벡터 사이의 거리 순위는 코사인 유사도 순위와 같다
+func (CosineMetric) Embed(v *Vector5D) (*Vector5D, bool) {
+	if v.Magnitude() == 0 {
+		return nil, false
+	}
+	return v.Normalize(), true
+}
+
+// EuclideanMetric - 유클리드 거리 기반 유사도 ((1 - 거리/최대 거리)*100)
+type EuclideanMetric struct{}
+
+func (EuclideanMetric) Name() string { return MetricEuclidean }
+
+func (EuclideanMetric) Score(v1, v2 *Vector5D) float64 { return Similarity(v1, v2) }
+
+func (EuclideanMetric) Embed(v *Vector5D) (*Vector5D, bool) { return v, true }
+
+// WeightedEuclideanMetric - 차원별 가중치를 적용한 유클리드 거리 기반 유사도
+type WeightedEuclideanMetric struct {
+	Weights *Vector5D
+}
+
+// NewWeightedEuclideanMetric 가중치는 0 이상이고 하나 이상 양수여야 한다
+func NewWeightedEuclideanMetric(weights *Vector5D) (*WeightedEuclideanMetric, error) {
+	for _, w := range weights.ToSlice() {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, errors.New("metric weights must be non-negative numbers")
+		}
+	}
+	if weights.Magnitude() == 0 {
+		return nil, errors.New("at least one metric weight must be positive")
+	}
+	return &WeightedEuclideanMetric{Weights: weights}, nil
+}
+
+func (m *WeightedEuclideanMetric) Name() string { return MetricWeightedEuclidean }
+
+func (m *WeightedEuclideanMetric) Score(v1, v2 *Vector5D) float64 {
+	distance := EuclideanDistance(v1.ApplyWeights(m.Weights), v2.ApplyWeights(m.Weights))
+	maxDistance := m.Weights.Magnitude() * maxScore
+	return distanceToScore(distance, maxDistance)
+}
+
+func (m *WeightedEuclideanMetric) Embed(v *Vector5D) (*Vector5D, bool) {
+	return v.ApplyWeights(m.Weights), true
+}
+
+// MahalanobisMetric - 모집단 공분산으로 보정한 마할라노비스 거리 기반 유사도
+// 함께 움직이는 차원(예: 사교성과 활동성)의 차이를 중복으로 세지 않고, 분산이 작은 차원의 차이를 더 크게 본다.
+type MahalanobisMetric struct {
+	whitening   [5][5]float64 // W^T W = 공분산의 역행렬
+	maxDistance float64       // 점수 범위(0-100) 안에서 가능한 최대 거리
+}
+
+// mahalanobisRidge 공분산이 특이(차원 간 완전 상관)하지 않도록 대각에 더하는 값 (점수 단위의 분산)
+const mahalanobisRidge = 1.0
+
+// NewMahalanobisMetric 표본 벡터들의 공분산으로 척도 생성 (표본이 2개 이상 필요)
+func NewMahalanobisMetric(samples []*Vector5D) (*MahalanobisMetric, error) {
+	if len(samples) < 2 {
+		return nil, errors.New("mahalanobis metric needs at least two samples")
+	}
+
+	var mean [5]float64
+	for _, s := range samples {
+		for i, x := range s.ToSlice() {
+			mean[i] += x
+		}
+	}
+	for i := range mean {
+		mean[i] /= float64(len(samples))
+	}
+
+	var cov [5][5]float64
+	for _, s := range samples {
+		x := s.ToSlice()
+		for i := 0; i < 5; i++ {
+			for j := 0; j < 5; j++ {
+				cov[i][j] += (x[i] - mean[i]) * (x[j] - mean[j])
+			}
+		}
+	}
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			cov[i][j] /= float64(len(samples) - 1)
+		}
+		cov[i][i] += mahalanobisRidge
+	}
+
+	inverse, ok := invert5(cov)
+	if !ok {
+		return nil, errors.New("population covariance is not invertible")
+	}
+	lower, ok := cholesky5(inverse)
+	if !ok {
+		return nil, errors.New("population covariance is not positive definite")
+	}
+
+	// 공분산의 역행렬 = L L^T 이므로 x -> L^T x 변환 후 유클리드 거리 = 마할라노비스 거리
+	m := &MahalanobisMetric{}
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			m.whitening[i][j] = lower[j][i]
+		}
+	}
+
+	// 이차 형식은 볼록하므로 최대 거리는 차이 벡터가 각 차원 ±100인 꼭짓점에서 나온다
+	for signs := 0; signs < 1<<5; signs++ {
+		var corner Vector5D
+		diff := []*float64{&corner.Sociality, &corner.Activity, &corner.Intimacy, &corner.Immersion, &corner.Flexibility}
+		for i := range diff {
+			*diff[i] = maxScore
+			if signs&(1<<i) != 0 {
+				*diff[i] = -maxScore
+			}
+		}
+		m.maxDistance = math.Max(m.maxDistance, m.transform(&corner).Magnitude())
+	}
+
+	return m, nil
+}
+
+func (m *MahalanobisMetric) Name() string { return MetricMahalanobis }
+
+func (m *MahalanobisMetric) Score(v1, v2 *Vector5D) float64 {
+	return distanceToScore(EuclideanDistance(m.transform(v1), m.transform(v2)), m.maxDistance)
+}
+
+func (m *MahalanobisMetric) Embed(v *Vector5D) (*Vector5D, bool) { return m.transform(v), true }
+
+func (m *MahalanobisMetric) transform(v *Vector5D) *Vector5D {
+	x := v.ToSlice()
+	var y [5]float64
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			y[i] += m.whitening[i][j] * x[j]
+		}
+	}
+	return FromSlice(y[:])
+}
+
+// distanceToScore 거리를 0-100 유사도로 변환
+func distanceToScore(distance, maxDistance float64) float64 {
+	if maxDistance == 0 {
+		return maxScore
+	}
+	similarity := (1 - distance/maxDistance) * 100
+	if similarity < 0 {
+		similarity = 0
+	}
+	return math.Round(similarity*10) / 10
+}
+
+// invert5 가우스-조르단 소거법으로 5x5 역행렬 계산
+func invert5(a [5][5]float64) ([5][5]float64, bool) {
+	var inv [5][5]float64
+	for i := range inv {
+		inv[i][i] = 1
+	}
+
+	for col := 0; col < 5; col++ {
+		pivot := col
+		for row := col + 1; row < 5; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return inv, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		p := a[col][col]
+		for j := 0; j < 5; j++ {
+			a[col][j] /= p
+			inv[col][j] /= p
+		}
+		for row := 0; row < 5; row++ {
+			if row == col {
+				continue
+			}
+			f := a[row][col]
+			for j := 0; j < 5; j++ {
+				a[row][j] -= f * a[col][j]
+				inv[row][j] -= f * inv[col][j]
+			}
+		}
+	}
+	return inv, true
+}
+
+// cholesky5 대칭 양의 정부호 행렬을 L L^T로 분해
+func cholesky5(a [5][5]float64) ([5][5]float64, bool) {
+	var l [5][5]float64
+	for i := 0; i < 5; i++ {
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return l, false
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l, true
+}
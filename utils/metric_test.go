@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+const matrixTolerance = 1e-9
+
+func multiply5(a, b [5][5]float64) [5][5]float64 {
+	var c [5][5]float64
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			for k := 0; k < 5; k++ {
+				c[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return c
+}
+
+func transpose5(a [5][5]float64) [5][5]float64 {
+	var t [5][5]float64
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			t[i][j] = a[j][i]
+		}
+	}
+	return t
+}
+
+func assertMatrixEqual(t *testing.T, name string, got, want [5][5]float64) {
+	t.Helper()
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			if math.Abs(got[i][j]-want[i][j]) > matrixTolerance {
+				t.Fatalf("%s[%d][%d] = %v, want %v", name, i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+var identity5 = [5][5]float64{
+	{1, 0, 0, 0, 0},
+	{0, 1, 0, 0, 0},
+	{0, 0, 1, 0, 0},
+	{0, 0, 0, 1, 0},
+	{0, 0, 0, 0, 1},
+}
+
+// 대칭 양의 정부호 행렬 (대각 우세)
+var spd5 = [5][5]float64{
+	{10, 2, 1, 0, 3},
+	{2, 8, 1, 1, 0},
+	{1, 1, 6, 2, 1},
+	{0, 1, 2, 7, 2},
+	{3, 0, 1, 2, 9},
+}
+
+func TestInvert5(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [5][5]float64
+		ok   bool
+	}{
+		{"identity", identity5, true},
+		{"diagonal", [5][5]float64{{2}, {0, 4}, {0, 0, 0.5}, {0, 0, 0, 1}, {0, 0, 0, 0, 10}}, true},
+		{"symmetric positive definite", spd5, true},
+		// 첫 열이 0이라 행 교환이 필요한 경우
+		{"needs pivoting", [5][5]float64{{0, 1}, {1, 0}, {0, 0, 1}, {0, 0, 0, 1}, {0, 0, 0, 0, 1}}, true},
+		{"singular", [5][5]float64{{1, 2}, {2, 4}, {0, 0, 1}, {0, 0, 0, 1}, {0, 0, 0, 0, 1}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, ok := invert5(tt.a)
+			if ok != tt.ok {
+				t.Fatalf("invert5 ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			assertMatrixEqual(t, "A*inv(A)", multiply5(tt.a, inv), identity5)
+			assertMatrixEqual(t, "inv(A)*A", multiply5(inv, tt.a), identity5)
+		})
+	}
+}
+
+func TestCholesky5(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [5][5]float64
+		ok   bool
+	}{
+		{"identity", identity5, true},
+		{"symmetric positive definite", spd5, true},
+		{"indefinite", [5][5]float64{{1, 2}, {2, 1}, {0, 0, 1}, {0, 0, 0, 1}, {0, 0, 0, 0, 1}}, false},
+		{"positive semidefinite", [5][5]float64{{1, 1}, {1, 1}, {0, 0, 1}, {0, 0, 0, 1}, {0, 0, 0, 0, 1}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, ok := cholesky5(tt.a)
+			if ok != tt.ok {
+				t.Fatalf("cholesky5 ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			for i := 0; i < 5; i++ {
+				if l[i][i] <= 0 {
+					t.Fatalf("L[%d][%d] = %v, want positive", i, i, l[i][i])
+				}
+				for j := i + 1; j < 5; j++ {
+					if l[i][j] != 0 {
+						t.Fatalf("L[%d][%d] = %v, want 0 above the diagonal", i, j, l[i][j])
+					}
+				}
+			}
+			assertMatrixEqual(t, "L*L^T", multiply5(l, transpose5(l)), tt.a)
+		})
+	}
+}
+
+func TestMahalanobisIdentityCovarianceMatchesEuclidean(t *testing.T) {
+	// 표본이 모두 같으면 공분산은 0이고 리지(1)만 남아 단위 행렬이 된다
+	same := &Vector5D{Sociality: 50, Activity: 50, Intimacy: 50, Immersion: 50, Flexibility: 50}
+	metric, err := NewMahalanobisMetric([]*Vector5D{same, same})
+	if err != nil {
+		t.Fatalf("NewMahalanobisMetric: %v", err)
+	}
+
+	pairs := [][2]*Vector5D{
+		{{}, {}},
+		{{}, {Sociality: 100, Activity: 100, Intimacy: 100, Immersion: 100, Flexibility: 100}},
+		{{Sociality: 80, Activity: 20, Intimacy: 55, Immersion: 10, Flexibility: 90}, {Sociality: 30, Activity: 70, Intimacy: 50, Immersion: 40, Flexibility: 60}},
+		{{Sociality: 33.3, Activity: 66.6, Intimacy: 12.5, Immersion: 99, Flexibility: 1}, {Sociality: 35, Activity: 60, Intimacy: 20, Immersion: 90, Flexibility: 5}},
+	}
+	for _, p := range pairs {
+		if got, want := metric.Score(p[0], p[1]), (EuclideanMetric{}).Score(p[0], p[1]); got != want {
+			t.Errorf("Score(%v, %v) = %v, want euclidean %v", p[0], p[1], got, want)
+		}
+		embedded, ok := metric.Embed(p[0])
+		if !ok {
+			t.Fatalf("Embed(%v) not ok", p[0])
+		}
+		if d := EuclideanDistance(embedded, p[0]); d > matrixTolerance {
+			t.Errorf("Embed(%v) = %v, want the same vector", p[0], embedded)
+		}
+	}
+}
+
+func TestMahalanobisWhitening(t *testing.T) {
+	// 사교성만 분산 2를 가지는 표본: 리지를 더한 공분산은 diag(3, 1, 1, 1, 1)
+	samples := []*Vector5D{
+		{Sociality: 0, Activity: 50, Intimacy: 50, Immersion: 50, Flexibility: 50},
+		{Sociality: 2, Activity: 50, Intimacy: 50, Immersion: 50, Flexibility: 50},
+	}
+	metric, err := NewMahalanobisMetric(samples)
+	if err != nil {
+		t.Fatalf("NewMahalanobisMetric: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		v1, v2 *Vector5D
+		want   float64 // 마할라노비스 거리
+	}{
+		{"high variance axis shrinks", &Vector5D{}, &Vector5D{Sociality: 30}, 30 / math.Sqrt(3)},
+		{"other axes unchanged", &Vector5D{}, &Vector5D{Activity: 30}, 30},
+		{"mixed", &Vector5D{Sociality: 10, Activity: 10}, &Vector5D{Sociality: 40, Activity: 50}, math.Sqrt(30*30/3.0 + 40*40)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e1, _ := metric.Embed(tt.v1)
+			e2, _ := metric.Embed(tt.v2)
+			if got := EuclideanDistance(e1, e2); math.Abs(got-tt.want) > matrixTolerance {
+				t.Errorf("whitened distance = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 최대 거리는 모든 차원이 반대 끝인 경우
+	wantMax := math.Sqrt(100*100/3.0 + 4*100*100)
+	if math.Abs(metric.maxDistance-wantMax) > matrixTolerance {
+		t.Errorf("maxDistance = %v, want %v", metric.maxDistance, wantMax)
+	}
+	if got := metric.Score(&Vector5D{}, &Vector5D{Sociality: 100, Activity: 100, Intimacy: 100, Immersion: 100, Flexibility: 100}); got != 0 {
+		t.Errorf("Score of opposite corners = %v, want 0", got)
+	}
+}
+
+func TestNewMahalanobisMetricNeedsTwoSamples(t *testing.T) {
+	if _, err := NewMahalanobisMetric([]*Vector5D{{}}); err == nil {
+		t.Error("expected error for a single sample")
+	}
+}
@@ -103,7 +103,7 @@ type SimilarityResult struct {
 	Similarity float64
 }
 
-func BatchSimilarity(target *Vector5D, vectors []*Vector5D, workers int) []SimilarityResult {
+func BatchSimilarity(target *Vector5D, vectors []*Vector5D, workers int, metric SimilarityMetric) []SimilarityResult {
 	if workers <= 0 {
 		workers = 4 // 기본 워커 수
 	}
@@ -135,7 +135,7 @@ func BatchSimilarity(target *Vector5D, vectors []*Vector5D, workers int) []Simil
 			for i := start; i < end && i < len(vectors); i++ {
 				results[i] = SimilarityResult{
 					Index:      i,
-					Similarity: SimilarityScore(target, vectors[i]),
+					Similarity: metric.Score(target, vectors[i]),
 				}
 			}
 		}(start, end)